@@ -5,33 +5,66 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
 
+	"avito/internal/authz"
 	"avito/internal/config"
+	"avito/internal/domain"
 	"avito/internal/handler"
+	"avito/internal/health"
+	"avito/internal/jwtauth"
+	"avito/internal/metrics"
+	"avito/internal/migrate"
+	"avito/internal/notifier"
+	"avito/internal/outbox"
+	"avito/internal/ratelimit"
 	"avito/internal/repository/postgres"
 	"avito/internal/service"
+	"avito/internal/tracing"
+	"avito/internal/webhook"
+	"avito/migrations"
 	"avito/pkg/logger"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	appLogger := logger.New(cfg.Logger.Level, cfg.Logger.Format)
+	appLogger := logger.New(cfg.Logger.Level, cfg.Logger.Format,
+		logger.WithContextExtractor(requestLogFields),
+		logger.WithContextExtractor(traceLogFields),
+		logger.WithContextExtractor(principalLogFields),
+	)
 	appLogger.Info("Starting application",
 		"app", cfg.App.Name,
 		"env", cfg.App.Env,
 		"port", cfg.Server.Port,
 	)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		ServiceName: cfg.App.Name,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to set up tracing", "error", err)
+	}
+
 	dbConfig := postgres.Config{
 		URL:            cfg.Database.URL,
 		MaxConnections: cfg.Database.MaxConnections,
@@ -46,77 +79,280 @@ func main() {
 	defer db.Close()
 	appLogger.Info("Successfully connected to database")
 
+	migrator, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		appLogger.Fatal("Failed to load migrations", "error", err)
+	}
+	if err := ensureSchema(context.Background(), migrator, cfg.App.AutoMigrate, appLogger); err != nil {
+		appLogger.Fatal("Database schema is not up to date", "error", err)
+	}
+
+	appMetrics := metrics.New(appLogger)
+	appMetrics.RegisterDBStats(db.DB, "pr_reviewer")
+
 	teamRepo := postgres.NewTeamRepository(db.DB)
 	userRepo := postgres.NewUserRepository(db.DB)
 	prRepo := postgres.NewPullRequestRepository(db.DB)
 	taskRepo := postgres.NewTaskRepository(db.DB)
+	decisionRepo := postgres.NewDecisionRepository(db.DB)
+	accountRepo := postgres.NewExternalAccountRepository(db.DB)
+	webhookRepo := postgres.NewWebhookRepository(db.DB)
+	eventRepo := postgres.NewEventRepository(db.DB)
+	notificationRepo := postgres.NewNotificationRepository(db.DB)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db.DB)
+	tokenRepo := postgres.NewAPITokenRepository(db.DB)
 	appLogger.Info("Repository layer initialized")
 
 	teamService := service.NewTeamService(db, teamRepo, userRepo)
-	prService := service.NewPRService(db, prRepo, userRepo, teamRepo)
-	userService := service.NewUserService(userRepo, prRepo, prService, teamRepo, taskRepo, appLogger)
-	statsService := service.NewStatsService(prRepo, userRepo, teamRepo, appLogger)
+	prOpts := []service.PROption{service.WithAssignmentObserver(appMetrics)}
+	if cfg.Assignment.Seed != 0 {
+		prOpts = append(prOpts, service.WithSeed(cfg.Assignment.Seed))
+		appLogger.Info("Reviewer selection uses fixed seed", "seed", cfg.Assignment.Seed)
+	}
+	if cfg.Assignment.Strategy == domain.StrategyFair {
+		prOpts = append(prOpts, service.WithFairness(cfg.Assignment.FairnessWindow))
+		appLogger.Info("Reviewer selection uses fair strategy", "window", cfg.Assignment.FairnessWindow)
+	}
+	prService := service.NewPRService(db, prRepo, userRepo, teamRepo, decisionRepo, prOpts...)
+	userService := service.NewUserService(db, userRepo, prRepo, prService, teamRepo, taskRepo, appLogger)
+	accountService := service.NewAccountService(accountRepo, userRepo)
+	webhookService := service.NewWebhookService(webhookRepo)
+	eventService := service.NewEventService(eventRepo, userRepo, teamRepo)
+	notificationService := service.NewNotificationService(notificationRepo, userRepo)
+	digestService := service.NewDigestService(prRepo, userRepo, domain.ReviewSLA{
+		Target:  cfg.Notify.ReviewSLA,
+		Warning: cfg.Notify.ReviewSLAWarning,
+	})
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.LockLease)
+	tokenService := service.NewTokenService(tokenRepo, userRepo)
+	statsService := service.NewStatsService(prRepo, userRepo, teamRepo, decisionRepo, cfg.Assignment.FairnessWindow, appLogger)
 
-	taskWorker := service.NewTaskWorker(taskRepo, userRepo, prService, appLogger)
+	taskHeartbeat := &health.Heartbeat{}
+	taskWorker := service.NewTaskWorker(db, taskRepo, userRepo, prService, appLogger,
+		service.WithTaskObserver(appMetrics),
+		service.WithHeartbeat(taskHeartbeat),
+		service.WithTaskTimeout(cfg.TaskWorker.TaskTimeout),
+	)
+	appMetrics.RegisterTaskQueue(taskRepo.CountPending)
+	webhookWorker := service.NewWebhookWorker(webhookRepo, webhook.NewSender(cfg.Webhooks.Timeout), service.WebhookWorkerConfig{
+		PollInterval: cfg.Webhooks.PollInterval,
+		BatchSize:    cfg.Webhooks.BatchSize,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		Timeout:      cfg.Webhooks.Timeout,
+		Backoff:      webhook.Backoff{Base: cfg.Webhooks.BackoffBase, Max: cfg.Webhooks.BackoffMax},
+	}, appLogger)
+	notificationWorker := service.NewNotificationWorker(notificationRepo, buildChannels(cfg.Notify), service.NotificationWorkerConfig{
+		PollInterval: cfg.Notify.PollInterval,
+		BatchSize:    cfg.Notify.BatchSize,
+		MaxAttempts:  cfg.Notify.MaxAttempts,
+		Timeout:      cfg.Notify.Timeout,
+		Backoff:      webhook.Backoff{Base: cfg.Notify.BackoffBase, Max: cfg.Notify.BackoffMax},
+	}, appLogger)
+	digestWorker := service.NewDigestWorker(db, notificationRepo, digestService, cfg.Notify.DigestPollInterval, appLogger)
+	idempotencyPurger := service.NewIdempotencyPurger(idempotencyRepo, cfg.Idempotency.PurgeInterval, appLogger)
+	broker := outbox.NewBroker(cfg.Outbox.StreamBuffer)
+	relay := outbox.NewRelay(db, buildSinks(cfg.Outbox, appLogger), outbox.Config{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+	}, appLogger, outbox.WithBroker(broker))
 	appLogger.Info("Service layer initialized")
 
-	h := handler.NewHandler(teamService, userService, prService, appLogger.Logger)
-	statsHandler := handler.NewStatsHandler(statsService, appLogger)
+	// Роли проверяются только для запросов через API: воркеры и вебхуки
+	// интеграций вызывают сервисы напрямую.
+	authorizer := authz.NewAuthorizer(userRepo, teamRepo, prRepo)
+	readiness := health.NewChecker(cfg.Health.CheckTimeout)
+	readiness.Add("database", health.DatabaseCheck(db, cfg.Health.DBMaxLatency))
+	readiness.Add("migrations", health.MigrationsCheck(migrator, migrator.Latest()))
+	readiness.Add("task_worker", health.HeartbeatCheck(taskHeartbeat, cfg.Health.WorkerStaleAfter))
+	healthHandler := handler.NewHealthHandler(readiness, appLogger)
+
+	h := handler.NewHandler(
+		authz.NewTeamService(teamService, authorizer),
+		authz.NewUserService(userService, authorizer),
+		authz.NewPRService(prService, authorizer),
+		appLogger,
+	)
+	statsHandler := handler.NewStatsHandler(authz.NewStatsService(statsService, authorizer), appLogger)
+	webhookHandler := handler.NewWebhookHandler(webhookService, appLogger)
+	notificationHandler := handler.NewNotificationHandler(
+		authz.NewNotificationService(notificationService, authorizer),
+		authz.NewDigestService(digestService, authorizer),
+		appLogger,
+	)
+	eventStreamHandler := handler.NewEventStreamHandler(authz.NewEventService(eventService, authorizer), broker, cfg.Outbox.StreamHeartbeat, cfg.Outbox.StreamPoll, appLogger)
+	tokenHandler := handler.NewTokenHandler(tokenService, appLogger)
+	idempotency := handler.NewIdempotencyMiddleware(idempotencyService, appLogger)
+	var authOpts []handler.AuthOption
+	if cfg.JWT.Enabled {
+		keys, err := jwtauth.NewKeySet(context.Background(), jwksSource(cfg.JWT), cfg.JWT.JWKSRefresh, cfg.JWT.JWKSTTL)
+		if err != nil {
+			appLogger.Fatal("Failed to load JWKS", "error", err)
+		}
+		verifier := jwtauth.NewVerifier(keys, jwtauth.Config{
+			Issuer:   cfg.JWT.Issuer,
+			Audience: cfg.JWT.Audience,
+			Leeway:   cfg.JWT.Leeway,
+		})
+		authOpts = append(authOpts, handler.WithJWT(service.NewJWTService(verifier, userRepo, service.JWTClaimMapping{
+			UserClaim:     cfg.JWT.UserClaim,
+			RoleClaim:     cfg.JWT.RoleClaim,
+			DefaultScopes: cfg.JWT.DefaultScopes,
+		})))
+		appLogger.Info("JWT authentication enabled", "issuer", cfg.JWT.Issuer, "audience", cfg.JWT.Audience)
+	}
+	auth := handler.NewAuthMiddleware(tokenService, cfg.Auth.Enabled, appLogger, authOpts...)
+	if !cfg.Auth.Enabled {
+		appLogger.Warn("API authentication disabled: AUTH_ENABLED=false")
+	}
+	routeLimits := make(map[string]ratelimit.Limit, len(cfg.RateLimit.Routes))
+	for route, limit := range cfg.RateLimit.Routes {
+		routeLimits[route] = ratelimit.Limit{RPS: limit.RPS, Burst: limit.Burst}
+	}
+	rateLimit := handler.NewRateLimitMiddleware(
+		ratelimit.New(),
+		ratelimit.Limit{RPS: cfg.RateLimit.Default.RPS, Burst: cfg.RateLimit.Default.Burst},
+		routeLimits,
+		cfg.RateLimit.Enabled,
+		appLogger,
+	)
+	ipRateLimit := handler.NewIPRateLimitMiddleware(
+		ratelimit.New(),
+		ratelimit.Limit{RPS: cfg.RateLimit.PerIP.RPS, Burst: cfg.RateLimit.PerIP.Burst},
+		cfg.RateLimit.Enabled,
+		appLogger,
+	)
+	integrationHandler := handler.NewIntegrationHandler(accountService, prService, cfg.Webhooks.GitHubSecret, cfg.Webhooks.GitLabToken, appLogger)
 	appLogger.Info("Handler layer initialized")
 
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(loggingMiddleware(appLogger))
+	r.Use(tracing.Middleware)
+	r.Use(loggingMiddleware(appLogger, appMetrics))
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(`{"status":"ok","service":"pr-reviewer"}`)); err != nil {
-			appLogger.Error("Failed to write health response", "error", err)
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
+
+		r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			if _, err := w.Write([]byte(`{"status":"ok","service":"pr-reviewer"}`)); err != nil {
+				appLogger.Error("Failed to write health response", "error", err)
+			}
+		})
+
+		r.Get("/livez", healthHandler.Live)
+		r.Get("/readyz", healthHandler.Ready)
+
+		if cfg.Metrics.Enabled {
+			r.Method(http.MethodGet, "/metrics", appMetrics.Handler())
 		}
-	})
 
-	r.Route("/team", func(r chi.Router) {
-		r.Post("/add", h.CreateTeam)
-		r.Get("/get", h.GetTeam)
-	})
+		// Вебхуки GitHub и GitLab проверяются подписью, а не API-токеном.
+		if cfg.Webhooks.GitHubSecret != "" {
+			r.With(rateLimit.Handler).Post("/integrations/github/webhook", integrationHandler.GitHubWebhook)
+		} else {
+			appLogger.Info("GitHub webhook disabled: GITHUB_WEBHOOK_SECRET is not set")
+		}
+		if cfg.Webhooks.GitLabToken != "" {
+			r.With(rateLimit.Handler).Post("/integrations/gitlab/webhook", integrationHandler.GitLabWebhook)
+		} else {
+			appLogger.Info("GitLab webhook disabled: GITLAB_WEBHOOK_TOKEN is not set")
+		}
 
-	r.Route("/users", func(r chi.Router) {
-		r.Post("/setIsActive", h.SetIsActive)
-		r.Get("/getReview", h.GetPRsByReviewer)
-		r.Post("/batchDeactivate", h.BatchDeactivate)
-		r.Get("/{user_id}", h.GetUser)
-	})
+		r.Group(func(r chi.Router) {
+			r.Use(ipRateLimit.Handler)
+			r.Use(auth.Authenticate)
+			r.Use(rateLimit.Handler)
+			r.Use(idempotency.Handler)
 
-	r.Route("/pullRequest", func(r chi.Router) {
-		r.Post("/create", h.CreatePR)
-		r.Post("/merge", h.MergePR)
-		r.Post("/reassign", h.ReassignReviewer)
-	})
+			teamAdmin := auth.RequireScope(domain.ScopeTeamAdmin)
+			prWrite := auth.RequireScope(domain.ScopePRWrite)
+
+			r.Route("/team", func(r chi.Router) {
+				r.With(teamAdmin).Post("/add", h.CreateTeam)
+				r.With(teamAdmin).Post("/addMembers", h.AddTeamMembers)
+				r.Get("/get", h.GetTeam)
+			})
+
+			r.Route("/users", func(r chi.Router) {
+				r.With(teamAdmin).Post("/setIsActive", h.SetIsActive)
+				r.With(auth.RequireScope(domain.ScopeAdmin)).Post("/setRole", h.SetRole)
+				r.Get("/getReview", h.GetPRsByReviewer)
+				r.With(teamAdmin).Post("/batchDeactivate", h.BatchDeactivate)
+				r.Get("/{user_id}", h.GetUser)
+				r.Group(func(r chi.Router) {
+					r.Use(auth.RequireScope(domain.ScopeNotifications))
+					r.Get("/{user_id}/notifications", notificationHandler.GetPreferences)
+					r.Post("/{user_id}/notifications", notificationHandler.UpdatePreferences)
+					r.Get("/{user_id}/digest", notificationHandler.GetDigest)
+				})
+			})
+
+			r.Route("/pullRequest", func(r chi.Router) {
+				r.With(prWrite).Post("/create", h.CreatePR)
+				r.With(prWrite).Post("/merge", h.MergePR)
+				r.With(prWrite).Post("/reassign", h.ReassignReviewer)
+				r.Get("/explain", h.ExplainAssignment)
+				r.Get("/list", h.ListPRs)
+				r.Get("/{pull_request_id}", h.GetPR)
+			})
 
-	r.Route("/stats", func(r chi.Router) {
-		r.Get("/team", statsHandler.GetTeamStats)
-		r.Get("/user", statsHandler.GetUserStats)
-		r.Get("/global", statsHandler.GetGlobalStats)
-		r.Get("/workload", statsHandler.GetWorkloadStats)
-		r.Get("/health", statsHandler.GetHealthStats)
+			r.Route("/stats", func(r chi.Router) {
+				r.Use(auth.RequireScope(domain.ScopeStatsRead))
+				r.Get("/team", statsHandler.GetTeamStats)
+				r.Get("/user", statsHandler.GetUserStats)
+				r.Get("/global", statsHandler.GetGlobalStats)
+				r.Get("/workload", statsHandler.GetWorkloadStats)
+				r.Get("/health", statsHandler.GetHealthStats)
+				r.Get("/pairings", statsHandler.GetPairingStats)
+			})
+
+			r.With(teamAdmin).Post("/integrations/accounts/link", integrationHandler.LinkAccount)
+			r.With(teamAdmin).Post("/integrations/accounts/unlink", integrationHandler.UnlinkAccount)
+
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Use(teamAdmin)
+				r.Post("/", webhookHandler.CreateSubscription)
+				r.Get("/", webhookHandler.ListSubscriptions)
+				r.Get("/{subscription_id}/deliveries", webhookHandler.ListDeliveries)
+			})
+
+			r.Route("/admin/tokens", func(r chi.Router) {
+				r.Use(auth.RequireScope(domain.ScopeAdmin))
+				r.Post("/", tokenHandler.CreateToken)
+				r.Get("/", tokenHandler.ListTokens)
+				r.Post("/{token_id}/revoke", tokenHandler.RevokeToken)
+				r.Get("/{token_id}/audit", tokenHandler.ListAudit)
+			})
+		})
 	})
 
+	// Поток событий живёт дольше таймаута обычных запросов.
+	r.With(ipRateLimit.Handler, auth.Authenticate, auth.RequireScope(domain.ScopeEventsRead), rateLimit.Handler).Get("/events/stream", eventStreamHandler.Stream)
+
 	srv := &http.Server{
 		Addr:         ":" + cfg.Server.Port,
 		Handler:      r,
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
+	srv.RegisterOnShutdown(broker.Close)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	go taskWorker.Run(ctx)
+	// Воркеры завершаются по ctx; db.Close в defer выполняется только после
+	// них, чтобы не закрыть пул посреди транзакции.
+	var workers sync.WaitGroup
+	workers.Go(func() { taskWorker.Run(ctx) })
+	workers.Go(func() { webhookWorker.Run(ctx) })
+	workers.Go(func() { relay.Run(ctx) })
+	workers.Go(func() { notificationWorker.Run(ctx) })
+	workers.Go(func() { digestWorker.Run(ctx) })
+	workers.Go(func() { idempotencyPurger.Run(ctx) })
 
 	go func() {
 		appLogger.Info("Starting HTTP server", "port", cfg.Server.Port)
@@ -127,6 +363,10 @@ func main() {
 
 	<-ctx.Done()
 
+	readiness.SetShuttingDown()
+	appLogger.Info("Draining before shutdown", "delay", cfg.Server.DrainDelay)
+	time.Sleep(cfg.Server.DrainDelay)
+
 	appLogger.Info("Shutting down server...")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
@@ -136,10 +376,65 @@ func main() {
 		appLogger.Error("Server forced to shutdown", "error", err)
 	}
 
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		appLogger.Error("Background workers did not stop before shutdown timeout")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		appLogger.Error("Failed to flush traces", "error", err)
+	}
+
 	appLogger.Info("Server stopped gracefully")
 }
 
-func loggingMiddleware(logger *logger.Logger) func(next http.Handler) http.Handler {
+func buildSinks(cfg config.OutboxConfig, appLogger *logger.Logger) []outbox.Sink {
+	sinks := make([]outbox.Sink, 0, len(cfg.Sinks))
+	for _, name := range cfg.Sinks {
+		switch name {
+		case outbox.SinkLog:
+			sinks = append(sinks, outbox.NewLogSink(appLogger))
+		case outbox.SinkWebhook:
+			sinks = append(sinks, outbox.NewWebhookSink())
+		case outbox.SinkFile:
+			sinks = append(sinks, outbox.NewFileSink(cfg.FilePath))
+		case outbox.SinkNotify:
+			sinks = append(sinks, outbox.NewNotificationSink())
+		}
+	}
+	return sinks
+}
+
+// buildChannels возвращает каналы уведомлений: чат доступен всегда, email —
+// только при заданном SMTP_ADDR.
+func buildChannels(cfg config.NotifyConfig) []notifier.Channel {
+	channels := []notifier.Channel{notifier.NewChatChannel(cfg.Timeout, cfg.ChatAllowedHosts)}
+	if cfg.SMTPAddr != "" {
+		channels = append(channels, notifier.NewSMTPChannel(notifier.SMTPConfig{
+			Addr:     cfg.SMTPAddr,
+			From:     cfg.SMTPFrom,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			Timeout:  cfg.Timeout,
+		}))
+	}
+	return channels
+}
+
+func jwksSource(cfg config.JWTConfig) jwtauth.Source {
+	if cfg.JWKSFile != "" {
+		return jwtauth.FileSource(cfg.JWKSFile)
+	}
+	return jwtauth.URLSource(cfg.JWKSURL, &http.Client{Timeout: 10 * time.Second})
+}
+
+func loggingMiddleware(logger *logger.Logger, m *metrics.Metrics) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
@@ -147,14 +442,62 @@ func loggingMiddleware(logger *logger.Logger) func(next http.Handler) http.Handl
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 			next.ServeHTTP(ww, r)
 
-			logger.Info("HTTP request",
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.ObserveHTTP(r.Method, routeLabel(r), status, time.Since(start))
+
+			logger.WithContext(r.Context()).Info("HTTP request",
 				"method", r.Method,
 				"path", r.URL.Path,
-				"status", ww.Status(),
+				"status", status,
 				"duration_ms", time.Since(start).Milliseconds(),
 				"remote_addr", r.RemoteAddr,
-				"request_id", middleware.GetReqID(r.Context()),
 			)
 		})
 	}
 }
+
+// routeLabel возвращает шаблон маршрута chi; запросы без маршрута собираются
+// под одной меткой, чтобы произвольные пути не создавали новые ряды.
+func routeLabel(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return "unmatched"
+}
+
+// requestLogFields, traceLogFields и principalLogFields дописывают к логам
+// request_id из chi, trace_id/span_id текущего span'а, токен и пользователя
+// principal'а.
+func requestLogFields(ctx context.Context) []any {
+	if reqID := middleware.GetReqID(ctx); reqID != "" {
+		return []any{"request_id", reqID}
+	}
+	return nil
+}
+
+func traceLogFields(ctx context.Context) []any {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return []any{"trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String()}
+	}
+	return nil
+}
+
+func principalLogFields(ctx context.Context) []any {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	var fields []any
+	if p.TokenID != 0 {
+		fields = append(fields, "token_id", p.TokenID)
+	}
+	if p.UserID != "" {
+		fields = append(fields, "principal_user_id", p.UserID)
+	}
+	return fields
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"avito/internal/config"
+	"avito/internal/migrate"
+	"avito/internal/repository/postgres"
+	"avito/migrations"
+	"avito/pkg/logger"
+)
+
+// runMigrate выполняет подкоманду migrate:
+//
+//	app migrate up
+//	app migrate down [-steps N]
+//	app migrate status
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		migrateUsage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		migrateFatalf("failed to load config: %v", err)
+	}
+	db, err := postgres.NewDB(postgres.Config{
+		URL:            cfg.Database.URL,
+		MaxConnections: 2,
+		MaxIdle:        1,
+		ConnLifetime:   time.Minute,
+	})
+	if err != nil {
+		migrateFatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db.DB, migrations.FS)
+	if err != nil {
+		migrateFatalf("%v", err)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		err = migrateUp(ctx, migrator)
+	case "down":
+		err = migrateDown(ctx, migrator, args[1:])
+	case "status":
+		err = migrateStatus(ctx, migrator)
+	default:
+		migrateUsage()
+		os.Exit(2)
+	}
+	if err != nil {
+		migrateFatalf("%v", err)
+	}
+}
+
+func migrateUp(ctx context.Context, migrator *migrate.Migrator) error {
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		fmt.Fprintf(os.Stderr, "Applied %06d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(os.Stderr, "No pending migrations")
+	}
+	return nil
+}
+
+func migrateDown(ctx context.Context, migrator *migrate.Migrator, args []string) error {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to revert")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *steps <= 0 {
+		return fmt.Errorf("-steps must be positive")
+	}
+
+	reverted, err := migrator.Down(ctx, *steps)
+	for _, m := range reverted {
+		fmt.Fprintf(os.Stderr, "Reverted %06d_%s\n", m.Version, m.Name)
+	}
+	return err
+}
+
+func migrateStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "VERSION\t%d\n", status.Version)
+	fmt.Fprintf(tw, "LATEST\t%d\n", status.Latest)
+	fmt.Fprintf(tw, "DIRTY\t%t\n", status.Dirty)
+	for _, m := range status.Pending {
+		fmt.Fprintf(tw, "PENDING\t%06d_%s\n", m.Version, m.Name)
+	}
+	return tw.Flush()
+}
+
+// ensureSchema не даёт запустить сервис на отставшей схеме. С autoApply
+// недостающие миграции применяются сразу.
+func ensureSchema(ctx context.Context, migrator *migrate.Migrator, autoApply bool, log *logger.Logger) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Dirty {
+		return fmt.Errorf("%w at version %d", migrate.ErrDirty, status.Version)
+	}
+	if status.Version > status.Latest {
+		log.Warn("Database schema is newer than the service", "version", status.Version, "latest", status.Latest)
+		return nil
+	}
+	if len(status.Pending) == 0 {
+		return nil
+	}
+	if !autoApply {
+		return fmt.Errorf("schema version %d is behind %d: run `migrate up` or set MIGRATIONS_AUTO_APPLY=true",
+			status.Version, status.Latest)
+	}
+
+	applied, err := migrator.Up(ctx)
+	for _, m := range applied {
+		log.Info("Migration applied", "version", m.Version, "name", m.Name)
+	}
+	return err
+}
+
+func migrateUsage() {
+	fmt.Fprintln(os.Stderr, "usage: app migrate up | down [-steps N] | status")
+}
+
+func migrateFatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "migrate: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,132 @@
+// Команда apitoken управляет API-токенами сервиса напрямую через БД. Ею
+// создаётся первый токен со scope admin, дальше токенами можно управлять
+// через /admin/tokens.
+//
+//	apitoken create -name ci -scopes pr:write,stats:read
+//	apitoken create -name alice -scopes team:admin -user u1
+//	apitoken list
+//	apitoken revoke -id 3
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"avito/internal/config"
+	"avito/internal/repository/postgres"
+	"avito/internal/service"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatalf("failed to load config: %v", err)
+	}
+	db, err := postgres.NewDB(postgres.Config{
+		URL:            cfg.Database.URL,
+		MaxConnections: 1,
+		MaxIdle:        1,
+		ConnLifetime:   time.Minute,
+	})
+	if err != nil {
+		fatalf("failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	tokenService := service.NewTokenService(postgres.NewAPITokenRepository(db.DB), postgres.NewUserRepository(db.DB))
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "create":
+		err = create(ctx, tokenService, os.Args[2:])
+	case "list":
+		err = list(ctx, tokenService)
+	case "revoke":
+		err = revoke(ctx, tokenService, os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatalf("%v", err)
+	}
+}
+
+func create(ctx context.Context, tokenService service.TokenService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "token name")
+	scopes := fs.String("scopes", "", "comma-separated scopes: admin, pr:write, team:admin, stats:read, notifications, events:read")
+	userID := fs.String("user", "", "user the token acts on behalf of (role and team checks)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var scopeList []string
+	for _, s := range strings.Split(*scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopeList = append(scopeList, s)
+		}
+	}
+
+	token, secret, err := tokenService.CreateToken(ctx, *name, scopeList, *userID)
+	if err != nil {
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Created token %d (%s) with scopes %s\n", token.ID, token.Name, strings.Join(token.Scopes, ","))
+	fmt.Println(secret)
+	return nil
+}
+
+func list(ctx context.Context, tokenService service.TokenService) error {
+	tokens, err := tokenService.ListTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tokens: %w", err)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tPREFIX\tSCOPES\tUSER\tCREATED\tREVOKED")
+	for _, t := range tokens {
+		revoked := "-"
+		if t.RevokedAt != nil {
+			revoked = t.RevokedAt.Format(time.RFC3339)
+		}
+		user := "-"
+		if t.UserID != "" {
+			user = t.UserID
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.ID, t.Name, t.Prefix, strings.Join(t.Scopes, ","), user, t.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	return tw.Flush()
+}
+
+func revoke(ctx context.Context, tokenService service.TokenService, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.Int64("id", 0, "token id")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := tokenService.RevokeToken(ctx, *id); err != nil {
+		return fmt.Errorf("failed to revoke token %d: %w", *id, err)
+	}
+	fmt.Fprintf(os.Stderr, "Revoked token %d\n", *id)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: apitoken create -name NAME -scopes SCOPE[,SCOPE...] [-user USER_ID] | list | revoke -id ID")
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "apitoken: "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,201 @@
+// Package authz проверяет роли пользователей поверх scope токенов. Обёртки
+// реализуют те же интерфейсы, что и сервисы, и ставятся между хендлерами и
+// сервисами; фоновые задачи и вебхуки интеграций работают с сервисами
+// напрямую.
+//
+// Если в контексте нет principal (аутентификация выключена), проверки
+// пропускаются.
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type UserRepo interface {
+	Get(ctx context.Context, userID string) (*domain.User, error)
+}
+
+type TeamRepo interface {
+	Get(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type PRRepo interface {
+	Get(ctx context.Context, prID string) (*domain.PullRequest, error)
+}
+
+// Authorizer содержит правила доступа; обёртки сервисов вызывают его перед
+// делегированием.
+type Authorizer struct {
+	userRepo UserRepo
+	teamRepo TeamRepo
+	prRepo   PRRepo
+}
+
+func NewAuthorizer(userRepo UserRepo, teamRepo TeamRepo, prRepo PRRepo) *Authorizer {
+	return &Authorizer{
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		prRepo:   prRepo,
+	}
+}
+
+// CanManageTeam разрешает управлять составом команды её лиду и администратору.
+func (a *Authorizer) CanManageTeam(ctx context.Context, teamID int) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() || p.LeadsTeam(teamID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanAddMembers разрешает пополнять команду её лиду и администратору.
+// Пользователя из другой команды может перевести только тот, кто вправе
+// менять его активность, иначе лид забирал бы людей из чужих команд.
+func (a *Authorizer) CanAddMembers(ctx context.Context, teamName string, userIDs []string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	team, err := a.teamRepo.Get(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to get team: %w", err)
+	}
+	if !p.LeadsTeam(team.ID) {
+		return domain.ErrForbidden
+	}
+	for _, userID := range userIDs {
+		user, err := a.userRepo.Get(ctx, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrUserNotFound) {
+				continue
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+		if user.TeamID != team.ID && !p.LeadsTeam(user.TeamID) {
+			return domain.ErrForbidden
+		}
+	}
+	return nil
+}
+
+// CanSetIsActive разрешает менять активность пользователя лиду его команды и
+// администратору.
+func (a *Authorizer) CanSetIsActive(ctx context.Context, userID string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	user, err := a.userRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if p.LeadsTeam(user.TeamID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanSetRole разрешает менять роли только администратору: иначе лид мог бы
+// выдать себе или другим права admin.
+func (a *Authorizer) CanSetRole(ctx context.Context) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanAccessUser открывает личные данные пользователя (настройки уведомлений,
+// дайджест) ему самому, лиду его команды и администратору.
+func (a *Authorizer) CanAccessUser(ctx context.Context, userID string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	if p.UserID != "" && p.UserID == userID {
+		return nil
+	}
+	if p.Role != domain.RoleLead {
+		return domain.ErrForbidden
+	}
+	user, err := a.userRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if p.LeadsTeam(user.TeamID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanReassign разрешает снять ревьюера с PR автору PR, лиду команды автора и
+// администратору.
+func (a *Authorizer) CanReassign(ctx context.Context, prID string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	pr, err := a.prRepo.Get(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("failed to get PR: %w", err)
+	}
+	if p.UserID != "" && p.UserID == pr.AuthorID {
+		return nil
+	}
+	if p.Role != domain.RoleLead {
+		return domain.ErrForbidden
+	}
+	author, err := a.userRepo.Get(ctx, pr.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to get author: %w", err)
+	}
+	if p.LeadsTeam(author.TeamID) {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanViewTeam открывает статистику и события команды её участникам и
+// администратору.
+func (a *Authorizer) CanViewTeam(ctx context.Context, teamName string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	team, err := a.teamRepo.Get(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to get team: %w", err)
+	}
+	if p.UserID != "" && p.TeamID == team.ID {
+		return nil
+	}
+	return domain.ErrForbidden
+}
+
+// CanStreamEvents разрешает подписку на события пользователя тем, кому
+// открыты его данные, а на события команды — её участникам. Поток без
+// фильтра содержит события всех команд и доступен только администратору.
+func (a *Authorizer) CanStreamEvents(ctx context.Context, userID, teamName string) error {
+	p := domain.PrincipalFromContext(ctx)
+	if p == nil || p.IsAdmin() {
+		return nil
+	}
+	if userID == "" && teamName == "" {
+		return domain.ErrForbidden
+	}
+	if userID != "" {
+		if err := a.CanAccessUser(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if teamName != "" {
+		if err := a.CanViewTeam(ctx, teamName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"avito/internal/domain"
+)
+
+type fakeUserRepo map[string]*domain.User
+
+func (f fakeUserRepo) Get(_ context.Context, userID string) (*domain.User, error) {
+	if u, ok := f[userID]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakeTeamRepo map[string]*domain.Team
+
+func (f fakeTeamRepo) Get(_ context.Context, teamName string) (*domain.Team, error) {
+	if t, ok := f[teamName]; ok {
+		return t, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakePRRepo map[string]*domain.PullRequest
+
+func (f fakePRRepo) Get(_ context.Context, prID string) (*domain.PullRequest, error) {
+	if pr, ok := f[prID]; ok {
+		return pr, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func newTestAuthorizer() *Authorizer {
+	users := fakeUserRepo{
+		"u1": {UserID: "u1", TeamID: 1, Role: domain.RoleLead},
+		"u2": {UserID: "u2", TeamID: 1, Role: domain.RoleMember},
+		"u3": {UserID: "u3", TeamID: 2, Role: domain.RoleLead},
+	}
+	teams := fakeTeamRepo{
+		"backend":  {ID: 1, Name: "backend"},
+		"frontend": {ID: 2, Name: "frontend"},
+	}
+	prs := fakePRRepo{
+		"pr-1": {PullRequestID: "pr-1", AuthorID: "u2"},
+	}
+	return NewAuthorizer(users, teams, prs)
+}
+
+func as(p *domain.Principal) context.Context {
+	if p == nil {
+		return context.Background()
+	}
+	return domain.WithPrincipal(context.Background(), p)
+}
+
+func TestAuthorizer(t *testing.T) {
+	a := newTestAuthorizer()
+
+	lead := &domain.Principal{UserID: "u1", TeamID: 1, Role: domain.RoleLead}
+	member := &domain.Principal{UserID: "u2", TeamID: 1, Role: domain.RoleMember}
+	otherLead := &domain.Principal{UserID: "u3", TeamID: 2, Role: domain.RoleLead}
+	admin := &domain.Principal{UserID: "u9", Role: domain.RoleAdmin}
+	adminToken := &domain.Principal{Scopes: []string{domain.ScopeAdmin}}
+	serviceToken := &domain.Principal{Scopes: []string{domain.ScopeTeamAdmin, domain.ScopePRWrite, domain.ScopeStatsRead}}
+
+	tests := []struct {
+		name  string
+		check func(ctx context.Context) error
+		allow []*domain.Principal
+		deny  []*domain.Principal
+	}{
+		{
+			name:  "set is active",
+			check: func(ctx context.Context) error { return a.CanSetIsActive(ctx, "u2") },
+			allow: []*domain.Principal{nil, lead, admin, adminToken},
+			deny:  []*domain.Principal{member, otherLead, serviceToken},
+		},
+		{
+			name:  "batch deactivate",
+			check: func(ctx context.Context) error { return a.CanManageTeam(ctx, 1) },
+			allow: []*domain.Principal{nil, lead, admin, adminToken},
+			deny:  []*domain.Principal{member, otherLead, serviceToken},
+		},
+		{
+			name:  "add new and own members",
+			check: func(ctx context.Context) error { return a.CanAddMembers(ctx, "backend", []string{"u2", "new"}) },
+			allow: []*domain.Principal{nil, lead, admin, adminToken},
+			deny:  []*domain.Principal{member, otherLead, serviceToken},
+		},
+		{
+			name:  "move member from another team",
+			check: func(ctx context.Context) error { return a.CanAddMembers(ctx, "backend", []string{"u3"}) },
+			allow: []*domain.Principal{nil, admin, adminToken},
+			deny:  []*domain.Principal{lead, member, otherLead},
+		},
+		{
+			name:  "set role",
+			check: func(ctx context.Context) error { return a.CanSetRole(ctx) },
+			allow: []*domain.Principal{nil, admin, adminToken},
+			deny:  []*domain.Principal{member, lead, otherLead, serviceToken},
+		},
+		{
+			name:  "user notifications",
+			check: func(ctx context.Context) error { return a.CanAccessUser(ctx, "u2") },
+			allow: []*domain.Principal{nil, member, lead, admin, adminToken},
+			deny:  []*domain.Principal{otherLead, serviceToken, {UserID: "u5", TeamID: 1}},
+		},
+		{
+			name:  "reassign",
+			check: func(ctx context.Context) error { return a.CanReassign(ctx, "pr-1") },
+			allow: []*domain.Principal{nil, member, lead, admin},
+			deny:  []*domain.Principal{otherLead, serviceToken, {UserID: "u5", TeamID: 1}},
+		},
+		{
+			name:  "team stats",
+			check: func(ctx context.Context) error { return a.CanViewTeam(ctx, "backend") },
+			allow: []*domain.Principal{nil, member, lead, adminToken},
+			deny:  []*domain.Principal{otherLead, serviceToken},
+		},
+		{
+			name:  "stream own events",
+			check: func(ctx context.Context) error { return a.CanStreamEvents(ctx, "u2", "") },
+			allow: []*domain.Principal{nil, member, lead, admin, adminToken},
+			deny:  []*domain.Principal{otherLead, serviceToken},
+		},
+		{
+			name:  "stream team events",
+			check: func(ctx context.Context) error { return a.CanStreamEvents(ctx, "", "backend") },
+			allow: []*domain.Principal{nil, member, lead, admin},
+			deny:  []*domain.Principal{otherLead, serviceToken},
+		},
+		{
+			name:  "stream all events",
+			check: func(ctx context.Context) error { return a.CanStreamEvents(ctx, "", "") },
+			allow: []*domain.Principal{nil, admin, adminToken},
+			deny:  []*domain.Principal{member, lead, serviceToken},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range tt.allow {
+				if err := tt.check(as(p)); err != nil {
+					t.Errorf("principal %+v: expected access, got %v", p, err)
+				}
+			}
+			for _, p := range tt.deny {
+				if err := tt.check(as(p)); !errors.Is(err, domain.ErrForbidden) {
+					t.Errorf("principal %+v: expected ErrForbidden, got %v", p, err)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthorizer_NotFound(t *testing.T) {
+	a := newTestAuthorizer()
+	ctx := as(&domain.Principal{UserID: "u2", TeamID: 1, Role: domain.RoleMember})
+
+	if err := a.CanSetIsActive(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CanSetIsActive: expected ErrNotFound, got %v", err)
+	}
+	if err := a.CanAccessUser(as(&domain.Principal{UserID: "u1", TeamID: 1, Role: domain.RoleLead}), "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CanAccessUser: expected ErrNotFound, got %v", err)
+	}
+	if err := a.CanReassign(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CanReassign: expected ErrNotFound, got %v", err)
+	}
+	if err := a.CanViewTeam(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("CanViewTeam: expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package authz
+
+import (
+	"context"
+
+	"avito/internal/domain"
+	"avito/internal/service"
+)
+
+type userService struct {
+	service.UserService
+	authz *Authorizer
+}
+
+// NewUserService проверяет права на деактивацию пользователей и команд.
+func NewUserService(inner service.UserService, authz *Authorizer) service.UserService {
+	return &userService{UserService: inner, authz: authz}
+}
+
+func (s *userService) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.authz.CanSetIsActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.UserService.SetIsActive(ctx, userID, isActive)
+}
+
+func (s *userService) SetRole(ctx context.Context, userID string, role domain.Role) (*domain.User, error) {
+	if err := s.authz.CanSetRole(ctx); err != nil {
+		return nil, err
+	}
+	return s.UserService.SetRole(ctx, userID, role)
+}
+
+func (s *userService) ScheduleBatchDeactivate(ctx context.Context, teamID int) error {
+	if err := s.authz.CanManageTeam(ctx, teamID); err != nil {
+		return err
+	}
+	return s.UserService.ScheduleBatchDeactivate(ctx, teamID)
+}
+
+type teamService struct {
+	service.TeamService
+	authz *Authorizer
+}
+
+// NewTeamService проверяет права на пополнение команды.
+func NewTeamService(inner service.TeamService, authz *Authorizer) service.TeamService {
+	return &teamService{TeamService: inner, authz: authz}
+}
+
+func (s *teamService) AddMembers(ctx context.Context, teamName string, members []*domain.TeamMember) (*domain.Team, error) {
+	if teamName == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		userIDs = append(userIDs, m.UserID)
+	}
+	if err := s.authz.CanAddMembers(ctx, teamName, userIDs); err != nil {
+		return nil, err
+	}
+	return s.TeamService.AddMembers(ctx, teamName, members)
+}
+
+type prService struct {
+	service.PRService
+	authz *Authorizer
+}
+
+// NewPRService проверяет права на переназначение ревьюеров.
+func NewPRService(inner service.PRService, authz *Authorizer) service.PRService {
+	return &prService{PRService: inner, authz: authz}
+}
+
+func (s *prService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
+	if prID == "" || oldReviewerID == "" {
+		return nil, "", domain.ErrInvalidInput
+	}
+	if err := s.authz.CanReassign(ctx, prID); err != nil {
+		return nil, "", err
+	}
+	return s.PRService.ReassignReviewer(ctx, prID, oldReviewerID)
+}
+
+type statsService struct {
+	service.StatsProvider
+	authz *Authorizer
+}
+
+// NewStatsService открывает статистику команды только её участникам.
+func NewStatsService(inner service.StatsProvider, authz *Authorizer) service.StatsProvider {
+	return &statsService{StatsProvider: inner, authz: authz}
+}
+
+func (s *statsService) GetPairingMatrix(ctx context.Context, teamName string) (*service.PairingMatrix, error) {
+	if teamName == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.authz.CanViewTeam(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return s.StatsProvider.GetPairingMatrix(ctx, teamName)
+}
+
+type notificationService struct {
+	service.NotificationService
+	authz *Authorizer
+}
+
+// NewNotificationService открывает настройки уведомлений владельцу, лиду его
+// команды и администратору.
+func NewNotificationService(inner service.NotificationService, authz *Authorizer) service.NotificationService {
+	return &notificationService{NotificationService: inner, authz: authz}
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.authz.CanAccessUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.NotificationService.GetPreferences(ctx, userID)
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	if prefs == nil || prefs.UserID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.authz.CanAccessUser(ctx, prefs.UserID); err != nil {
+		return nil, err
+	}
+	return s.NotificationService.UpdatePreferences(ctx, prefs)
+}
+
+type digestService struct {
+	service.DigestService
+	authz *Authorizer
+}
+
+// NewDigestService отдаёт дайджест тем же, кому открыты настройки уведомлений.
+func NewDigestService(inner service.DigestService, authz *Authorizer) service.DigestService {
+	return &digestService{DigestService: inner, authz: authz}
+}
+
+func (s *digestService) BuildDigest(ctx context.Context, userID string) (*domain.Digest, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := s.authz.CanAccessUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.DigestService.BuildDigest(ctx, userID)
+}
+
+type eventService struct {
+	service.EventService
+	authz *Authorizer
+}
+
+// NewEventService ограничивает подписку на поток событий своими данными и
+// своей командой.
+func NewEventService(inner service.EventService, authz *Authorizer) service.EventService {
+	return &eventService{EventService: inner, authz: authz}
+}
+
+func (s *eventService) StreamFilter(ctx context.Context, userID, teamName string) (*domain.EventFilter, error) {
+	if err := s.authz.CanStreamEvents(ctx, userID, teamName); err != nil {
+		return nil, err
+	}
+	return s.EventService.StreamFilter(ctx, userID, teamName)
+}
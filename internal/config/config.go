@@ -4,14 +4,27 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	Logger   LoggerConfig
-	App      AppConfig
+	Database    DatabaseConfig
+	Server      ServerConfig
+	Logger      LoggerConfig
+	App         AppConfig
+	Assignment  AssignmentConfig
+	Webhooks    WebhooksConfig
+	Outbox      OutboxConfig
+	Notify      NotifyConfig
+	Idempotency IdempotencyConfig
+	Auth        AuthConfig
+	JWT         JWTConfig
+	RateLimit   RateLimitConfig
+	Metrics     MetricsConfig
+	Tracing     TracingConfig
+	Health      HealthConfig
+	TaskWorker  TaskWorkerConfig
 }
 
 type DatabaseConfig struct {
@@ -26,6 +39,9 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	// DrainDelay — сколько /readyz отвечает 503 перед остановкой сервера,
+	// чтобы балансировщик успел вывести экземпляр из ротации.
+	DrainDelay time.Duration
 }
 
 type LoggerConfig struct {
@@ -34,12 +50,142 @@ type LoggerConfig struct {
 }
 
 type AppConfig struct {
-	Env            string
-	Name           string
-	MigrationsPath string
+	Env  string
+	Name string
+	// AutoMigrate применяет встроенные миграции при старте, если схема
+	// отстаёт. Иначе сервис с устаревшей схемой не запускается.
+	AutoMigrate bool
 }
 
+type AssignmentConfig struct {
+	Seed           int64
+	Strategy       string
+	FairnessWindow int
+}
+
+type WebhooksConfig struct {
+	GitHubSecret string
+	GitLabToken  string
+
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	Timeout      time.Duration
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+type OutboxConfig struct {
+	Sinks        []string
+	FilePath     string
+	PollInterval time.Duration
+	BatchSize    int
+
+	StreamBuffer    int
+	StreamHeartbeat time.Duration
+	// StreamPoll — как часто поток перечитывает журнал: события, которые
+	// опубликовал релей другой реплики, в локальный broker не попадают.
+	StreamPoll time.Duration
+}
+
+type NotifyConfig struct {
+	SMTPAddr     string
+	SMTPFrom     string
+	SMTPUsername string
+	SMTPPassword string
+	// ChatAllowedHosts ограничивает хосты вебхуков чата; пустой список — любой
+	// публичный хост.
+	ChatAllowedHosts []string
+
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	Timeout      time.Duration
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+
+	DigestPollInterval time.Duration
+	ReviewSLA          time.Duration
+	ReviewSLAWarning   time.Duration
+}
+
+type IdempotencyConfig struct {
+	TTL           time.Duration
+	PurgeInterval time.Duration
+	// LockLease — сколько незавершённый запрос держит ключ. Больше таймаута
+	// запроса, чтобы ключ не перехватили, пока запрос ещё выполняется.
+	LockLease time.Duration
+}
+
+type AuthConfig struct {
+	Enabled bool
+}
+
+type JWTConfig struct {
+	Enabled bool
+	// Ключи берутся из файла или по URL — задаётся ровно один источник.
+	JWKSFile    string
+	JWKSURL     string
+	JWKSRefresh time.Duration
+	JWKSTTL     time.Duration
+	Issuer      string
+	Audience    string
+	Leeway      time.Duration
+
+	UserClaim     string
+	RoleClaim     string
+	DefaultScopes []string
+}
+
+type MetricsConfig struct {
+	Enabled bool
+}
+
+type TaskWorkerConfig struct {
+	// TaskTimeout ограничивает одну задачу деактивации или добора ревьюеров.
+	TaskTimeout time.Duration
+}
+
+type HealthConfig struct {
+	CheckTimeout     time.Duration
+	DBMaxLatency     time.Duration
+	WorkerStaleAfter time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter — none, otlp или stdout. Адрес OTLP-коллектора задаётся
+	// стандартной переменной OTEL_EXPORTER_OTLP_ENDPOINT.
+	Exporter    string
+	SampleRatio float64
+}
+
+// RateLimit — скорость пополнения бакета (запросов в секунду) и его ёмкость.
+type RateLimit struct {
+	RPS   float64
+	Burst int
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	// Default действует на все маршруты без собственного лимита; бакет
+	// общий для них в пределах клиента.
+	Default RateLimit
+	// Routes — лимиты по ключу "METHOD /pattern", у каждого маршрута свой бакет.
+	Routes map[string]RateLimit
+	// PerIP действует до аутентификации на все запросы с одного IP.
+	PerIP RateLimit
+}
+
+// defaultRateLimitRoutes ограничивает создание PR: именно его заваливают
+// зациклившиеся CI-джобы.
+var defaultRateLimitRoutes = []string{"POST /pullRequest/create=5:10"}
+
 func Load() (*Config, error) {
+	rateLimitRoutes, err := parseRateLimitRoutes(getEnvAsList("RATE_LIMIT_ROUTES", defaultRateLimitRoutes))
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		Database: DatabaseConfig{
 			URL:            getEnv("DATABASE_URL", ""),
@@ -52,15 +198,107 @@ func Load() (*Config, error) {
 			ReadTimeout:     getEnvAsDuration("SERVER_READ_TIMEOUT", 5*time.Second),
 			WriteTimeout:    getEnvAsDuration("SERVER_WRITE_TIMEOUT", 10*time.Second),
 			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			DrainDelay:      getEnvAsDuration("SERVER_DRAIN_DELAY", 5*time.Second),
 		},
 		Logger: LoggerConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
 		App: AppConfig{
-			Env:            getEnv("APP_ENV", "development"),
-			Name:           getEnv("APP_NAME", "pr-reviewer-service"),
-			MigrationsPath: getEnv("MIGRATIONS_PATH", "./migrations"),
+			Env:         getEnv("APP_ENV", "development"),
+			Name:        getEnv("APP_NAME", "pr-reviewer-service"),
+			AutoMigrate: getEnvAsBool("MIGRATIONS_AUTO_APPLY", false),
+		},
+		Assignment: AssignmentConfig{
+			Seed:           getEnvAsInt64("ASSIGNMENT_SEED", 0),
+			Strategy:       getEnv("ASSIGNMENT_STRATEGY", "random"),
+			FairnessWindow: getEnvAsInt("ASSIGNMENT_FAIRNESS_WINDOW", 20),
+		},
+		Webhooks: WebhooksConfig{
+			GitHubSecret: getEnv("GITHUB_WEBHOOK_SECRET", ""),
+			GitLabToken:  getEnv("GITLAB_WEBHOOK_TOKEN", ""),
+			PollInterval: getEnvAsDuration("WEBHOOK_POLL_INTERVAL", time.Second),
+			BatchSize:    getEnvAsInt("WEBHOOK_BATCH_SIZE", 100),
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+			BackoffBase:  getEnvAsDuration("WEBHOOK_BACKOFF_BASE", 5*time.Second),
+			BackoffMax:   getEnvAsDuration("WEBHOOK_BACKOFF_MAX", time.Hour),
+		},
+		Outbox: OutboxConfig{
+			Sinks:        getEnvAsList("OUTBOX_SINKS", []string{"webhook", "notify"}),
+			FilePath:     getEnv("OUTBOX_FILE_PATH", ""),
+			PollInterval: getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			BatchSize:    getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+
+			StreamBuffer:    getEnvAsInt("EVENTS_STREAM_BUFFER", 256),
+			StreamHeartbeat: getEnvAsDuration("EVENTS_STREAM_HEARTBEAT", 15*time.Second),
+			StreamPoll:      getEnvAsDuration("EVENTS_STREAM_POLL_INTERVAL", 2*time.Second),
+		},
+		Notify: NotifyConfig{
+			SMTPAddr:         getEnv("SMTP_ADDR", ""),
+			SMTPFrom:         getEnv("SMTP_FROM", ""),
+			SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+			ChatAllowedHosts: getEnvAsList("NOTIFY_CHAT_ALLOWED_HOSTS", nil),
+			PollInterval:     getEnvAsDuration("NOTIFY_POLL_INTERVAL", 5*time.Second),
+			BatchSize:        getEnvAsInt("NOTIFY_BATCH_SIZE", 100),
+			MaxAttempts:      getEnvAsInt("NOTIFY_MAX_ATTEMPTS", 5),
+			Timeout:          getEnvAsDuration("NOTIFY_TIMEOUT", 10*time.Second),
+			BackoffBase:      getEnvAsDuration("NOTIFY_BACKOFF_BASE", 30*time.Second),
+			BackoffMax:       getEnvAsDuration("NOTIFY_BACKOFF_MAX", time.Hour),
+
+			DigestPollInterval: getEnvAsDuration("DIGEST_POLL_INTERVAL", time.Minute),
+			ReviewSLA:          getEnvAsDuration("REVIEW_SLA", 48*time.Hour),
+			ReviewSLAWarning:   getEnvAsDuration("REVIEW_SLA_WARNING", 24*time.Hour),
+		},
+		Idempotency: IdempotencyConfig{
+			TTL:           getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+			PurgeInterval: getEnvAsDuration("IDEMPOTENCY_PURGE_INTERVAL", 10*time.Minute),
+			LockLease:     getEnvAsDuration("IDEMPOTENCY_LOCK_LEASE", 2*time.Minute),
+		},
+		Auth: AuthConfig{
+			Enabled: getEnvAsBool("AUTH_ENABLED", true),
+		},
+		JWT: JWTConfig{
+			Enabled:     getEnvAsBool("JWT_ENABLED", false),
+			JWKSFile:    getEnv("JWT_JWKS_FILE", ""),
+			JWKSURL:     getEnv("JWT_JWKS_URL", ""),
+			JWKSRefresh: getEnvAsDuration("JWT_JWKS_REFRESH", 5*time.Minute),
+			JWKSTTL:     getEnvAsDuration("JWT_JWKS_TTL", time.Hour),
+			Issuer:      getEnv("JWT_ISSUER", ""),
+			Audience:    getEnv("JWT_AUDIENCE", ""),
+			Leeway:      getEnvAsDuration("JWT_LEEWAY", 30*time.Second),
+
+			UserClaim:     getEnv("JWT_USER_CLAIM", "sub"),
+			RoleClaim:     getEnv("JWT_ROLE_CLAIM", "role"),
+			DefaultScopes: getEnvAsList("JWT_DEFAULT_SCOPES", nil),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			Default: RateLimit{
+				RPS:   getEnvAsFloat("RATE_LIMIT_RPS", 20),
+				Burst: getEnvAsInt("RATE_LIMIT_BURST", 40),
+			},
+			Routes: rateLimitRoutes,
+			PerIP: RateLimit{
+				RPS:   getEnvAsFloat("RATE_LIMIT_IP_RPS", 50),
+				Burst: getEnvAsInt("RATE_LIMIT_IP_BURST", 100),
+			},
+		},
+		Metrics: MetricsConfig{
+			Enabled: getEnvAsBool("METRICS_ENABLED", true),
+		},
+		Health: HealthConfig{
+			CheckTimeout:     getEnvAsDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+			DBMaxLatency:     getEnvAsDuration("HEALTH_DB_MAX_LATENCY", 500*time.Millisecond),
+			WorkerStaleAfter: getEnvAsDuration("HEALTH_WORKER_STALE_AFTER", 3*time.Minute),
+		},
+		TaskWorker: TaskWorkerConfig{
+			TaskTimeout: getEnvAsDuration("TASK_TIMEOUT", 2*time.Minute),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnv("TRACING_EXPORTER", "none"),
+			SampleRatio: getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
 		},
 	}
 
@@ -98,6 +336,132 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid LOG_FORMAT: %s (must be json or text)", c.Logger.Format)
 	}
 
+	validStrategies := map[string]bool{
+		"random": true,
+		"fair":   true,
+	}
+	if !validStrategies[c.Assignment.Strategy] {
+		return fmt.Errorf("invalid ASSIGNMENT_STRATEGY: %s (must be random or fair)", c.Assignment.Strategy)
+	}
+
+	if c.Assignment.FairnessWindow <= 0 {
+		return fmt.Errorf("ASSIGNMENT_FAIRNESS_WINDOW must be positive")
+	}
+
+	if c.Webhooks.PollInterval <= 0 || c.Webhooks.Timeout <= 0 || c.Webhooks.BackoffBase <= 0 {
+		return fmt.Errorf("WEBHOOK_POLL_INTERVAL, WEBHOOK_TIMEOUT and WEBHOOK_BACKOFF_BASE must be positive")
+	}
+
+	if c.Webhooks.BackoffMax < c.Webhooks.BackoffBase {
+		return fmt.Errorf("WEBHOOK_BACKOFF_MAX must not be less than WEBHOOK_BACKOFF_BASE")
+	}
+
+	if c.Webhooks.BatchSize <= 0 || c.Webhooks.MaxAttempts <= 0 {
+		return fmt.Errorf("WEBHOOK_BATCH_SIZE and WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+
+	validSinks := map[string]bool{
+		"log":     true,
+		"webhook": true,
+		"file":    true,
+		"notify":  true,
+	}
+	for _, sink := range c.Outbox.Sinks {
+		if !validSinks[sink] {
+			return fmt.Errorf("invalid OUTBOX_SINKS entry: %s (must be log, webhook, file or notify)", sink)
+		}
+		if sink == "file" && c.Outbox.FilePath == "" {
+			return fmt.Errorf("OUTBOX_FILE_PATH is required for the file sink")
+		}
+	}
+
+	if c.Outbox.PollInterval <= 0 || c.Outbox.BatchSize <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL and OUTBOX_BATCH_SIZE must be positive")
+	}
+
+	if c.Outbox.StreamBuffer <= 0 || c.Outbox.StreamHeartbeat <= 0 || c.Outbox.StreamPoll <= 0 {
+		return fmt.Errorf("EVENTS_STREAM_BUFFER, EVENTS_STREAM_HEARTBEAT and EVENTS_STREAM_POLL_INTERVAL must be positive")
+	}
+
+	if c.Notify.SMTPAddr != "" && c.Notify.SMTPFrom == "" {
+		return fmt.Errorf("SMTP_FROM is required when SMTP_ADDR is set")
+	}
+
+	if c.Notify.PollInterval <= 0 || c.Notify.Timeout <= 0 || c.Notify.BackoffBase <= 0 {
+		return fmt.Errorf("NOTIFY_POLL_INTERVAL, NOTIFY_TIMEOUT and NOTIFY_BACKOFF_BASE must be positive")
+	}
+
+	if c.Notify.BackoffMax < c.Notify.BackoffBase {
+		return fmt.Errorf("NOTIFY_BACKOFF_MAX must not be less than NOTIFY_BACKOFF_BASE")
+	}
+
+	if c.Notify.BatchSize <= 0 || c.Notify.MaxAttempts <= 0 {
+		return fmt.Errorf("NOTIFY_BATCH_SIZE and NOTIFY_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.Notify.DigestPollInterval <= 0 || c.Notify.ReviewSLAWarning <= 0 {
+		return fmt.Errorf("DIGEST_POLL_INTERVAL and REVIEW_SLA_WARNING must be positive")
+	}
+
+	if c.Notify.ReviewSLA < c.Notify.ReviewSLAWarning {
+		return fmt.Errorf("REVIEW_SLA must not be less than REVIEW_SLA_WARNING")
+	}
+
+	if c.Idempotency.TTL <= 0 || c.Idempotency.PurgeInterval <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL and IDEMPOTENCY_PURGE_INTERVAL must be positive")
+	}
+
+	if c.Idempotency.LockLease <= 0 || c.Idempotency.LockLease > c.Idempotency.TTL {
+		return fmt.Errorf("IDEMPOTENCY_LOCK_LEASE must be positive and not exceed IDEMPOTENCY_TTL")
+	}
+
+	if c.RateLimit.Default.RPS <= 0 || c.RateLimit.Default.Burst <= 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS and RATE_LIMIT_BURST must be positive")
+	}
+
+	if c.RateLimit.PerIP.RPS <= 0 || c.RateLimit.PerIP.Burst <= 0 {
+		return fmt.Errorf("RATE_LIMIT_IP_RPS and RATE_LIMIT_IP_BURST must be positive")
+	}
+
+	if c.Server.DrainDelay < 0 {
+		return fmt.Errorf("SERVER_DRAIN_DELAY must not be negative")
+	}
+
+	if c.Health.CheckTimeout <= 0 || c.Health.DBMaxLatency <= 0 || c.Health.WorkerStaleAfter <= 0 {
+		return fmt.Errorf("HEALTH_CHECK_TIMEOUT, HEALTH_DB_MAX_LATENCY and HEALTH_WORKER_STALE_AFTER must be positive")
+	}
+
+	// Воркер отмечается до и после каждой задачи, так что порог должен
+	// превышать её максимальную длительность.
+	if c.TaskWorker.TaskTimeout <= 0 || c.Health.WorkerStaleAfter <= c.TaskWorker.TaskTimeout {
+		return fmt.Errorf("TASK_TIMEOUT must be positive and less than HEALTH_WORKER_STALE_AFTER")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "otlp", "stdout":
+	default:
+		return fmt.Errorf("TRACING_EXPORTER must be one of none, otlp, stdout")
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	if c.JWT.Enabled {
+		if (c.JWT.JWKSFile == "") == (c.JWT.JWKSURL == "") {
+			return fmt.Errorf("exactly one of JWT_JWKS_FILE and JWT_JWKS_URL is required when JWT_ENABLED is set")
+		}
+		if c.JWT.JWKSRefresh <= 0 || c.JWT.Leeway < 0 {
+			return fmt.Errorf("JWT_JWKS_REFRESH must be positive and JWT_LEEWAY must not be negative")
+		}
+		if c.JWT.JWKSTTL < c.JWT.JWKSRefresh {
+			return fmt.Errorf("JWT_JWKS_TTL must not be less than JWT_JWKS_REFRESH")
+		}
+		if c.JWT.UserClaim == "" {
+			return fmt.Errorf("JWT_USER_CLAIM must not be empty")
+		}
+	}
+
 	return nil
 }
 
@@ -122,6 +486,63 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvAsList(key string, defaultValue []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []string
+	for _, v := range strings.Split(valueStr, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -136,6 +557,30 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return value
 }
 
+// parseRateLimitRoutes разбирает записи вида "POST /pullRequest/create=5:10"
+// (метод и путь, скорость в запросах в секунду и ёмкость бакета).
+func parseRateLimitRoutes(entries []string) (map[string]RateLimit, error) {
+	routes := make(map[string]RateLimit, len(entries))
+	for _, entry := range entries {
+		route, spec, ok := strings.Cut(entry, "=")
+		method, path, okRoute := strings.Cut(strings.TrimSpace(route), " ")
+		rps, burst, okSpec := strings.Cut(spec, ":")
+		if !ok || !okRoute || !okSpec || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q (must be METHOD /path=RPS:BURST)", entry)
+		}
+		limit := RateLimit{}
+		var err error
+		if limit.RPS, err = strconv.ParseFloat(rps, 64); err != nil || limit.RPS <= 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES rate in %q", entry)
+		}
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES burst in %q", entry)
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = limit
+	}
+	return routes, nil
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.App.Env == "development"
 }
@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	ScopePRWrite   = "pr:write"
+	ScopeTeamAdmin = "team:admin"
+	ScopeStatsRead = "stats:read"
+	// ScopeNotifications открывает настройки уведомлений и дайджест; поверх
+	// него проверяется, чьи это настройки.
+	ScopeNotifications = "notifications"
+	// ScopeEventsRead открывает /events/stream; какие события видны,
+	// решается по роли владельца токена.
+	ScopeEventsRead = "events:read"
+	// ScopeAdmin разрешает управление токенами и включает все остальные scope.
+	ScopeAdmin = "admin"
+)
+
+// APITokenPrefix отличает токены сервиса от прочих секретов в логах и конфигах.
+const APITokenPrefix = "prt_"
+
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopePRWrite, ScopeTeamAdmin, ScopeStatsRead, ScopeNotifications, ScopeEventsRead, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIToken хранится только в виде SHA-256 хеша; сам токен показывается один
+// раз при создании.
+type APIToken struct {
+	ID     int64
+	Name   string
+	Prefix string
+	Hash   string
+	Scopes []string
+	// UserID — пользователь, от имени которого действует токен; по нему
+	// определяются роль и команда. Пустой для сервисных токенов.
+	UserID    string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+func (t *APIToken) Validate() error {
+	if t.Name == "" || len(t.Scopes) == 0 {
+		return ErrInvalidInput
+	}
+	for _, scope := range t.Scopes {
+		if !IsValidScope(scope) {
+			return ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// GenerateAPIToken создаёт случайный токен и возвращает его вместе с хешем
+// для хранения.
+func GenerateAPIToken() (plaintext, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	plaintext = APITokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return plaintext, HashAPIToken(plaintext), nil
+}
+
+func HashAPIToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Principal — тот, от чьего имени выполняется запрос.
+type Principal struct {
+	TokenID   int64
+	TokenName string
+	Scopes    []string
+	UserID    string
+	TeamID    int
+	Role      Role
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope) || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// IsAdmin считает администратором и пользователя с ролью admin, и токен со
+// scope admin.
+func (p *Principal) IsAdmin() bool {
+	return p.Role == RoleAdmin || slices.Contains(p.Scopes, ScopeAdmin)
+}
+
+// LeadsTeam сообщает, является ли principal лидом команды teamID.
+func (p *Principal) LeadsTeam(teamID int) bool {
+	return p.UserID != "" && p.Role == RoleLead && p.TeamID == teamID
+}
+
+// Subject однозначно идентифицирует principal, например для разделения
+// ключей идемпотентности между клиентами. Principal из JWT не имеет токена
+// в БД и идентифицируется пользователем.
+func (p *Principal) Subject() string {
+	if p.TokenID == 0 && p.UserID != "" {
+		return "user:" + p.UserID
+	}
+	return fmt.Sprintf("token:%d", p.TokenID)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext возвращает principal запроса или nil, если
+// аутентификация выключена.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// TokenAuditEntry фиксирует изменяющий запрос, выполненный с токеном.
+type TokenAuditEntry struct {
+	ID        int64
+	TokenID   int64
+	Method    string
+	Route     string
+	Status    int
+	RequestID string
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+const (
+	AuditActionAssigned = "assigned"
+
+	AuditSourceAutoFill = "auto_fill"
+)
+
+type AssignmentAudit struct {
+	ID            int64
+	PullRequestID string
+	UserID        string
+	Action        string
+	Source        string
+	TaskID        *int
+	CreatedAt     time.Time
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "time"
+
+const (
+	DecisionKindCreate   = "create"
+	DecisionKindReassign = "reassign"
+	DecisionKindFill     = "fill"
+)
+
+const (
+	StrategyRandom = "random"
+	// StrategyFair понижает шансы ревьюеров, недавно ревьюивших того же автора.
+	StrategyFair = "fair"
+)
+
+// Исходы подбора ревьюеров для метрик. NO_CANDIDATE совпадает с кодом ошибки
+// переназначения без свободных кандидатов.
+const (
+	AssignmentOutcomeAssigned    = "ASSIGNED"
+	AssignmentOutcomePartial     = "PARTIAL"
+	AssignmentOutcomeNoCandidate = "NO_CANDIDATE"
+)
+
+func IsValidStrategy(strategy string) bool {
+	return strategy == StrategyRandom || strategy == StrategyFair
+}
+
+type ExclusionReason string
+
+const (
+	ExclusionAuthor          ExclusionReason = "author"
+	ExclusionAlreadyAssigned ExclusionReason = "already_assigned"
+	ExclusionInactive        ExclusionReason = "inactive"
+)
+
+type Exclusion struct {
+	UserID string          `json:"user_id"`
+	Reason ExclusionReason `json:"reason"`
+}
+
+// AssignmentDecision хранит входные данные выбора ревьюеров: по seed и
+// списку кандидатов выбор можно воспроизвести заново.
+type AssignmentDecision struct {
+	ID            int64
+	PullRequestID string
+	Kind          string
+	Strategy      string
+	Rule          ReviewerRule
+	Seed          int64
+	Slots         int
+	Candidates    []string
+	// CandidateLevels параллелен Candidates.
+	CandidateLevels []Seniority
+	// CandidatePenalties параллелен Candidates: сколько раз кандидат ревьюил
+	// автора в окне истории назначений.
+	CandidatePenalties []int
+	KeptLevels         []Seniority
+	Excluded           []Exclusion
+	Selected           []string
+	RuleViolated       bool
+	ReplacedUserID     string
+	CreatedAt          time.Time
+}
+
+// Outcome сообщает, удалось ли занять все слоты.
+func (d *AssignmentDecision) Outcome() string {
+	switch {
+	case len(d.Selected) == 0 && d.Slots > 0:
+		return AssignmentOutcomeNoCandidate
+	case len(d.Selected) < d.Slots:
+		return AssignmentOutcomePartial
+	default:
+		return AssignmentOutcomeAssigned
+	}
+}
+
+// Pool возвращает всех участников команды, рассмотренных при выборе.
+func (d *AssignmentDecision) Pool() []string {
+	pool := make([]string, 0, len(d.Candidates)+len(d.Excluded))
+	pool = append(pool, d.Candidates...)
+	for _, e := range d.Excluded {
+		pool = append(pool, e.UserID)
+	}
+	return pool
+}
+
+type AssignmentExplanation struct {
+	PullRequest *PullRequest
+	Decisions   []*AssignmentDecision
+}
+
+type PairingCount struct {
+	AuthorID   string
+	ReviewerID string
+	Count      int
+}
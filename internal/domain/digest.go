@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type SLAStatus string
+
+const (
+	SLAStatusOK       SLAStatus = "OK"
+	SLAStatusAtRisk   SLAStatus = "AT_RISK"
+	SLAStatusBreached SLAStatus = "BREACHED"
+)
+
+const EventDigestDaily = "digest.daily"
+
+// ReviewSLA — срок, за который ревьюер должен закрыть ревью. Warning — возраст
+// PR, после которого он считается под угрозой нарушения срока.
+type ReviewSLA struct {
+	Target  time.Duration
+	Warning time.Duration
+}
+
+func (s ReviewSLA) Status(age time.Duration) SLAStatus {
+	switch {
+	case age >= s.Target:
+		return SLAStatusBreached
+	case age >= s.Warning:
+		return SLAStatusAtRisk
+	default:
+		return SLAStatusOK
+	}
+}
+
+type DigestItem struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	CreatedAt       time.Time
+	Age             time.Duration
+	SLAStatus       SLAStatus
+	DueAt           time.Time
+}
+
+// Digest — сводка открытых ревью пользователя, старые PR первыми.
+type Digest struct {
+	UserID      string
+	GeneratedAt time.Time
+	Items       []*DigestItem
+	AtRisk      int
+	Breached    int
+}
+
+func NewDigest(userID string, prs []*PullRequestShort, sla ReviewSLA, now time.Time) *Digest {
+	d := &Digest{
+		UserID:      userID,
+		GeneratedAt: now,
+		Items:       make([]*DigestItem, 0, len(prs)),
+	}
+	for _, pr := range prs {
+		age := max(now.Sub(pr.CreatedAt), 0)
+		item := &DigestItem{
+			PullRequestID:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorID:        pr.AuthorID,
+			CreatedAt:       pr.CreatedAt,
+			Age:             age,
+			SLAStatus:       sla.Status(age),
+			DueAt:           pr.CreatedAt.Add(sla.Target),
+		}
+		switch item.SLAStatus {
+		case SLAStatusAtRisk:
+			d.AtRisk++
+		case SLAStatusBreached:
+			d.Breached++
+		}
+		d.Items = append(d.Items, item)
+	}
+	sort.SliceStable(d.Items, func(i, j int) bool {
+		return d.Items[i].CreatedAt.Before(d.Items[j].CreatedAt)
+	})
+	return d
+}
+
+// LastDigestSlot возвращает последний наступивший к now момент отправки
+// дайджеста по часовому поясу пользователя.
+func (p *NotificationPreferences) LastDigestSlot(now time.Time) time.Time {
+	local := now.In(p.location())
+	slot := nextClock(local, p.DigestAt)
+	if slot.After(local) {
+		slot = time.Date(slot.Year(), slot.Month(), slot.Day()-1, p.DigestAt/60, p.DigestAt%60, 0, 0, slot.Location())
+	}
+	return slot
+}
+
+// DigestDue сообщает, наступило ли время очередного ежедневного дайджеста.
+func (p *NotificationPreferences) DigestDue(now time.Time) bool {
+	return p.DailyDigest && len(p.Channels) > 0 && p.LastDigestAt.Before(p.LastDigestSlot(now))
+}
@@ -3,15 +3,33 @@ package domain
 import "errors"
 
 var (
-	ErrTeamExists     = errors.New("team already exists")
-	ErrPRExists       = errors.New("pull request already exists")
-	ErrPRMerged       = errors.New("pull request is already merged")
-	ErrNotAssigned    = errors.New("user is not assigned as reviewer")
-	ErrNoCandidate    = errors.New("no available candidate for assignment")
-	ErrNotFound       = errors.New("resource not found")
-	ErrInvalidInput   = errors.New("invalid input data")
-	ErrAuthorNotFound = errors.New("author not found")
-	ErrTeamNotFound   = errors.New("team not found")
-	ErrUserNotFound   = errors.New("user not found")
-	ErrPRNotFound     = errors.New("pull request not found")
+	ErrTeamExists       = errors.New("team already exists")
+	ErrPRExists         = errors.New("pull request already exists")
+	ErrPRMerged         = errors.New("pull request is already merged")
+	ErrNotAssigned      = errors.New("user is not assigned as reviewer")
+	ErrNoCandidate      = errors.New("no available candidate for assignment")
+	ErrNotFound         = errors.New("resource not found")
+	ErrInvalidInput     = errors.New("invalid input data")
+	ErrAuthorNotFound   = errors.New("author not found")
+	ErrTeamNotFound     = errors.New("team not found")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrPRNotFound       = errors.New("pull request not found")
+	ErrUnauthorized     = errors.New("unauthorized")
+	ErrAccountNotLinked = errors.New("external account is not linked to a user")
+	// ErrExternalIDTaken — id внешней системы уже привязан к другому логину.
+	ErrExternalIDTaken = errors.New("external account id is linked to another login")
+	// ErrPreconditionFailed — версия ресурса не совпала с переданной в If-Match.
+	ErrPreconditionFailed = errors.New("resource version does not match")
+	// ErrConcurrentModification — ресурс изменили между чтением и записью.
+	ErrConcurrentModification = errors.New("resource was modified concurrently")
+	// ErrIdempotencyKeyReused — ключ уже использован для запроса с другим телом.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used with a different request")
+	// ErrIdempotencyInProgress — запрос с тем же ключом ещё выполняется.
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+	// ErrIdempotencyLeaseLost — аренда ключа истекла, и его занял другой запрос.
+	ErrIdempotencyLeaseLost = errors.New("idempotency key is no longer held by this request")
+	// ErrInsufficientScope — у токена нет scope, нужного для маршрута.
+	ErrInsufficientScope = errors.New("token does not have the required scope")
+	// ErrForbidden — роль пользователя не позволяет выполнить действие.
+	ErrForbidden = errors.New("action is not allowed for this user")
 )
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+const (
+	EventPRCreated          = "pr.created"
+	EventReviewerAssigned   = "reviewer.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventPRMerged           = "pr.merged"
+	EventUserActivated      = "user.activated"
+	EventUserDeactivated    = "user.deactivated"
+	EventTeamCreated        = "team.created"
+)
+
+var eventTypes = map[string]bool{
+	EventPRCreated:          true,
+	EventReviewerAssigned:   true,
+	EventReviewerReassigned: true,
+	EventPRMerged:           true,
+	EventUserActivated:      true,
+	EventUserDeactivated:    true,
+	EventTeamCreated:        true,
+}
+
+func IsValidEventType(eventType string) bool {
+	return eventTypes[eventType]
+}
+
+// Event — запись outbox, сохраняемая в одной транзакции с изменением состояния.
+type Event struct {
+	ID int64
+	// Seq — позиция в журнале публикаций (порядок коммитов релея), 0 до
+	// публикации. По ней потоки возобновляются через Last-Event-ID.
+	Seq         int64
+	Type        string
+	AggregateID string
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+}
+
+func NewEvent(eventType, aggregateID string, payload any) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Payload:     data,
+	}, nil
+}
+
+type PREventPayload struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+}
+
+func NewPREventPayload(pr *PullRequest) PREventPayload {
+	reviewers := pr.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	return PREventPayload{
+		PullRequestID:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		AssignedReviewers: reviewers,
+	}
+}
+
+type ReviewerEventPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	OldReviewerID string `json:"old_reviewer_id,omitempty"`
+	Source        string `json:"source,omitempty"`
+}
+
+type UserEventPayload struct {
+	UserID string `json:"user_id"`
+	TeamID int    `json:"team_id"`
+}
+
+type TeamEventPayload struct {
+	TeamID       int      `json:"team_id"`
+	TeamName     string   `json:"team_name"`
+	ReviewerRule string   `json:"reviewer_rule"`
+	Members      []string `json:"members"`
+}
+
+func NewTeamEventPayload(team *Team) TeamEventPayload {
+	members := make([]string, 0, len(team.Members))
+	for _, m := range team.Members {
+		members = append(members, m.UserID)
+	}
+	return TeamEventPayload{
+		TeamID:       team.ID,
+		TeamName:     team.Name,
+		ReviewerRule: string(team.ReviewerRule.OrDefault()),
+		Members:      members,
+	}
+}
+
+type eventSubjects struct {
+	AuthorID          string   `json:"author_id"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	ReviewerID        string   `json:"reviewer_id"`
+	OldReviewerID     string   `json:"old_reviewer_id"`
+	UserID            string   `json:"user_id"`
+	TeamName          string   `json:"team_name"`
+	Members           []string `json:"members"`
+}
+
+func (e *Event) subjects() eventSubjects {
+	var s eventSubjects
+	_ = json.Unmarshal(e.Payload, &s)
+	return s
+}
+
+// UserIDs возвращает пользователей, которых касается событие: автора,
+// ревьюеров, деактивированного пользователя или участников команды.
+func (e *Event) UserIDs() []string {
+	s := e.subjects()
+	var ids []string
+	for _, id := range []string{s.AuthorID, s.ReviewerID, s.OldReviewerID, s.UserID} {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	ids = append(ids, s.AssignedReviewers...)
+	return append(ids, s.Members...)
+}
+
+// EventFilter отбирает события для подписчика потока. Пустой фильтр пропускает всё.
+type EventFilter struct {
+	UserID      string
+	TeamName    string
+	TeamMembers map[string]bool
+}
+
+func (f *EventFilter) Matches(e *Event) bool {
+	if f.UserID == "" && f.TeamName == "" {
+		return true
+	}
+	ids := e.UserIDs()
+	if f.UserID != "" && !slices.Contains(ids, f.UserID) {
+		return false
+	}
+	if f.TeamName != "" {
+		if e.subjects().TeamName == f.TeamName {
+			return true
+		}
+		for _, id := range ids {
+			if f.TeamMembers[id] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+func IsValidProvider(provider string) bool {
+	return provider == ProviderGitHub || provider == ProviderGitLab
+}
+
+// ExternalAccount связывает логин во внешней системе (GitHub, GitLab) с пользователем сервиса.
+type ExternalAccount struct {
+	Provider string
+	Login    string
+	// ExternalID — числовой id пользователя во внешней системе, 0 — не задан.
+	// По нему GitLab сообщает автора merge request'а.
+	ExternalID int64
+	UserID     string
+	CreatedAt  time.Time
+}
+
+func (a *ExternalAccount) Validate() error {
+	if !IsValidProvider(a.Provider) {
+		return ErrInvalidInput
+	}
+	if a.Login == "" || a.UserID == "" || a.ExternalID < 0 {
+		return ErrInvalidInput
+	}
+	return nil
+}
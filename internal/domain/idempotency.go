@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// MaxIdempotencyKeyLength ограничивает длину заголовка Idempotency-Key.
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyRecord — сохранённый результат запроса с заголовком
+// Idempotency-Key. Пока запрос выполняется, Completed равен false и ответа нет.
+type IdempotencyRecord struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	// LockedUntil — до этого момента незавершённый запрос держит ключ; после
+	// него ключ можно занять снова (процесс, выполнявший запрос, упал).
+	LockedUntil time.Time
+	// Token выдаётся при резервировании ключа; Complete и Release действуют
+	// только с ним.
+	Token string
+}
+
+// RequestFingerprint однозначно описывает запрос: повтор с тем же ключом
+// должен совпадать по методу, пути и телу.
+func RequestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+)
+
+const (
+	NotifyModeImmediate = "immediate"
+	NotifyModeDigest    = "digest"
+)
+
+const (
+	NotifyChannelEmail = "email"
+	NotifyChannelChat  = "chat"
+)
+
+const (
+	NotificationStatusPending = "pending"
+	NotificationStatusSent    = "sent"
+	NotificationStatusFailed  = "failed"
+)
+
+const DefaultDigestAt = 9 * 60
+
+// QuietHours — интервал в минутах от полуночи по часовому поясу пользователя.
+// Start > End означает интервал через полночь (например, 22:00–08:00).
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+func (q *QuietHours) Contains(minute int) bool {
+	if q.Start <= q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+type NotificationPreferences struct {
+	UserID         string
+	Channels       []string
+	Email          string
+	ChatWebhookURL string
+	Mode           string
+	QuietHours     *QuietHours
+	Timezone       string
+	DigestAt       int
+	DailyDigest    bool
+	LastDigestAt   time.Time
+	UpdatedAt      time.Time
+}
+
+func (p *NotificationPreferences) Validate() error {
+	if p.UserID == "" {
+		return ErrInvalidInput
+	}
+	if p.Mode != NotifyModeImmediate && p.Mode != NotifyModeDigest {
+		return ErrInvalidInput
+	}
+	for _, ch := range p.Channels {
+		switch ch {
+		case NotifyChannelEmail:
+			addr, err := mail.ParseAddress(p.Email)
+			if err != nil || addr.Address != p.Email {
+				return ErrInvalidInput
+			}
+		case NotifyChannelChat:
+			u, err := url.Parse(p.ChatWebhookURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return ErrInvalidInput
+			}
+		default:
+			return ErrInvalidInput
+		}
+	}
+	if q := p.QuietHours; q != nil && (!validMinute(q.Start) || !validMinute(q.End) || q.Start == q.End) {
+		return ErrInvalidInput
+	}
+	if !validMinute(p.DigestAt) {
+		return ErrInvalidInput
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+func (p *NotificationPreferences) location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextDelivery возвращает момент, не раньше которого можно отправить
+// уведомление, созданное в now: ближайшее время дайджеста в режиме digest,
+// конец тихих часов, если now попадает в них, иначе сразу.
+func (p *NotificationPreferences) NextDelivery(now time.Time) time.Time {
+	local := now.In(p.location())
+	if p.Mode == NotifyModeDigest {
+		return nextClock(local, p.DigestAt)
+	}
+	if p.QuietHours != nil && p.QuietHours.Contains(local.Hour()*60+local.Minute()) {
+		return nextClock(local, p.QuietHours.End)
+	}
+	return now
+}
+
+func nextClock(local time.Time, minute int) time.Time {
+	at := time.Date(local.Year(), local.Month(), local.Day(), minute/60, minute%60, 0, 0, local.Location())
+	if at.Before(local) {
+		at = time.Date(local.Year(), local.Month(), local.Day()+1, minute/60, minute%60, 0, 0, local.Location())
+	}
+	return at
+}
+
+func validMinute(m int) bool {
+	return m >= 0 && m < 24*60
+}
+
+// ParseClock разбирает время суток "HH:MM" в минуты от полуночи.
+func ParseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: %w", s, ErrInvalidInput)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+type Notification struct {
+	ID           int64
+	UserID       string
+	EventID      int64
+	EventType    string
+	Subject      string
+	Body         string
+	Status       string
+	DeliverAfter time.Time
+	Attempts     int
+	// SentChannels — каналы, в которые уведомление уже доставлено.
+	SentChannels []string
+	ErrorMessage sql.NullString
+	CreatedAt    time.Time
+	SentAt       sql.NullTime
+}
@@ -0,0 +1,72 @@
+package domain
+
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+func (s Seniority) IsValid() bool {
+	return s == SeniorityJunior || s == SeniorityMiddle || s == SenioritySenior
+}
+
+// OrDefault возвращает middle для незаданного уровня.
+func (s Seniority) OrDefault() Seniority {
+	if s == "" {
+		return SeniorityMiddle
+	}
+	return s
+}
+
+type ReviewerRule string
+
+const (
+	ReviewerRuleNone           ReviewerRule = "none"
+	ReviewerRuleSeniorRequired ReviewerRule = "at_least_one_senior"
+	ReviewerRuleNoTwoJuniors   ReviewerRule = "no_two_juniors"
+)
+
+func (r ReviewerRule) IsValid() bool {
+	switch r {
+	case ReviewerRuleNone, ReviewerRuleSeniorRequired, ReviewerRuleNoTwoJuniors:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r ReviewerRule) OrDefault() ReviewerRule {
+	if r == "" {
+		return ReviewerRuleNone
+	}
+	return r
+}
+
+// Satisfied проверяет, что набор уровней ревьюеров PR соответствует правилу.
+// Пустой набор правилу не противоречит.
+func (r ReviewerRule) Satisfied(levels []Seniority) bool {
+	if len(levels) == 0 {
+		return true
+	}
+	switch r {
+	case ReviewerRuleSeniorRequired:
+		for _, l := range levels {
+			if l == SenioritySenior {
+				return true
+			}
+		}
+		return false
+	case ReviewerRuleNoTwoJuniors:
+		juniors := 0
+		for _, l := range levels {
+			if l == SeniorityJunior {
+				juniors++
+			}
+		}
+		return juniors < 2
+	default:
+		return true
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ReviewerStatus string
+
+const (
+	ReviewerStatusAssigned ReviewerStatus = "ASSIGNED"
+	// ReviewerStatusInactive — ревьюер назначен, но деактивирован и ждёт замены.
+	ReviewerStatusInactive ReviewerStatus = "INACTIVE"
+)
+
+type ReviewerState struct {
+	UserID   string
+	Username string
+	IsActive bool
+	Status   ReviewerStatus
+}
+
+func NewReviewerState(u *User) *ReviewerState {
+	status := ReviewerStatusAssigned
+	if !u.IsActive {
+		status = ReviewerStatusInactive
+	}
+	return &ReviewerState{
+		UserID:   u.UserID,
+		Username: u.Username,
+		IsActive: u.IsActive,
+		Status:   status,
+	}
+}
+
+// PullRequestDetails — PR вместе с текущим состоянием его ревьюеров.
+type PullRequestDetails struct {
+	PR        *PullRequest
+	Reviewers []*ReviewerState
+}
+
+// ETag строится из версии PR и состояния ревьюеров: версия меняется при
+// изменении PR, а активность ревьюера хранится в users и на неё не влияет.
+// Для If-Match значима только версия, см. ParseETagVersion.
+func (d *PullRequestDetails) ETag() string {
+	h := sha256.New()
+	for _, r := range d.Reviewers {
+		fmt.Fprintf(h, "%s:%s:%t\n", r.UserID, r.Username, r.IsActive)
+	}
+	return fmt.Sprintf(`"%d-%s"`, d.PR.Version, hex.EncodeToString(h.Sum(nil))[:12])
+}
+
+// ParseETagVersion извлекает версию PR из значения ETag: принимаются как
+// полный тег "<version>-<hash>", так и просто "<version>", в том числе
+// в слабой форме W/"...".
+func ParseETagVersion(tag string) (int, error) {
+	tag = strings.TrimSpace(tag)
+	tag = strings.TrimPrefix(tag, "W/")
+	tag = strings.Trim(tag, `"`)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, ErrInvalidInput
+	}
+	return version, nil
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	DefaultPRPageSize = 50
+	MaxPRPageSize     = 200
+)
+
+const (
+	PRSortCreatedAt = "created_at"
+	PRSortMergedAt  = "merged_at"
+	PRSortName      = "name"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// PRFilter — параметры выборки списка PR. Пустые поля не ограничивают
+// выборку; интервалы дат полуоткрытые: [From, To).
+type PRFilter struct {
+	Status     PRStatus
+	AuthorID   string
+	ReviewerID string
+	TeamName   string
+
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+
+	Query string
+
+	Sort  string
+	Order string
+	Limit int
+
+	Cursor *PRCursor
+}
+
+// Normalize подставляет сортировку и размер страницы по умолчанию и проверяет
+// параметры, в том числе соответствие курсора сортировке.
+func (f *PRFilter) Normalize() error {
+	if f.Status != "" && !f.Status.IsValid() {
+		return ErrInvalidInput
+	}
+	if f.Sort == "" {
+		f.Sort = PRSortCreatedAt
+	}
+	if f.Sort != PRSortCreatedAt && f.Sort != PRSortMergedAt && f.Sort != PRSortName {
+		return ErrInvalidInput
+	}
+	if f.Order == "" {
+		f.Order = SortDesc
+	}
+	if f.Order != SortAsc && f.Order != SortDesc {
+		return ErrInvalidInput
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultPRPageSize
+	}
+	if f.Limit < 0 || f.Limit > MaxPRPageSize {
+		return ErrInvalidInput
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return ErrInvalidInput
+	}
+	if f.MergedFrom != nil && f.MergedTo != nil && !f.MergedFrom.Before(*f.MergedTo) {
+		return ErrInvalidInput
+	}
+	if f.Cursor != nil && (f.Cursor.Sort != f.Sort || f.Cursor.Order != f.Order) {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// PRCursor указывает на последний PR предыдущей страницы: значение поля
+// сортировки и id для разрешения равенств.
+type PRCursor struct {
+	Sort  string    `json:"s"`
+	Order string    `json:"o"`
+	Time  time.Time `json:"t,omitempty"`
+	Name  string    `json:"n,omitempty"`
+	ID    string    `json:"id"`
+}
+
+func NewPRCursor(f *PRFilter, last *PullRequest) *PRCursor {
+	c := &PRCursor{Sort: f.Sort, Order: f.Order, ID: last.PullRequestID}
+	switch f.Sort {
+	case PRSortName:
+		c.Name = last.PullRequestName
+	case PRSortMergedAt:
+		if last.MergedAt != nil {
+			c.Time = *last.MergedAt
+		}
+	default:
+		c.Time = last.CreatedAt
+	}
+	return c
+}
+
+func (c *PRCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePRCursor(s string) (*PRCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidInput
+	}
+	var c PRCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return nil, ErrInvalidInput
+	}
+	return &c, nil
+}
+
+// PRPage — страница списка PR. Total — число PR, подходящих под фильтр без
+// учёта курсора; NextCursor пуст на последней странице.
+type PRPage struct {
+	Items      []*PullRequest
+	Total      int
+	NextCursor string
+}
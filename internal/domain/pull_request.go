@@ -2,6 +2,8 @@ package domain
 
 import "time"
 
+const MaxReviewers = 2
+
 const (
 	PRStatusIDOpen   int16 = 1
 	PRStatusIDMerged int16 = 2
@@ -49,13 +51,19 @@ type PullRequest struct {
 	AssignedReviewers []string   `json:"assigned_reviewers,omitempty"`
 	CreatedAt         time.Time  `json:"createdAt,omitempty" db:"created_at"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
+	// Version увеличивается при каждом изменении PR и набора его ревьюеров.
+	Version int `json:"version" db:"version"`
+	// PairingWarning заполняется сервисом, если правило подбора ревьюеров
+	// команды не удалось соблюсти. В БД не хранится.
+	PairingWarning string `json:"pairing_warning,omitempty" db:"-"`
 }
 
 type PullRequestShort struct {
-	PullRequestID   string   `json:"pull_request_id"`
-	PullRequestName string   `json:"pull_request_name"`
-	AuthorID        string   `json:"author_id"`
-	Status          PRStatus `json:"status"`
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	Status          PRStatus  `json:"status"`
+	CreatedAt       time.Time `json:"createdAt"`
 }
 
 func (pr *PullRequest) Validate() error {
@@ -71,7 +79,7 @@ func (pr *PullRequest) Validate() error {
 	if !pr.Status.IsValid() {
 		return ErrInvalidInput
 	}
-	if len(pr.AssignedReviewers) > 2 {
+	if len(pr.AssignedReviewers) > MaxReviewers {
 		return ErrInvalidInput
 	}
 	return nil
@@ -98,6 +106,14 @@ func (pr *PullRequest) HasReviewer(userID string) bool {
 	return false
 }
 
+func (pr *PullRequest) MissingReviewers() int {
+	missing := MaxReviewers - len(pr.AssignedReviewers)
+	if missing < 0 {
+		return 0
+	}
+	return missing
+}
+
 func (pr *PullRequest) IsAuthor(userID string) bool {
 	return pr.AuthorID == userID
 }
@@ -108,6 +124,7 @@ func (pr *PullRequest) ToShort() *PullRequestShort {
 		PullRequestName: pr.PullRequestName,
 		AuthorID:        pr.AuthorID,
 		Status:          pr.Status,
+		CreatedAt:       pr.CreatedAt,
 	}
 }
 
@@ -0,0 +1,23 @@
+package domain
+
+type Role string
+
+const (
+	RoleMember Role = "member"
+	// RoleLead управляет своей командой: активирует и деактивирует участников
+	// и переназначает ревьюеров на PR команды.
+	RoleLead  Role = "lead"
+	RoleAdmin Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	return r == RoleMember || r == RoleLead || r == RoleAdmin
+}
+
+// OrDefault возвращает member для незаданной роли.
+func (r Role) OrDefault() Role {
+	if r == "" {
+		return RoleMember
+	}
+	return r
+}
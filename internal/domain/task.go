@@ -12,6 +12,18 @@ const (
 	TaskStatusFailed     = "failed"
 )
 
+// Виды фоновых задач TaskWorker.
+const (
+	TaskKindDeactivate = "deactivate"
+	TaskKindFill       = "fill"
+)
+
+const (
+	FillTriggerUserActivated = "user_activated"
+	FillTriggerTeamCreated   = "team_created"
+	FillTriggerMembersAdded  = "members_added"
+)
+
 type BatchDeactivateTask struct {
 	ID           int
 	TeamID       int
@@ -20,3 +32,14 @@ type BatchDeactivateTask struct {
 	CreatedAt    time.Time
 	ProcessedAt  sql.NullTime
 }
+
+type ReviewerFillTask struct {
+	ID           int
+	TeamID       int
+	Trigger      string
+	Status       string
+	FilledCount  int
+	ErrorMessage sql.NullString
+	CreatedAt    time.Time
+	ProcessedAt  sql.NullTime
+}
@@ -1,8 +1,11 @@
 package domain
 
 type Team struct {
-	ID      int           `json:"id" db:"id"`
-	Name    string        `json:"name" db:"name"`
+	ID           int          `json:"id" db:"id"`
+	Name         string       `json:"name" db:"name"`
+	ReviewerRule ReviewerRule `json:"reviewer_rule" db:"reviewer_rule"`
+	// Version увеличивается при изменении состава команды и статусов участников.
+	Version int           `json:"version" db:"version"`
 	Members []*TeamMember `json:"members,omitempty"`
 }
 
@@ -13,6 +16,9 @@ func (t *Team) Validate() error {
 	if t.Members == nil {
 		return ErrInvalidInput
 	}
+	if t.ReviewerRule != "" && !t.ReviewerRule.IsValid() {
+		return ErrInvalidInput
+	}
 
 	for _, member := range t.Members {
 		if err := member.Validate(); err != nil {
@@ -48,6 +54,15 @@ func (t *Team) GetActiveMembersExcluding(excludeUserIDs ...string) []*TeamMember
 	return result
 }
 
+func (t *Team) GetMember(userID string) *TeamMember {
+	for _, member := range t.Members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
+
 func (t *Team) HasMember(userID string) bool {
 	for _, member := range t.Members {
 		if member.UserID == userID {
@@ -56,3 +71,28 @@ func (t *Team) HasMember(userID string) bool {
 	}
 	return false
 }
+
+// SplitCandidates делит участников команды на кандидатов в ревьюеры и
+// исключённых с указанием причины.
+func (t *Team) SplitCandidates(authorID string, assigned ...string) ([]*TeamMember, []Exclusion) {
+	assignedMap := make(map[string]bool, len(assigned))
+	for _, id := range assigned {
+		assignedMap[id] = true
+	}
+
+	var candidates []*TeamMember
+	var excluded []Exclusion
+	for _, member := range t.Members {
+		switch {
+		case member.UserID == authorID:
+			excluded = append(excluded, Exclusion{UserID: member.UserID, Reason: ExclusionAuthor})
+		case assignedMap[member.UserID]:
+			excluded = append(excluded, Exclusion{UserID: member.UserID, Reason: ExclusionAlreadyAssigned})
+		case !member.IsActive:
+			excluded = append(excluded, Exclusion{UserID: member.UserID, Reason: ExclusionInactive})
+		default:
+			candidates = append(candidates, member)
+		}
+	}
+	return candidates, excluded
+}
@@ -8,23 +8,29 @@ import (
 var userIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 type User struct {
-	UserID   string `json:"user_id" db:"id"`
-	Username string `json:"username" db:"username"`
-	TeamID   int    `json:"team_id" db:"team_id"`
-	IsActive bool   `json:"is_active" db:"is_active"`
+	UserID    string    `json:"user_id" db:"id"`
+	Username  string    `json:"username" db:"username"`
+	TeamID    int       `json:"team_id" db:"team_id"`
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	Seniority Seniority `json:"seniority" db:"seniority"`
+	Role      Role      `json:"role" db:"role"`
 }
 
 type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	IsActive  bool      `json:"is_active"`
+	Seniority Seniority `json:"seniority"`
+	Role      Role      `json:"role"`
 }
 
 func (u *User) ToTeamMember() *TeamMember {
 	return &TeamMember{
-		UserID:   u.UserID,
-		Username: u.Username,
-		IsActive: u.IsActive,
+		UserID:    u.UserID,
+		Username:  u.Username,
+		IsActive:  u.IsActive,
+		Seniority: u.Seniority,
+		Role:      u.Role,
 	}
 }
 
@@ -41,6 +47,12 @@ func (u *User) Validate() error {
 	if u.TeamID <= 0 {
 		return ErrInvalidInput
 	}
+	if u.Seniority != "" && !u.Seniority.IsValid() {
+		return fmt.Errorf("invalid seniority %q: %w", u.Seniority, ErrInvalidInput)
+	}
+	if u.Role != "" && !u.Role.IsValid() {
+		return fmt.Errorf("invalid role %q: %w", u.Role, ErrInvalidInput)
+	}
 	return nil
 }
 
@@ -54,5 +66,11 @@ func (tm *TeamMember) Validate() error {
 	if tm.Username == "" {
 		return ErrInvalidInput
 	}
+	if tm.Seniority != "" && !tm.Seniority.IsValid() {
+		return fmt.Errorf("invalid team member seniority %q: %w", tm.Seniority, ErrInvalidInput)
+	}
+	if tm.Role != "" && !tm.Role.IsValid() {
+		return fmt.Errorf("invalid team member role %q: %w", tm.Role, ErrInvalidInput)
+	}
 	return nil
 }
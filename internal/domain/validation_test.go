@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -38,6 +39,16 @@ func TestUser_Validate(t *testing.T) {
 			user:    domain.User{UserID: "valid_user", Username: "Test User", TeamID: 0},
 			wantErr: true,
 		},
+		{
+			name:    "Lead role",
+			user:    domain.User{UserID: "valid_user", Username: "Test User", TeamID: 1, Role: domain.RoleLead},
+			wantErr: false,
+		},
+		{
+			name:    "Unknown role",
+			user:    domain.User{UserID: "valid_user", Username: "Test User", TeamID: 1, Role: "owner"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -164,3 +175,420 @@ func TestPullRequest_BusinessRules(t *testing.T) {
 		t.Error("MergedAt is too old")
 	}
 }
+
+func TestTeam_SplitCandidates(t *testing.T) {
+	team := domain.Team{
+		Name: "backend",
+		Members: []*domain.TeamMember{
+			{UserID: "author", Username: "a", IsActive: true},
+			{UserID: "assigned", Username: "b", IsActive: true},
+			{UserID: "inactive", Username: "c", IsActive: false},
+			{UserID: "free", Username: "d", IsActive: true},
+		},
+	}
+
+	candidates, excluded := team.SplitCandidates("author", "assigned")
+
+	if len(candidates) != 1 || candidates[0].UserID != "free" {
+		t.Fatalf("Expected only 'free' as candidate, got %v", candidates)
+	}
+
+	want := map[string]domain.ExclusionReason{
+		"author":   domain.ExclusionAuthor,
+		"assigned": domain.ExclusionAlreadyAssigned,
+		"inactive": domain.ExclusionInactive,
+	}
+	if len(excluded) != len(want) {
+		t.Fatalf("Expected %d exclusions, got %d", len(want), len(excluded))
+	}
+	for _, e := range excluded {
+		if want[e.UserID] != e.Reason {
+			t.Errorf("Exclusion for %s = %s, want %s", e.UserID, e.Reason, want[e.UserID])
+		}
+	}
+}
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     domain.WebhookSubscription
+		wantErr bool
+	}{
+		{
+			name:    "Valid subscription",
+			sub:     domain.WebhookSubscription{URL: "https://hooks.example.com/pr", Secret: "s", Events: []string{domain.EventPRMerged}},
+			wantErr: false,
+		},
+		{
+			name:    "All events",
+			sub:     domain.WebhookSubscription{URL: "http://localhost:9000/hook", Secret: "s"},
+			wantErr: false,
+		},
+		{
+			name:    "Unsupported scheme",
+			sub:     domain.WebhookSubscription{URL: "ftp://hooks.example.com", Secret: "s"},
+			wantErr: true,
+		},
+		{
+			name:    "Empty secret",
+			sub:     domain.WebhookSubscription{URL: "https://hooks.example.com", Secret: ""},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown event",
+			sub:     domain.WebhookSubscription{URL: "https://hooks.example.com", Secret: "s", Events: []string{"pr.deleted"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.sub.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("WebhookSubscription.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_Accepts(t *testing.T) {
+	all := domain.WebhookSubscription{}
+	if !all.Accepts(domain.EventUserDeactivated) {
+		t.Error("subscription without filter should accept every event")
+	}
+
+	filtered := domain.WebhookSubscription{Events: []string{domain.EventPRCreated, domain.EventPRMerged}}
+	if !filtered.Accepts(domain.EventPRMerged) {
+		t.Error("filtered subscription should accept pr.merged")
+	}
+	if filtered.Accepts(domain.EventReviewerAssigned) {
+		t.Error("filtered subscription should not accept reviewer.assigned")
+	}
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	mustEvent := func(eventType string, payload any) *domain.Event {
+		e, err := domain.NewEvent(eventType, "agg", payload)
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		return e
+	}
+	assigned := mustEvent(domain.EventReviewerAssigned, domain.ReviewerEventPayload{PullRequestID: "pr-1", ReviewerID: "u2"})
+	merged := mustEvent(domain.EventPRMerged, domain.PREventPayload{PullRequestID: "pr-1", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"}})
+	teamCreated := mustEvent(domain.EventTeamCreated, domain.TeamEventPayload{TeamName: "backend"})
+
+	backend := map[string]bool{"u1": true, "u2": true}
+
+	tests := []struct {
+		name   string
+		filter domain.EventFilter
+		event  *domain.Event
+		want   bool
+	}{
+		{"No filter", domain.EventFilter{}, assigned, true},
+		{"Reviewer matches user", domain.EventFilter{UserID: "u2"}, assigned, true},
+		{"Other user", domain.EventFilter{UserID: "u1"}, assigned, false},
+		{"Author matches user", domain.EventFilter{UserID: "u1"}, merged, true},
+		{"Reviewer list matches user", domain.EventFilter{UserID: "u3"}, merged, true},
+		{"Team member involved", domain.EventFilter{TeamName: "backend", TeamMembers: backend}, assigned, true},
+		{"Team event by name", domain.EventFilter{TeamName: "backend", TeamMembers: backend}, teamCreated, true},
+		{"Other team", domain.EventFilter{TeamName: "mobile", TeamMembers: map[string]bool{"u9": true}}, merged, false},
+		{"User and team", domain.EventFilter{UserID: "u3", TeamName: "backend", TeamMembers: backend}, merged, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.event); got != tt.want {
+				t.Errorf("EventFilter.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotificationPreferences_Validate(t *testing.T) {
+	valid := func() *domain.NotificationPreferences {
+		return &domain.NotificationPreferences{
+			UserID:         "u1",
+			Channels:       []string{domain.NotifyChannelEmail, domain.NotifyChannelChat},
+			Email:          "alice@example.com",
+			ChatWebhookURL: "https://chat.example.com/hooks/abc",
+			Mode:           domain.NotifyModeImmediate,
+			QuietHours:     &domain.QuietHours{Start: 22 * 60, End: 8 * 60},
+			Timezone:       "Europe/Moscow",
+			DigestAt:       domain.DefaultDigestAt,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(p *domain.NotificationPreferences)
+		wantErr bool
+	}{
+		{"Valid", func(*domain.NotificationPreferences) {}, false},
+		{"No channels", func(p *domain.NotificationPreferences) { p.Channels = nil; p.Email = "" }, false},
+		{"Unknown channel", func(p *domain.NotificationPreferences) { p.Channels = []string{"sms"} }, true},
+		{"Email channel without email", func(p *domain.NotificationPreferences) { p.Email = "" }, true},
+		{"Email with display name", func(p *domain.NotificationPreferences) { p.Email = "Alice <alice@example.com>" }, true},
+		{"Chat URL not http", func(p *domain.NotificationPreferences) { p.ChatWebhookURL = "ftp://chat" }, true},
+		{"Unknown mode", func(p *domain.NotificationPreferences) { p.Mode = "weekly" }, true},
+		{"Empty quiet hours", func(p *domain.NotificationPreferences) { p.QuietHours = &domain.QuietHours{Start: 60, End: 60} }, true},
+		{"Quiet hours out of range", func(p *domain.NotificationPreferences) { p.QuietHours.End = 24 * 60 }, true},
+		{"Unknown timezone", func(p *domain.NotificationPreferences) { p.Timezone = "Mars/Olympus" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := valid()
+			tt.mutate(p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNotificationPreferences_NextDelivery(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, time.UTC)
+	}
+	night := &domain.QuietHours{Start: 22 * 60, End: 8 * 60}
+	lunch := &domain.QuietHours{Start: 13 * 60, End: 14 * 60}
+
+	tests := []struct {
+		name  string
+		prefs domain.NotificationPreferences
+		now   time.Time
+		want  time.Time
+	}{
+		{"Immediate", domain.NotificationPreferences{Mode: domain.NotifyModeImmediate, Timezone: "UTC"}, at(12, 0), at(12, 0)},
+		{"Outside quiet hours", domain.NotificationPreferences{Mode: domain.NotifyModeImmediate, QuietHours: night, Timezone: "UTC"}, at(12, 0), at(12, 0)},
+		{"Quiet hours before midnight", domain.NotificationPreferences{Mode: domain.NotifyModeImmediate, QuietHours: night, Timezone: "UTC"}, at(23, 0), at(32, 0)},
+		{"Quiet hours after midnight", domain.NotificationPreferences{Mode: domain.NotifyModeImmediate, QuietHours: night, Timezone: "UTC"}, at(3, 0), at(8, 0)},
+		{"Daytime quiet hours", domain.NotificationPreferences{Mode: domain.NotifyModeImmediate, QuietHours: lunch, Timezone: "UTC"}, at(13, 30), at(14, 0)},
+		{"Digest later today", domain.NotificationPreferences{Mode: domain.NotifyModeDigest, DigestAt: 18 * 60, Timezone: "UTC"}, at(12, 0), at(18, 0)},
+		{"Digest tomorrow", domain.NotificationPreferences{Mode: domain.NotifyModeDigest, DigestAt: 9 * 60, Timezone: "UTC"}, at(12, 0), at(33, 0)},
+		{"Timezone", domain.NotificationPreferences{Mode: domain.NotifyModeDigest, DigestAt: 9 * 60, Timezone: "Europe/Moscow"}, at(5, 0), at(6, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prefs.NextDelivery(tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextDelivery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDigest(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	sla := domain.ReviewSLA{Target: 48 * time.Hour, Warning: 24 * time.Hour}
+	prs := []*domain.PullRequestShort{
+		{PullRequestID: "fresh", CreatedAt: now.Add(-2 * time.Hour)},
+		{PullRequestID: "old", CreatedAt: now.Add(-72 * time.Hour)},
+		{PullRequestID: "aging", CreatedAt: now.Add(-30 * time.Hour)},
+	}
+
+	d := domain.NewDigest("u1", prs, sla, now)
+
+	wantOrder := []string{"old", "aging", "fresh"}
+	wantStatus := []domain.SLAStatus{domain.SLAStatusBreached, domain.SLAStatusAtRisk, domain.SLAStatusOK}
+	for i, item := range d.Items {
+		if item.PullRequestID != wantOrder[i] || item.SLAStatus != wantStatus[i] {
+			t.Errorf("item %d = %s/%s, want %s/%s", i, item.PullRequestID, item.SLAStatus, wantOrder[i], wantStatus[i])
+		}
+	}
+	if d.Breached != 1 || d.AtRisk != 1 {
+		t.Errorf("Breached = %d, AtRisk = %d, want 1 and 1", d.Breached, d.AtRisk)
+	}
+	if want := now.Add(-24 * time.Hour); !d.Items[0].DueAt.Equal(want) {
+		t.Errorf("DueAt = %v, want %v", d.Items[0].DueAt, want)
+	}
+}
+
+func TestNotificationPreferences_DigestDue(t *testing.T) {
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	prefs := func(last time.Time) *domain.NotificationPreferences {
+		return &domain.NotificationPreferences{
+			Channels:     []string{domain.NotifyChannelChat},
+			Timezone:     "UTC",
+			DigestAt:     9 * 60,
+			DailyDigest:  true,
+			LastDigestAt: last,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		prefs *domain.NotificationPreferences
+		now   time.Time
+		want  bool
+	}{
+		{"Before digest time", prefs(at(9, 9)), at(10, 8), false},
+		{"Digest time passed", prefs(at(9, 9)), at(10, 9), true},
+		{"Already sent today", prefs(at(10, 9)), at(10, 15), false},
+		{"Configured after today's slot", prefs(at(10, 12)), at(10, 15), false},
+		{"Disabled", func() *domain.NotificationPreferences { p := prefs(at(9, 9)); p.DailyDigest = false; return p }(), at(10, 10), false},
+		{"No channels", func() *domain.NotificationPreferences { p := prefs(at(9, 9)); p.Channels = nil; return p }(), at(10, 10), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prefs.DigestDue(tt.now); got != tt.want {
+				t.Errorf("DigestDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRFilter_Normalize(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	nextDay := day.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  domain.PRFilter
+		wantErr bool
+	}{
+		{"Defaults", domain.PRFilter{}, false},
+		{"Unknown status", domain.PRFilter{Status: "CLOSED"}, true},
+		{"Unknown sort", domain.PRFilter{Sort: "author"}, true},
+		{"Unknown order", domain.PRFilter{Order: "up"}, true},
+		{"Limit too large", domain.PRFilter{Limit: domain.MaxPRPageSize + 1}, true},
+		{"Empty date range", domain.PRFilter{CreatedFrom: &nextDay, CreatedTo: &day}, true},
+		{"Valid date range", domain.PRFilter{MergedFrom: &day, MergedTo: &nextDay}, false},
+		{"Cursor for other sort", domain.PRFilter{Sort: domain.PRSortName, Cursor: &domain.PRCursor{Sort: domain.PRSortCreatedAt, Order: domain.SortDesc, ID: "pr-1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.filter
+			if err := f.Normalize(); (err != nil) != tt.wantErr {
+				t.Errorf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	f := domain.PRFilter{}
+	_ = f.Normalize()
+	if f.Sort != domain.PRSortCreatedAt || f.Order != domain.SortDesc || f.Limit != domain.DefaultPRPageSize {
+		t.Errorf("defaults = %s %s %d", f.Sort, f.Order, f.Limit)
+	}
+}
+
+func TestPRCursor_RoundTrip(t *testing.T) {
+	f := &domain.PRFilter{Sort: domain.PRSortCreatedAt, Order: domain.SortDesc}
+	created := time.Date(2024, 3, 10, 12, 30, 0, 123456000, time.UTC)
+	c := domain.NewPRCursor(f, &domain.PullRequest{PullRequestID: "pr-7", CreatedAt: created})
+
+	got, err := domain.DecodePRCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodePRCursor() error = %v", err)
+	}
+	if got.ID != "pr-7" || !got.Time.Equal(created) || got.Sort != f.Sort || got.Order != f.Order {
+		t.Errorf("cursor = %+v", got)
+	}
+
+	if _, err := domain.DecodePRCursor("not-a-cursor"); err == nil {
+		t.Error("DecodePRCursor() accepted garbage")
+	}
+}
+
+func TestPullRequestDetails_ETag(t *testing.T) {
+	active := &domain.User{UserID: "u2", Username: "Bob", IsActive: true}
+	d := &domain.PullRequestDetails{
+		PR:        &domain.PullRequest{PullRequestID: "pr-1", Version: 3},
+		Reviewers: []*domain.ReviewerState{domain.NewReviewerState(active)},
+	}
+	tag := d.ETag()
+
+	version, err := domain.ParseETagVersion(tag)
+	if err != nil || version != 3 {
+		t.Fatalf("ParseETagVersion(%s) = %d, %v", tag, version, err)
+	}
+
+	inactive := *active
+	inactive.IsActive = false
+	d.Reviewers = []*domain.ReviewerState{domain.NewReviewerState(&inactive)}
+	if d.Reviewers[0].Status != domain.ReviewerStatusInactive {
+		t.Errorf("status = %s, want INACTIVE", d.Reviewers[0].Status)
+	}
+	if d.ETag() == tag {
+		t.Error("ETag() did not change with reviewer state")
+	}
+}
+
+func TestParseETagVersion(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    int
+		wantErr bool
+	}{
+		{tag: `"5-abcdef"`, want: 5},
+		{tag: `W/"5-abcdef"`, want: 5},
+		{tag: `"12"`, want: 12},
+		{tag: `7`, want: 7},
+		{tag: `"0"`, wantErr: true},
+		{tag: `"abc"`, wantErr: true},
+		{tag: ``, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := domain.ParseETagVersion(tt.tag)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseETagVersion(%q) = %d, %v", tt.tag, got, err)
+		}
+	}
+}
+
+func TestAPIToken(t *testing.T) {
+	plaintext, hash, err := domain.GenerateAPIToken()
+	if err != nil {
+		t.Fatalf("GenerateAPIToken() error = %v", err)
+	}
+	if !strings.HasPrefix(plaintext, domain.APITokenPrefix) || domain.HashAPIToken(plaintext) != hash {
+		t.Errorf("token %q does not match its hash", plaintext)
+	}
+
+	valid := domain.APIToken{Name: "ci", Scopes: []string{domain.ScopePRWrite, domain.ScopeStatsRead}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for _, bad := range []domain.APIToken{
+		{Name: "", Scopes: []string{domain.ScopePRWrite}},
+		{Name: "ci"},
+		{Name: "ci", Scopes: []string{"pr:delete"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) accepted invalid token", bad)
+		}
+	}
+
+	p := &domain.Principal{TokenID: 7, Scopes: []string{domain.ScopeStatsRead}}
+	if !p.HasScope(domain.ScopeStatsRead) || p.HasScope(domain.ScopePRWrite) {
+		t.Errorf("HasScope() mismatch for %v", p.Scopes)
+	}
+	admin := &domain.Principal{Scopes: []string{domain.ScopeAdmin}}
+	if !admin.HasScope(domain.ScopeTeamAdmin) {
+		t.Error("admin scope must imply team:admin")
+	}
+}
+
+func TestAssignmentDecision_Outcome(t *testing.T) {
+	tests := []struct {
+		selected []string
+		slots    int
+		want     string
+	}{
+		{[]string{"u1", "u2"}, 2, domain.AssignmentOutcomeAssigned},
+		{[]string{"u1"}, 2, domain.AssignmentOutcomePartial},
+		{[]string{}, 1, domain.AssignmentOutcomeNoCandidate},
+		{[]string{}, 0, domain.AssignmentOutcomeAssigned},
+	}
+	for _, tt := range tests {
+		d := &domain.AssignmentDecision{Selected: tt.selected, Slots: tt.slots}
+		if got := d.Outcome(); got != tt.want {
+			t.Errorf("Outcome(selected=%v, slots=%d) = %s, want %s", tt.selected, tt.slots, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"database/sql"
+	"net/url"
+	"time"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// WebhookSubscription — внешний получатель событий. Пустой Events означает
+// подписку на все события.
+type WebhookSubscription struct {
+	ID        int
+	URL       string
+	Secret    string
+	Events    []string
+	IsActive  bool
+	CreatedAt time.Time
+}
+
+func (s *WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidInput
+	}
+	if s.Secret == "" {
+		return ErrInvalidInput
+	}
+	for _, e := range s.Events {
+		if !IsValidEventType(e) {
+			return ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+func (s *WebhookSubscription) Accepts(eventType string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int
+	EventID        int64
+	EventType      string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseCode   sql.NullInt32
+	ErrorMessage   sql.NullString
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
+
+	Subscription *WebhookSubscription
+	Event        *Event
+}
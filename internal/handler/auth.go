@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"avito/internal/domain"
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type AuthMiddleware struct {
+	tokenService service.TokenService
+	jwt          service.Authenticator
+	enabled      bool
+	logger       *logger.Logger
+}
+
+type AuthOption func(*AuthMiddleware)
+
+// WithJWT включает проверку JWT: bearer-токены без префикса API-токена
+// передаются authenticator.
+func WithJWT(authenticator service.Authenticator) AuthOption {
+	return func(m *AuthMiddleware) {
+		m.jwt = authenticator
+	}
+}
+
+// NewAuthMiddleware создаёт проверку API-токенов. При enabled == false
+// middleware пропускает все запросы без principal.
+func NewAuthMiddleware(tokenService service.TokenService, enabled bool, log *logger.Logger, opts ...AuthOption) *AuthMiddleware {
+	m := &AuthMiddleware{
+		tokenService: tokenService,
+		enabled:      enabled,
+		logger:       log,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Authenticate требует заголовок "Authorization: Bearer <token>", кладёт
+// principal в контекст и записывает в аудит изменяющие запросы, выполненные
+// с API-токеном.
+func (m *AuthMiddleware) Authenticate(next http.Handler) http.Handler {
+	if !m.enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			response.Unauthorized(w, "UNAUTHORIZED", "missing bearer token")
+			return
+		}
+		principal, err := m.authenticate(r.Context(), token)
+		if err != nil {
+			m.logger.WithContext(r.Context()).Warn("Authentication failed", "path", r.URL.Path, "error", err)
+			response.HandleError(w, err)
+			return
+		}
+
+		r = r.WithContext(domain.WithPrincipal(r.Context(), principal))
+		if !isMutation(r.Method) || principal.TokenID == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		entry := &domain.TokenAuditEntry{
+			TokenID:   principal.TokenID,
+			Method:    r.Method,
+			Route:     routePattern(r),
+			Status:    status,
+			RequestID: middleware.GetReqID(r.Context()),
+		}
+		if err := m.tokenService.RecordMutation(context.WithoutCancel(r.Context()), entry); err != nil {
+			m.logger.WithContext(r.Context()).Error("Failed to record token audit entry", "token_id", principal.TokenID, "error", err)
+		}
+	})
+}
+
+// RequireScope пропускает запрос, только если у токена есть scope.
+func (m *AuthMiddleware) RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !m.enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := domain.PrincipalFromContext(r.Context())
+			if principal == nil {
+				response.Unauthorized(w, "UNAUTHORIZED", "missing bearer token")
+				return
+			}
+			if !principal.HasScope(scope) {
+				m.logger.WithContext(r.Context()).Warn("Insufficient token scope",
+					"subject", principal.Subject(),
+					"scope", scope,
+					"path", r.URL.Path,
+				)
+				response.HandleError(w, domain.ErrInsufficientScope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (m *AuthMiddleware) authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	if m.jwt != nil && !strings.HasPrefix(token, domain.APITokenPrefix) {
+		return m.jwt.Authenticate(ctx, token)
+	}
+	return m.tokenService.Authenticate(ctx, token)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,167 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito/internal/domain"
+	"avito/internal/handler"
+	"avito/pkg/logger"
+)
+
+type fakeTokenService struct {
+	tokens  map[string]*domain.Principal
+	audited []*domain.TokenAuditEntry
+}
+
+func (f *fakeTokenService) CreateToken(context.Context, string, []string, string) (*domain.APIToken, string, error) {
+	return nil, "", nil
+}
+
+func (f *fakeTokenService) ListTokens(context.Context) ([]*domain.APIToken, error) {
+	return nil, nil
+}
+
+func (f *fakeTokenService) RevokeToken(context.Context, int64) error {
+	return nil
+}
+
+func (f *fakeTokenService) Authenticate(_ context.Context, plaintext string) (*domain.Principal, error) {
+	p, ok := f.tokens[plaintext]
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	return p, nil
+}
+
+func (f *fakeTokenService) RecordMutation(_ context.Context, entry *domain.TokenAuditEntry) error {
+	f.audited = append(f.audited, entry)
+	return nil
+}
+
+func (f *fakeTokenService) ListAudit(context.Context, int64) ([]*domain.TokenAuditEntry, error) {
+	return nil, nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	svc := &fakeTokenService{tokens: map[string]*domain.Principal{
+		"prt_writer": {TokenID: 1, Scopes: []string{domain.ScopePRWrite}},
+		"prt_stats":  {TokenID: 2, Scopes: []string{domain.ScopeStatsRead}},
+		"prt_admin":  {TokenID: 3, Scopes: []string{domain.ScopeAdmin}},
+	}}
+	auth := handler.NewAuthMiddleware(svc, true, logger.NewWithWriter(io.Discard, "error", "json"))
+
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	r := chi.NewRouter()
+	r.Use(auth.Authenticate)
+	r.With(auth.RequireScope(domain.ScopePRWrite)).Post("/pullRequest/{action}", ok)
+	r.Get("/team/get", ok)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     string
+		wantStatus int
+	}{
+		{"No token", http.MethodGet, "/team/get", "", http.StatusUnauthorized},
+		{"Unknown token", http.MethodGet, "/team/get", "Bearer prt_unknown", http.StatusUnauthorized},
+		{"Wrong scheme", http.MethodGet, "/team/get", "Basic prt_writer", http.StatusUnauthorized},
+		{"Any token reads", http.MethodGet, "/team/get", "Bearer prt_stats", http.StatusOK},
+		{"Missing scope", http.MethodPost, "/pullRequest/merge", "Bearer prt_stats", http.StatusForbidden},
+		{"Scope granted", http.MethodPost, "/pullRequest/merge", "Bearer prt_writer", http.StatusOK},
+		{"Admin implies all", http.MethodPost, "/pullRequest/create", "bearer prt_admin", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// Аудит пишется для изменяющих запросов с валидным токеном, включая отказы по scope.
+	if len(svc.audited) != 3 {
+		t.Fatalf("audited = %d entries, want 3", len(svc.audited))
+	}
+	if e := svc.audited[1]; e.TokenID != 1 || e.Route != "/pullRequest/{action}" || e.Status != http.StatusOK {
+		t.Errorf("audit entry = %+v", e)
+	}
+}
+
+func TestAuthMiddleware_Disabled(t *testing.T) {
+	auth := handler.NewAuthMiddleware(&fakeTokenService{}, false, logger.NewWithWriter(io.Discard, "error", "json"))
+	h := auth.Authenticate(auth.RequireScope(domain.ScopeAdmin)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/tokens", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+}
+
+type fakeJWTAuthenticator map[string]*domain.Principal
+
+func (f fakeJWTAuthenticator) Authenticate(_ context.Context, token string) (*domain.Principal, error) {
+	p, ok := f[token]
+	if !ok {
+		return nil, domain.ErrUnauthorized
+	}
+	return p, nil
+}
+
+func TestAuthMiddleware_JWT(t *testing.T) {
+	svc := &fakeTokenService{tokens: map[string]*domain.Principal{
+		"prt_writer": {TokenID: 1, Scopes: []string{domain.ScopePRWrite}},
+	}}
+	jwt := fakeJWTAuthenticator{
+		"eyJ.lead": {UserID: "u1", Role: domain.RoleLead, Scopes: []string{domain.ScopePRWrite}},
+	}
+	auth := handler.NewAuthMiddleware(svc, true, logger.NewWithWriter(io.Discard, "error", "json"), handler.WithJWT(jwt))
+
+	var got *domain.Principal
+	h := auth.Authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = domain.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range []struct {
+		token      string
+		wantStatus int
+		wantSubj   string
+	}{
+		{"eyJ.lead", http.StatusOK, "user:u1"},
+		{"prt_writer", http.StatusOK, "token:1"},
+		{"eyJ.unknown", http.StatusUnauthorized, ""},
+	} {
+		got = nil
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tc.token, rec.Code, tc.wantStatus)
+		}
+		if tc.wantSubj != "" && (got == nil || got.Subject() != tc.wantSubj) {
+			t.Errorf("%s: principal = %+v, want subject %s", tc.token, got, tc.wantSubj)
+		}
+	}
+
+	// Аудит ведётся только по API-токенам из БД.
+	if len(svc.audited) != 1 || svc.audited[0].TokenID != 1 {
+		t.Errorf("audited = %+v, want one entry for token 1", svc.audited)
+	}
+}
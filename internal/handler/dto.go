@@ -1,20 +1,32 @@
 package handler
 
 import (
+	"encoding/json"
+	"net/url"
+	"strconv"
 	"time"
 
 	"avito/internal/domain"
+	"avito/internal/notifier"
 )
 
 type CreateTeamRequest struct {
-	TeamName string           `json:"team_name"`
-	Members  []*TeamMemberDTO `json:"members"`
+	TeamName     string           `json:"team_name"`
+	ReviewerRule string           `json:"reviewer_rule,omitempty"`
+	Members      []*TeamMemberDTO `json:"members"`
 }
 
 type TeamMemberDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	IsActive  bool   `json:"is_active"`
+	Seniority string `json:"seniority,omitempty"`
+	Role      string `json:"role,omitempty"`
+}
+
+type AddTeamMembersRequest struct {
+	TeamName string           `json:"team_name"`
+	Members  []*TeamMemberDTO `json:"members"`
 }
 
 type TeamResponse struct {
@@ -22,9 +34,11 @@ type TeamResponse struct {
 }
 
 type TeamDTO struct {
-	ID      int              `json:"id"`
-	Name    string           `json:"name"`
-	Members []*TeamMemberDTO `json:"members"`
+	ID           int              `json:"id"`
+	Name         string           `json:"name"`
+	ReviewerRule string           `json:"reviewer_rule"`
+	Version      int              `json:"version"`
+	Members      []*TeamMemberDTO `json:"members"`
 }
 
 type SetIsActiveRequest struct {
@@ -32,15 +46,22 @@ type SetIsActiveRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
+type SetRoleRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
 type UserResponse struct {
 	User *UserDTO `json:"user"`
 }
 
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamID   int    `json:"team_id"`
-	IsActive bool   `json:"is_active"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	TeamID    int    `json:"team_id"`
+	IsActive  bool   `json:"is_active"`
+	Seniority string `json:"seniority"`
+	Role      string `json:"role"`
 }
 
 type CreatePRRequest struct {
@@ -75,6 +96,34 @@ type PRDTO struct {
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         time.Time  `json:"createdAt"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	Version           int        `json:"version"`
+	PairingWarning    string     `json:"pairing_warning,omitempty"`
+}
+
+type ExplainResponse struct {
+	PullRequestID     string         `json:"pull_request_id"`
+	AssignedReviewers []string       `json:"assigned_reviewers"`
+	Decisions         []*DecisionDTO `json:"decisions"`
+}
+
+type DecisionDTO struct {
+	Kind           string          `json:"kind"`
+	Strategy       string          `json:"strategy"`
+	Rule           string          `json:"reviewer_rule"`
+	RuleViolated   bool            `json:"rule_violated"`
+	Seed           int64           `json:"seed"`
+	Slots          int             `json:"slots"`
+	Pool           []string        `json:"candidate_pool"`
+	Candidates     []string        `json:"eligible"`
+	Excluded       []*ExclusionDTO `json:"excluded"`
+	Selected       []string        `json:"selected"`
+	ReplacedUserID string          `json:"replaced_user_id,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+}
+
+type ExclusionDTO struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
 }
 
 type PRShortDTO struct {
@@ -96,15 +145,19 @@ func ToTeamDTO(team *domain.Team) *TeamDTO {
 	members := make([]*TeamMemberDTO, 0, len(team.Members))
 	for _, m := range team.Members {
 		members = append(members, &TeamMemberDTO{
-			UserID:   m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			UserID:    m.UserID,
+			Username:  m.Username,
+			IsActive:  m.IsActive,
+			Seniority: string(m.Seniority),
+			Role:      string(m.Role),
 		})
 	}
 	return &TeamDTO{
-		ID:      team.ID,
-		Name:    team.Name,
-		Members: members,
+		ID:           team.ID,
+		Name:         team.Name,
+		ReviewerRule: string(team.ReviewerRule),
+		Version:      team.Version,
+		Members:      members,
 	}
 }
 
@@ -113,10 +166,12 @@ func ToUserDTO(user *domain.User) *UserDTO {
 		return nil
 	}
 	return &UserDTO{
-		UserID:   user.UserID,
-		Username: user.Username,
-		TeamID:   user.TeamID,
-		IsActive: user.IsActive,
+		UserID:    user.UserID,
+		Username:  user.Username,
+		TeamID:    user.TeamID,
+		IsActive:  user.IsActive,
+		Seniority: string(user.Seniority),
+		Role:      string(user.Role),
 	}
 }
 
@@ -132,6 +187,47 @@ func ToPRDTO(pr *domain.PullRequest) *PRDTO {
 		AssignedReviewers: pr.AssignedReviewers,
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		Version:           pr.Version,
+		PairingWarning:    pr.PairingWarning,
+	}
+}
+
+func ToExplainResponse(explanation *domain.AssignmentExplanation) *ExplainResponse {
+	if explanation == nil || explanation.PullRequest == nil {
+		return nil
+	}
+	reviewers := explanation.PullRequest.AssignedReviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+	decisions := make([]*DecisionDTO, 0, len(explanation.Decisions))
+	for _, d := range explanation.Decisions {
+		excluded := make([]*ExclusionDTO, 0, len(d.Excluded))
+		for _, e := range d.Excluded {
+			excluded = append(excluded, &ExclusionDTO{
+				UserID: e.UserID,
+				Reason: string(e.Reason),
+			})
+		}
+		decisions = append(decisions, &DecisionDTO{
+			Kind:           d.Kind,
+			Strategy:       d.Strategy,
+			Rule:           string(d.Rule),
+			RuleViolated:   d.RuleViolated,
+			Seed:           d.Seed,
+			Slots:          d.Slots,
+			Pool:           d.Pool(),
+			Candidates:     d.Candidates,
+			Excluded:       excluded,
+			Selected:       d.Selected,
+			ReplacedUserID: d.ReplacedUserID,
+			CreatedAt:      d.CreatedAt,
+		})
+	}
+	return &ExplainResponse{
+		PullRequestID:     explanation.PullRequest.PullRequestID,
+		AssignedReviewers: reviewers,
+		Decisions:         decisions,
 	}
 }
 
@@ -158,10 +254,38 @@ func (r *CreateTeamRequest) Validate() error {
 	if len(r.Members) == 0 {
 		return domain.ErrInvalidInput
 	}
+	if r.ReviewerRule != "" && !domain.ReviewerRule(r.ReviewerRule).IsValid() {
+		return domain.ErrInvalidInput
+	}
 	for _, member := range r.Members {
 		if member.UserID == "" || member.Username == "" {
 			return domain.ErrInvalidInput
 		}
+		if member.Seniority != "" && !domain.Seniority(member.Seniority).IsValid() {
+			return domain.ErrInvalidInput
+		}
+		// Роль меняет только администратор через /users/setRole.
+		if member.Role != "" {
+			return domain.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+func (r *AddTeamMembersRequest) Validate() error {
+	if r.TeamName == "" || len(r.Members) == 0 {
+		return domain.ErrInvalidInput
+	}
+	for _, member := range r.Members {
+		if member.UserID == "" || member.Username == "" {
+			return domain.ErrInvalidInput
+		}
+		if member.Seniority != "" && !domain.Seniority(member.Seniority).IsValid() {
+			return domain.ErrInvalidInput
+		}
+		if member.Role != "" {
+			return domain.ErrInvalidInput
+		}
 	}
 	return nil
 }
@@ -173,6 +297,13 @@ func (r *SetIsActiveRequest) Validate() error {
 	return nil
 }
 
+func (r *SetRoleRequest) Validate() error {
+	if r.UserID == "" || !domain.Role(r.Role).IsValid() {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
 func (r *CreatePRRequest) Validate() error {
 	if r.PullRequestID == "" {
 		return domain.ErrInvalidInput
@@ -213,3 +344,507 @@ func (r *BatchDeactivateRequest) Validate() error {
 	}
 	return nil
 }
+
+type LinkAccountRequest struct {
+	Provider   string `json:"provider"`
+	Login      string `json:"login"`
+	ExternalID int64  `json:"external_id,omitempty"`
+	UserID     string `json:"user_id"`
+}
+
+func (r *LinkAccountRequest) Validate() error {
+	if !domain.IsValidProvider(r.Provider) {
+		return domain.ErrInvalidInput
+	}
+	if r.Login == "" || r.UserID == "" || r.ExternalID < 0 {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+type UnlinkAccountRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+}
+
+func (r *UnlinkAccountRequest) Validate() error {
+	if !domain.IsValidProvider(r.Provider) || r.Login == "" {
+		return domain.ErrInvalidInput
+	}
+	return nil
+}
+
+type ExternalAccountResponse struct {
+	Account *ExternalAccountDTO `json:"account"`
+}
+
+type ExternalAccountDTO struct {
+	Provider   string    `json:"provider"`
+	Login      string    `json:"login"`
+	ExternalID int64     `json:"external_id,omitempty"`
+	UserID     string    `json:"user_id"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ToExternalAccountDTO(account *domain.ExternalAccount) *ExternalAccountDTO {
+	if account == nil {
+		return nil
+	}
+	return &ExternalAccountDTO{
+		Provider:   account.Provider,
+		Login:      account.Login,
+		ExternalID: account.ExternalID,
+		UserID:     account.UserID,
+		CreatedAt:  account.CreatedAt,
+	}
+}
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	if r.URL == "" || r.Secret == "" {
+		return domain.ErrInvalidInput
+	}
+	for _, e := range r.Events {
+		if !domain.IsValidEventType(e) {
+			return domain.ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+type WebhookSubscriptionResponse struct {
+	Subscription *WebhookSubscriptionDTO `json:"subscription"`
+}
+
+type WebhookListResponse struct {
+	Subscriptions []*WebhookSubscriptionDTO `json:"subscriptions"`
+}
+
+type WebhookSubscriptionDTO struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type WebhookDeliveriesResponse struct {
+	SubscriptionID int                   `json:"subscription_id"`
+	Deliveries     []*WebhookDeliveryDTO `json:"deliveries"`
+}
+
+type WebhookDeliveryDTO struct {
+	ID            int64      `json:"id"`
+	EventID       int64      `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	ResponseCode  *int       `json:"response_code,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
+}
+
+func ToWebhookSubscriptionDTO(sub *domain.WebhookSubscription) *WebhookSubscriptionDTO {
+	if sub == nil {
+		return nil
+	}
+	events := sub.Events
+	if events == nil {
+		events = []string{}
+	}
+	return &WebhookSubscriptionDTO{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    events,
+		IsActive:  sub.IsActive,
+		CreatedAt: sub.CreatedAt,
+	}
+}
+
+func ToWebhookDeliveryDTOs(deliveries []*domain.WebhookDelivery) []*WebhookDeliveryDTO {
+	result := make([]*WebhookDeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		dto := &WebhookDeliveryDTO{
+			ID:            d.ID,
+			EventID:       d.EventID,
+			EventType:     d.EventType,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			NextAttemptAt: d.NextAttemptAt,
+			Error:         d.ErrorMessage.String,
+			CreatedAt:     d.CreatedAt,
+		}
+		if d.ResponseCode.Valid {
+			code := int(d.ResponseCode.Int32)
+			dto.ResponseCode = &code
+		}
+		if d.DeliveredAt.Valid {
+			dto.DeliveredAt = &d.DeliveredAt.Time
+		}
+		result = append(result, dto)
+	}
+	return result
+}
+
+type EventDTO struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func ToEventDTO(e *domain.Event) *EventDTO {
+	if e == nil {
+		return nil
+	}
+	return &EventDTO{
+		ID:          e.ID,
+		Type:        e.Type,
+		AggregateID: e.AggregateID,
+		CreatedAt:   e.CreatedAt,
+		Data:        e.Payload,
+	}
+}
+
+type QuietHoursDTO struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type NotificationPreferencesRequest struct {
+	Channels       []string       `json:"channels"`
+	Email          string         `json:"email"`
+	ChatWebhookURL string         `json:"chat_webhook_url"`
+	Mode           string         `json:"mode"`
+	QuietHours     *QuietHoursDTO `json:"quiet_hours"`
+	Timezone       string         `json:"timezone"`
+	DigestAt       string         `json:"digest_at"`
+	DailyDigest    *bool          `json:"daily_digest"`
+}
+
+// ToDomain переводит запрос в настройки, подставляя значения по умолчанию
+// для незаданных полей; остальная проверка — в domain.NotificationPreferences.Validate.
+func (r *NotificationPreferencesRequest) ToDomain(userID string) (*domain.NotificationPreferences, error) {
+	prefs := &domain.NotificationPreferences{
+		UserID:         userID,
+		Channels:       r.Channels,
+		Email:          r.Email,
+		ChatWebhookURL: r.ChatWebhookURL,
+		Mode:           r.Mode,
+		Timezone:       r.Timezone,
+		DigestAt:       domain.DefaultDigestAt,
+		DailyDigest:    r.DailyDigest == nil || *r.DailyDigest,
+	}
+	if prefs.Channels == nil {
+		prefs.Channels = []string{}
+	}
+	if prefs.Mode == "" {
+		prefs.Mode = domain.NotifyModeImmediate
+	}
+	if prefs.Timezone == "" {
+		prefs.Timezone = "UTC"
+	}
+	if r.DigestAt != "" {
+		digestAt, err := domain.ParseClock(r.DigestAt)
+		if err != nil {
+			return nil, err
+		}
+		prefs.DigestAt = digestAt
+	}
+	if r.QuietHours != nil {
+		start, err := domain.ParseClock(r.QuietHours.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := domain.ParseClock(r.QuietHours.End)
+		if err != nil {
+			return nil, err
+		}
+		prefs.QuietHours = &domain.QuietHours{Start: start, End: end}
+	}
+	return prefs, nil
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences *NotificationPreferencesDTO `json:"preferences"`
+}
+
+type NotificationPreferencesDTO struct {
+	UserID         string         `json:"user_id"`
+	Channels       []string       `json:"channels"`
+	Email          string         `json:"email,omitempty"`
+	ChatWebhookURL string         `json:"chat_webhook_url,omitempty"`
+	Mode           string         `json:"mode"`
+	QuietHours     *QuietHoursDTO `json:"quiet_hours,omitempty"`
+	Timezone       string         `json:"timezone"`
+	DigestAt       string         `json:"digest_at"`
+	DailyDigest    bool           `json:"daily_digest"`
+}
+
+func ToNotificationPreferencesDTO(p *domain.NotificationPreferences) *NotificationPreferencesDTO {
+	if p == nil {
+		return nil
+	}
+	channels := p.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	dto := &NotificationPreferencesDTO{
+		UserID:         p.UserID,
+		Channels:       channels,
+		Email:          p.Email,
+		ChatWebhookURL: p.ChatWebhookURL,
+		Mode:           p.Mode,
+		Timezone:       p.Timezone,
+		DigestAt:       domain.FormatClock(p.DigestAt),
+		DailyDigest:    p.DailyDigest,
+	}
+	if p.QuietHours != nil {
+		dto.QuietHours = &QuietHoursDTO{
+			Start: domain.FormatClock(p.QuietHours.Start),
+			End:   domain.FormatClock(p.QuietHours.End),
+		}
+	}
+	return dto
+}
+
+type DigestResponse struct {
+	Digest *DigestDTO `json:"digest"`
+}
+
+type DigestDTO struct {
+	UserID      string           `json:"user_id"`
+	GeneratedAt time.Time        `json:"generatedAt"`
+	OpenReviews int              `json:"open_reviews"`
+	AtRisk      int              `json:"at_risk"`
+	Breached    int              `json:"breached"`
+	Items       []*DigestItemDTO `json:"items"`
+	Subject     string           `json:"subject"`
+	Text        string           `json:"text"`
+}
+
+type DigestItemDTO struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	CreatedAt       time.Time `json:"createdAt"`
+	AgeHours        int       `json:"age_hours"`
+	SLAStatus       string    `json:"sla_status"`
+	SLADueAt        time.Time `json:"sla_due_at"`
+}
+
+// ToDigestDTO включает текст, который получит пользователь в выбранных каналах.
+func ToDigestDTO(d *domain.Digest) *DigestDTO {
+	if d == nil {
+		return nil
+	}
+	msg := notifier.RenderDigest(d)
+	dto := &DigestDTO{
+		UserID:      d.UserID,
+		GeneratedAt: d.GeneratedAt,
+		OpenReviews: len(d.Items),
+		AtRisk:      d.AtRisk,
+		Breached:    d.Breached,
+		Items:       make([]*DigestItemDTO, 0, len(d.Items)),
+		Subject:     msg.Subject,
+		Text:        msg.Body,
+	}
+	for _, item := range d.Items {
+		dto.Items = append(dto.Items, &DigestItemDTO{
+			PullRequestID:   item.PullRequestID,
+			PullRequestName: item.PullRequestName,
+			AuthorID:        item.AuthorID,
+			CreatedAt:       item.CreatedAt,
+			AgeHours:        int(item.Age / time.Hour),
+			SLAStatus:       string(item.SLAStatus),
+			SLADueAt:        item.DueAt,
+		})
+	}
+	return dto
+}
+
+// ParsePRFilter разбирает параметры GET /pullRequest/list. Даты принимаются в
+// формате RFC 3339 или YYYY-MM-DD (полночь UTC).
+func ParsePRFilter(q url.Values) (*domain.PRFilter, error) {
+	f := &domain.PRFilter{
+		Status:     domain.PRStatus(q.Get("status")),
+		AuthorID:   q.Get("author_id"),
+		ReviewerID: q.Get("reviewer_id"),
+		TeamName:   q.Get("team_name"),
+		Query:      q.Get("q"),
+		Sort:       q.Get("sort"),
+		Order:      q.Get("order"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return nil, domain.ErrInvalidInput
+		}
+		f.Limit = limit
+	}
+
+	dates := []struct {
+		param string
+		dst   **time.Time
+	}{
+		{"created_from", &f.CreatedFrom},
+		{"created_to", &f.CreatedTo},
+		{"merged_from", &f.MergedFrom},
+		{"merged_to", &f.MergedTo},
+	}
+	for _, d := range dates {
+		v := q.Get(d.param)
+		if v == "" {
+			continue
+		}
+		t, err := parseDate(v)
+		if err != nil {
+			return nil, domain.ErrInvalidInput
+		}
+		*d.dst = &t
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodePRCursor(v)
+		if err != nil {
+			return nil, err
+		}
+		f.Cursor = cursor
+	}
+	return f, nil
+}
+
+func parseDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+type PRListResponse struct {
+	PullRequests []*PRDTO `json:"pull_requests"`
+	Total        int      `json:"total"`
+	NextCursor   string   `json:"next_cursor,omitempty"`
+}
+
+func ToPRListResponse(page *domain.PRPage) *PRListResponse {
+	resp := &PRListResponse{
+		PullRequests: make([]*PRDTO, 0, len(page.Items)),
+		Total:        page.Total,
+		NextCursor:   page.NextCursor,
+	}
+	for _, pr := range page.Items {
+		resp.PullRequests = append(resp.PullRequests, ToPRDTO(pr))
+	}
+	return resp
+}
+
+type PRDetailsResponse struct {
+	PR        *PRDTO              `json:"pr"`
+	Reviewers []*ReviewerStateDTO `json:"reviewers"`
+}
+
+type ReviewerStateDTO struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+	Status   string `json:"status"`
+}
+
+func ToPRDetailsResponse(d *domain.PullRequestDetails) *PRDetailsResponse {
+	resp := &PRDetailsResponse{
+		PR:        ToPRDTO(d.PR),
+		Reviewers: make([]*ReviewerStateDTO, 0, len(d.Reviewers)),
+	}
+	for _, r := range d.Reviewers {
+		resp.Reviewers = append(resp.Reviewers, &ReviewerStateDTO{
+			UserID:   r.UserID,
+			Username: r.Username,
+			IsActive: r.IsActive,
+			Status:   string(r.Status),
+		})
+	}
+	return resp
+}
+
+type CreateTokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	UserID string   `json:"user_id,omitempty"`
+}
+
+func (r *CreateTokenRequest) Validate() error {
+	token := domain.APIToken{Name: r.Name, Scopes: r.Scopes}
+	return token.Validate()
+}
+
+type TokenResponse struct {
+	Token *TokenDTO `json:"token"`
+	// Secret возвращается только при создании.
+	Secret string `json:"secret,omitempty"`
+}
+
+type TokenListResponse struct {
+	Tokens []*TokenDTO `json:"tokens"`
+}
+
+type TokenDTO struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	UserID    string     `json:"user_id,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+func ToTokenDTO(t *domain.APIToken) *TokenDTO {
+	return &TokenDTO{
+		ID:        t.ID,
+		Name:      t.Name,
+		Prefix:    t.Prefix,
+		Scopes:    t.Scopes,
+		UserID:    t.UserID,
+		CreatedAt: t.CreatedAt,
+		RevokedAt: t.RevokedAt,
+	}
+}
+
+type TokenAuditResponse struct {
+	TokenID int64                 `json:"token_id"`
+	Entries []*TokenAuditEntryDTO `json:"entries"`
+}
+
+type TokenAuditEntryDTO struct {
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Status    int       `json:"status"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ToTokenAuditResponse(tokenID int64, entries []*domain.TokenAuditEntry) *TokenAuditResponse {
+	resp := &TokenAuditResponse{
+		TokenID: tokenID,
+		Entries: make([]*TokenAuditEntryDTO, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &TokenAuditEntryDTO{
+			Method:    e.Method,
+			Route:     e.Route,
+			Status:    e.Status,
+			RequestID: e.RequestID,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/outbox"
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+const replayPageSize = 500
+
+type EventStreamHandler struct {
+	eventService service.EventService
+	broker       *outbox.Broker
+	heartbeat    time.Duration
+	poll         time.Duration
+	logger       *logger.Logger
+}
+
+func NewEventStreamHandler(
+	eventService service.EventService,
+	broker *outbox.Broker,
+	heartbeat time.Duration,
+	poll time.Duration,
+	log *logger.Logger,
+) *EventStreamHandler {
+	return &EventStreamHandler{
+		eventService: eventService,
+		broker:       broker,
+		heartbeat:    heartbeat,
+		poll:         poll,
+		logger:       log,
+	}
+}
+
+// Stream отдаёт доменные события как Server-Sent Events. С заголовком
+// Last-Event-ID (или параметром last_event_id) сначала досылаются
+// события из outbox_events после указанной позиции журнала (Event.Seq),
+// затем — новые.
+//
+// Новые события приходят из локального broker, а раз в poll поток
+// перечитывает журнал: события, опубликованные релеем другой реплики, видны
+// только там. Если номер события из broker идёт не сразу за последним
+// отправленным, пропущенное тоже досылается из журнала, чтобы порядок Seq
+// сохранялся.
+func (h *EventStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := h.logger.WithContext(ctx)
+
+	userID := r.URL.Query().Get("user_id")
+	teamName := r.URL.Query().Get("team_name")
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	var afterSeq int64
+	resume := lastEventID != ""
+	if resume {
+		var err error
+		afterSeq, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || afterSeq < 0 {
+			response.BadRequest(w, "INVALID_INPUT", "Last-Event-ID must be a non-negative integer")
+			return
+		}
+	}
+
+	filter, err := h.eventService.StreamFilter(ctx, userID, teamName)
+	if err != nil {
+		log.Warn("Failed to build event stream filter",
+			"user_id", userID,
+			"team_name", teamName,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Warn("Failed to reset write deadline for event stream", "error", err)
+	}
+
+	// Подписка до чтения журнала, чтобы не пропустить события между ними.
+	sub := h.broker.Subscribe()
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Error("Event stream does not support flushing", "error", err)
+		return
+	}
+
+	log.Info("Event stream opened",
+		"user_id", userID,
+		"team_name", teamName,
+		"last_event_id", lastEventID,
+	)
+
+	// lastSeq — позиция последнего отправленного события. Без Last-Event-ID
+	// поток начинается с текущего конца журнала.
+	lastSeq := afterSeq
+	if !resume {
+		if lastSeq, err = h.eventService.LatestSeq(ctx); err != nil {
+			log.Error("Failed to get event log position", "error", err)
+			return
+		}
+	}
+
+	catchUp := func() error {
+		for {
+			events, err := h.eventService.EventsAfter(ctx, lastSeq, replayPageSize)
+			if err != nil {
+				log.Error("Failed to read event log", "after_seq", lastSeq, "error", err)
+				return err
+			}
+			for _, e := range events {
+				lastSeq = e.Seq
+				if filter.Matches(e) {
+					if err := writeEvent(w, e); err != nil {
+						return err
+					}
+				}
+			}
+			if len(events) < replayPageSize {
+				return nil
+			}
+		}
+	}
+
+	if resume {
+		if err := catchUp(); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+
+	poll := time.NewTicker(h.poll)
+	defer poll.Stop()
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Event stream closed", "user_id", userID, "team_name", teamName)
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-poll.C:
+			if err := catchUp(); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				log.Warn("Event stream subscriber lagged behind, closing", "user_id", userID, "team_name", teamName)
+				return
+			}
+			if e.Seq <= lastSeq {
+				continue
+			}
+			if e.Seq != lastSeq+1 {
+				if err := catchUp(); err != nil {
+					return
+				}
+				break
+			}
+			lastSeq = e.Seq
+			if !filter.Matches(e) {
+				continue
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e *domain.Event) error {
+	data, err := json.Marshal(ToEventDTO(e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Seq, e.Type, data)
+	return err
+}
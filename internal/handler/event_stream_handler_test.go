@@ -0,0 +1,183 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/handler"
+	"avito/internal/outbox"
+	"avito/pkg/logger"
+)
+
+type fakeEventService struct {
+	mu  sync.Mutex
+	log []*domain.Event
+}
+
+// publish добавляет событие в журнал в обход broker, как релей другой реплики.
+func (f *fakeEventService) publish(e *domain.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.log = append(f.log, e)
+}
+
+func (f *fakeEventService) StreamFilter(_ context.Context, userID, teamName string) (*domain.EventFilter, error) {
+	if userID == "ghost" {
+		return nil, domain.ErrUserNotFound
+	}
+	return &domain.EventFilter{UserID: userID, TeamName: teamName}, nil
+}
+
+func (f *fakeEventService) EventsAfter(_ context.Context, afterSeq int64, limit int) ([]*domain.Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*domain.Event
+	for _, e := range f.log {
+		if e.Seq > afterSeq && len(result) < limit {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeEventService) LatestSeq(context.Context) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.log) == 0 {
+		return 0, nil
+	}
+	return f.log[len(f.log)-1].Seq, nil
+}
+
+// assignedEvent создаёт событие с позицией seq в журнале; id намеренно
+// отличается, потому что поток возобновляется по Seq.
+func assignedEvent(t *testing.T, seq int64, reviewerID string) *domain.Event {
+	t.Helper()
+	e, err := domain.NewEvent(domain.EventReviewerAssigned, "pr-1", domain.ReviewerEventPayload{
+		PullRequestID: "pr-1",
+		ReviewerID:    reviewerID,
+	})
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	e.ID = 100 - seq
+	e.Seq = seq
+	return e
+}
+
+func TestEventStreamHandler_ResumesFromLastEventID(t *testing.T) {
+	svc := &fakeEventService{log: []*domain.Event{
+		assignedEvent(t, 5, "u1"),
+		assignedEvent(t, 6, "u1"),
+		assignedEvent(t, 7, "u2"),
+	}}
+	broker := outbox.NewBroker(8)
+	h := handler.NewEventStreamHandler(svc, broker, time.Minute, time.Minute, logger.NewWithWriter(io.Discard, "error", "json"))
+
+	srv := httptest.NewServer(http.HandlerFunc(h.Stream))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"?user_id=u1", nil)
+	req.Header.Set("Last-Event-ID", "5")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	for broker.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Событие 6 уже отдано из журнала и не должно прийти повторно.
+	live := []*domain.Event{assignedEvent(t, 6, "u1"), assignedEvent(t, 8, "u2"), assignedEvent(t, 9, "u1")}
+	broker.Deliver(live)
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(ids) < 2 && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	if strings.Join(ids, ",") != "6,9" {
+		t.Errorf("streamed ids = %v, want [6 9]", ids)
+	}
+}
+
+func TestEventStreamHandler_PollsLogForOtherReplicas(t *testing.T) {
+	svc := &fakeEventService{log: []*domain.Event{assignedEvent(t, 3, "u1")}}
+	broker := outbox.NewBroker(8)
+	h := handler.NewEventStreamHandler(svc, broker, time.Minute, 10*time.Millisecond,
+		logger.NewWithWriter(io.Discard, "error", "json"))
+
+	srv := httptest.NewServer(http.HandlerFunc(h.Stream))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?user_id=u1")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	for broker.Subscribers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Событие 4 опубликовано другой репликой и есть только в журнале;
+	// событие 6 приходит из broker раньше, чем поток дочитал журнал, и 5
+	// должно быть дослано перед ним.
+	svc.publish(assignedEvent(t, 4, "u1"))
+	svc.publish(assignedEvent(t, 5, "u1"))
+	svc.publish(assignedEvent(t, 6, "u1"))
+	broker.Deliver([]*domain.Event{assignedEvent(t, 6, "u1")})
+
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for len(ids) < 3 && scanner.Scan() {
+		if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+			ids = append(ids, id)
+		}
+	}
+	if strings.Join(ids, ",") != "4,5,6" {
+		t.Errorf("streamed ids = %v, want [4 5 6]", ids)
+	}
+}
+
+func TestEventStreamHandler_InvalidRequests(t *testing.T) {
+	h := handler.NewEventStreamHandler(&fakeEventService{}, outbox.NewBroker(1), time.Minute, time.Minute,
+		logger.NewWithWriter(io.Discard, "error", "json"))
+
+	tests := []struct {
+		name       string
+		url        string
+		lastID     string
+		wantStatus int
+	}{
+		{"Bad Last-Event-ID", "/events/stream", "abc", http.StatusBadRequest},
+		{"Unknown user", "/events/stream?user_id=ghost", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			rec := httptest.NewRecorder()
+			h.Stream(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
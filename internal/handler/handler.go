@@ -1,28 +1,27 @@
 package handler
 
 import (
-	"log/slog"
-
 	"avito/internal/service"
+	"avito/pkg/logger"
 )
 
 type Handler struct {
 	teamService service.TeamService
 	userService service.UserService
 	prService   service.PRService
-	logger      *slog.Logger
+	logger      *logger.Logger
 }
 
 func NewHandler(
 	teamService service.TeamService,
 	userService service.UserService,
 	prService service.PRService,
-	logger *slog.Logger,
+	log *logger.Logger,
 ) *Handler {
 	return &Handler{
 		teamService: teamService,
 		userService: userService,
 		prService:   prService,
-		logger:      logger,
+		logger:      log,
 	}
 }
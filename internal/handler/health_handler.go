@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"avito/internal/health"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type ReadinessChecker interface {
+	Check(ctx context.Context) health.Report
+}
+
+type HealthHandler struct {
+	checker ReadinessChecker
+	logger  *logger.Logger
+}
+
+func NewHealthHandler(checker ReadinessChecker, log *logger.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  log,
+	}
+}
+
+// Live отвечает, пока процесс способен обслуживать HTTP; зависимости не
+// проверяются, чтобы сбой БД не приводил к перезапуску экземпляров.
+func (h *HealthHandler) Live(w http.ResponseWriter, _ *http.Request) {
+	response.OK(w, map[string]string{"status": "ok"})
+}
+
+// Ready возвращает 503, если какая-то зависимость недоступна или сервис
+// завершается.
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := h.checker.Check(r.Context())
+	if !report.Ready {
+		if !report.ShuttingDown {
+			h.logger.WithContext(r.Context()).Warn("Readiness check failed", "components", report.Components)
+		}
+		response.JSON(w, http.StatusServiceUnavailable, report)
+		return
+	}
+	response.OK(w, report)
+}
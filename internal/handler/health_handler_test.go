@@ -0,0 +1,53 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"avito/internal/handler"
+	"avito/internal/health"
+	"avito/pkg/logger"
+)
+
+func TestHealthHandlerReady(t *testing.T) {
+	hb := &health.Heartbeat{}
+	checker := health.NewChecker(time.Second)
+	checker.Add("task_worker", health.HeartbeatCheck(hb, time.Minute))
+	h := handler.NewHealthHandler(checker, logger.NewWithWriter(io.Discard, "error", "json"))
+
+	ready := func() (int, health.Report) {
+		rec := httptest.NewRecorder()
+		h.Ready(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report health.Report
+		if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+			t.Fatalf("failed to decode report: %v", err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := ready()
+	if code != http.StatusServiceUnavailable || report.Components["task_worker"].Status != health.StatusDown {
+		t.Fatalf("before heartbeat: status %d, report %+v", code, report)
+	}
+
+	hb.Beat()
+	if code, report := ready(); code != http.StatusOK || !report.Ready {
+		t.Fatalf("after heartbeat: status %d, report %+v", code, report)
+	}
+
+	checker.SetShuttingDown()
+	if code, report := ready(); code != http.StatusServiceUnavailable || !report.ShuttingDown {
+		t.Errorf("shutting down: status %d, report %+v", code, report)
+	}
+
+	// Liveness не зависит от готовности.
+	rec := httptest.NewRecorder()
+	h.Live(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("livez status = %d", rec.Code)
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"avito/internal/domain"
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+// maxIdempotentBodySize ограничивает тело запроса, которое читается целиком
+// для отпечатка.
+const maxIdempotentBodySize = 1 << 20
+
+type IdempotencyMiddleware struct {
+	idempotencyService service.IdempotencyService
+	logger             *logger.Logger
+}
+
+func NewIdempotencyMiddleware(idempotencyService service.IdempotencyService, log *logger.Logger) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		idempotencyService: idempotencyService,
+		logger:             log,
+	}
+}
+
+// Handler обрабатывает POST-запросы с заголовком Idempotency-Key: первый
+// запрос выполняется и его ответ сохраняется, повтор с тем же телом получает
+// сохранённый ответ с заголовком Idempotent-Replayed, а повтор с другим
+// телом — 422. Ответы 5xx, 401 и 403 не сохраняются, чтобы запрос можно
+// было повторить: middleware стоит до проверки scope на маршруте, и отказ в
+// доступе не должен закрепляться за ключом. Если хендлер паникует, ключ
+// освобождается до передачи паники дальше.
+func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if r.Method != http.MethodPost || key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > domain.MaxIdempotencyKeyLength {
+			response.BadRequest(w, "INVALID_INPUT", "Idempotency-Key is too long")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			m.logger.WithContext(r.Context()).Warn("Failed to read request body", "error", err)
+			response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			response.BadRequest(w, "INVALID_INPUT", "request body is too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		// Ключи разных клиентов не пересекаются.
+		if principal := domain.PrincipalFromContext(ctx); principal != nil {
+			key = principal.Subject() + ":" + key
+		}
+		fingerprint := domain.RequestFingerprint(r.Method, r.URL.Path, body)
+		reservation, err := m.idempotencyService.Begin(ctx, key, fingerprint)
+		if err != nil {
+			m.logger.WithContext(r.Context()).Warn("Idempotency key rejected", "key", key, "path", r.URL.Path, "error", err)
+			response.HandleError(w, err)
+			return
+		}
+		if reservation.Completed {
+			m.replay(w, r, reservation)
+			return
+		}
+
+		// Запрос мог прерваться по таймауту, а результат всё равно нужно записать.
+		release := func() {
+			if err := m.idempotencyService.Release(context.WithoutCancel(ctx), reservation); err != nil {
+				m.logOwnershipError(r, "Failed to release idempotency key", key, err)
+			}
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				release()
+				panic(p)
+			}
+		}()
+
+		var buf bytes.Buffer
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		ww.Tee(&buf)
+		next.ServeHTTP(ww, r)
+
+		ctx = context.WithoutCancel(ctx)
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if !storableStatus(status) {
+			release()
+			return
+		}
+		reservation.StatusCode = status
+		reservation.ContentType = ww.Header().Get("Content-Type")
+		reservation.Body = buf.Bytes()
+		if err := m.idempotencyService.Complete(ctx, reservation); err != nil {
+			m.logOwnershipError(r, "Failed to store idempotent response", key, err)
+		}
+	})
+}
+
+// logOwnershipError пишет ошибку Complete или Release. Потерю аренды —
+// запрос шёл дольше IDEMPOTENCY_LOCK_LEASE, и ключ занял повтор — пишем
+// предупреждением: запись повтора остаётся нетронутой.
+func (m *IdempotencyMiddleware) logOwnershipError(r *http.Request, msg, key string, err error) {
+	log := m.logger.WithContext(r.Context())
+	if errors.Is(err, domain.ErrIdempotencyLeaseLost) {
+		log.Warn(msg, "key", key, "error", err)
+		return
+	}
+	log.Error(msg, "key", key, "error", err)
+}
+
+// storableStatus сообщает, можно ли закрепить ответ за ключом.
+func storableStatus(status int) bool {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return false
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return false
+	default:
+		return true
+	}
+}
+
+func (m *IdempotencyMiddleware) replay(w http.ResponseWriter, r *http.Request, rec *domain.IdempotencyRecord) {
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(rec.StatusCode)
+	if _, err := w.Write(rec.Body); err != nil {
+		m.logger.WithContext(r.Context()).Error("Failed to write replayed response", "key", rec.Key, "error", err)
+	}
+}
@@ -0,0 +1,176 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/handler"
+	"avito/pkg/logger"
+)
+
+type fakeIdempotencyService struct {
+	mu      sync.Mutex
+	records map[string]*domain.IdempotencyRecord
+	tokens  int
+}
+
+// takeOver имитирует повтор, занявший ключ после истечения аренды.
+func (f *fakeIdempotencyService) takeOver(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[key].Token = "retry"
+}
+
+func (f *fakeIdempotencyService) Begin(_ context.Context, key, fingerprint string) (*domain.IdempotencyRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rec, ok := f.records[key]
+	if !ok {
+		f.tokens++
+		token := fmt.Sprint(f.tokens)
+		f.records[key] = &domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint, Token: token}
+		return &domain.IdempotencyRecord{Key: key, Fingerprint: fingerprint, Token: token}, nil
+	}
+	if rec.Fingerprint != fingerprint {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if !rec.Completed {
+		return nil, domain.ErrIdempotencyInProgress
+	}
+	return rec, nil
+}
+
+func (f *fakeIdempotencyService) Complete(_ context.Context, rec *domain.IdempotencyRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.records[rec.Key]
+	if stored == nil || stored.Token != rec.Token {
+		return domain.ErrIdempotencyLeaseLost
+	}
+	stored.Completed = true
+	stored.StatusCode = rec.StatusCode
+	stored.ContentType = rec.ContentType
+	stored.Body = append([]byte(nil), rec.Body...)
+	return nil
+}
+
+func (f *fakeIdempotencyService) Release(_ context.Context, rec *domain.IdempotencyRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if stored := f.records[rec.Key]; stored == nil || stored.Token != rec.Token {
+		return domain.ErrIdempotencyLeaseLost
+	}
+	delete(f.records, rec.Key)
+	return nil
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	svc := &fakeIdempotencyService{records: map[string]*domain.IdempotencyRecord{}}
+	mw := handler.NewIdempotencyMiddleware(svc, logger.NewWithWriter(io.Discard, "error", "json"))
+
+	calls := 0
+	failNext := 0
+	panicNext := false
+	takeOverNext := ""
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if takeOverNext != "" {
+			svc.takeOver(takeOverNext)
+			takeOverNext = ""
+		}
+		if panicNext {
+			panicNext = false
+			panic("boom")
+		}
+		if failNext != 0 {
+			w.WriteHeader(failNext)
+			failNext = 0
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	})
+	h := mw.Handler(next)
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/create", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := do("k1", `{"id":1}`)
+	if first.Code != http.StatusCreated || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("first: status = %d, replayed = %q", first.Code, first.Header().Get("Idempotent-Replayed"))
+	}
+
+	replayed := do("k1", `{"id":1}`)
+	if replayed.Code != http.StatusCreated || replayed.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("replay: status = %d, replayed = %q", replayed.Code, replayed.Header().Get("Idempotent-Replayed"))
+	}
+	if replayed.Body.String() != first.Body.String() {
+		t.Errorf("replay body = %q, want %q", replayed.Body.String(), first.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+
+	if rec := do("k1", `{"id":2}`); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("reuse with different body: status = %d, want 422", rec.Code)
+	}
+
+	for _, status := range []int{http.StatusInternalServerError, http.StatusUnauthorized, http.StatusForbidden} {
+		failNext = status
+		if rec := do("k2", `{}`); rec.Code != status {
+			t.Fatalf("failing request: status = %d, want %d", rec.Code, status)
+		}
+	}
+	if rec := do("k2", `{}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry after 5xx/401/403: status = %d, want fresh 201", rec.Code)
+	}
+
+	panicNext = true
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic should propagate to the recoverer")
+			}
+		}()
+		do("k3", `{}`)
+	}()
+	if rec := do("k3", `{}`); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("retry after panic: status = %d, want fresh 201", rec.Code)
+	}
+
+	// Аренда истекла посреди запроса, и ключ занял повтор: ответ первого
+	// запроса не должен попасть в чужую запись.
+	takeOverNext = "k4"
+	do("k4", `{}`)
+	if stored := svc.records["k4"]; stored.Completed || stored.Token != "retry" {
+		t.Errorf("record after lost lease = %+v, want untouched retry reservation", stored)
+	}
+	failNext = http.StatusInternalServerError
+	takeOverNext = "k5"
+	do("k5", `{}`)
+	if _, ok := svc.records["k5"]; !ok {
+		t.Error("release after lost lease must not delete the retry reservation")
+	}
+
+	do("", `{}`)
+	do("", `{}`)
+	if calls != 11 {
+		t.Errorf("handler calls = %d, want 11 (requests without key are not deduplicated)", calls)
+	}
+}
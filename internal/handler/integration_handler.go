@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"avito/internal/domain"
+	"avito/internal/integration/github"
+	"avito/internal/integration/gitlab"
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+const maxWebhookBodyBytes = 1 << 20
+
+type IntegrationHandler struct {
+	accountService service.AccountService
+	github         *github.Processor
+	githubSecret   []byte
+	gitlab         *gitlab.Processor
+	gitlabSecret   []byte
+	logger         *logger.Logger
+}
+
+func NewIntegrationHandler(
+	accountService service.AccountService,
+	prService service.PRService,
+	githubSecret string,
+	gitlabSecret string,
+	log *logger.Logger,
+) *IntegrationHandler {
+	return &IntegrationHandler{
+		accountService: accountService,
+		github:         github.NewProcessor(prService, accountService),
+		githubSecret:   []byte(githubSecret),
+		gitlab:         gitlab.NewProcessor(prService, accountService),
+		gitlabSecret:   []byte(gitlabSecret),
+		logger:         log,
+	}
+}
+
+func (h *IntegrationHandler) LinkAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req LinkAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	account, err := h.accountService.LinkAccount(ctx, &domain.ExternalAccount{
+		Provider:   req.Provider,
+		Login:      req.Login,
+		ExternalID: req.ExternalID,
+		UserID:     req.UserID,
+	})
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to link account",
+			"provider", req.Provider,
+			"login", req.Login,
+			"user_id", req.UserID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Account linked successfully",
+		"provider", account.Provider,
+		"login", account.Login,
+		"user_id", account.UserID,
+	)
+
+	response.OK(w, ExternalAccountResponse{Account: ToExternalAccountDTO(account)})
+}
+
+func (h *IntegrationHandler) UnlinkAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req UnlinkAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	if err := h.accountService.UnlinkAccount(ctx, req.Provider, req.Login); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to unlink account",
+			"provider", req.Provider,
+			"login", req.Login,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Account unlinked successfully",
+		"provider", req.Provider,
+		"login", req.Login,
+	)
+
+	response.NoContent(w)
+}
+
+func (h *IntegrationHandler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to read webhook body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if !github.VerifySignature(h.githubSecret, body, r.Header.Get(github.SignatureHeader)) {
+		h.logger.WithContext(r.Context()).Warn("GitHub webhook signature mismatch",
+			"delivery", r.Header.Get("X-GitHub-Delivery"),
+		)
+		response.HandleError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	event := r.Header.Get(github.EventHeader)
+	result, err := h.github.Handle(ctx, event, body)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to handle GitHub webhook",
+			"event", event,
+			"delivery", r.Header.Get("X-GitHub-Delivery"),
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("GitHub webhook processed",
+		"event", event,
+		"outcome", result.Outcome,
+		"pr_id", result.PullRequestID,
+		"reason", result.Reason,
+	)
+
+	response.OK(w, result)
+}
+
+func (h *IntegrationHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if !gitlab.VerifyToken(h.gitlabSecret, r.Header.Get(gitlab.TokenHeader)) {
+		h.logger.WithContext(r.Context()).Warn("GitLab webhook token mismatch",
+			"event", r.Header.Get(gitlab.EventHeader),
+		)
+		response.HandleError(w, domain.ErrUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to read webhook body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	event := r.Header.Get(gitlab.EventHeader)
+	result, err := h.gitlab.Handle(ctx, event, body)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to handle GitLab webhook",
+			"event", event,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("GitLab webhook processed",
+		"event", event,
+		"outcome", result.Outcome,
+		"pr_id", result.PullRequestID,
+		"reason", result.Reason,
+	)
+
+	response.OK(w, result)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type NotificationHandler struct {
+	notificationService service.NotificationService
+	digestService       service.DigestService
+	logger              *logger.Logger
+}
+
+func NewNotificationHandler(
+	notificationService service.NotificationService,
+	digestService service.DigestService,
+	log *logger.Logger,
+) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		digestService:       digestService,
+		logger:              log,
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := r.PathValue("user_id")
+	if userID == "" {
+		h.logger.WithContext(r.Context()).Warn("Missing user_id parameter")
+		response.BadRequest(w, "INVALID_INPUT", "user_id is required")
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(ctx, userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get notification preferences",
+			"user_id", userID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, NotificationPreferencesResponse{Preferences: ToNotificationPreferencesDTO(prefs)})
+}
+
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := r.PathValue("user_id")
+	if userID == "" {
+		h.logger.WithContext(r.Context()).Warn("Missing user_id parameter")
+		response.BadRequest(w, "INVALID_INPUT", "user_id is required")
+		return
+	}
+
+	var req NotificationPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	prefs, err := req.ToDomain(userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	prefs, err = h.notificationService.UpdatePreferences(ctx, prefs)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to update notification preferences",
+			"user_id", userID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Notification preferences updated",
+		"user_id", prefs.UserID,
+		"channels", prefs.Channels,
+		"mode", prefs.Mode,
+	)
+
+	response.OK(w, NotificationPreferencesResponse{Preferences: ToNotificationPreferencesDTO(prefs)})
+}
+
+// GetDigest показывает дайджест открытых ревью в том виде, в каком он будет
+// отправлен, без постановки в очередь.
+func (h *NotificationHandler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID := r.PathValue("user_id")
+	if userID == "" {
+		h.logger.WithContext(r.Context()).Warn("Missing user_id parameter")
+		response.BadRequest(w, "INVALID_INPUT", "user_id is required")
+		return
+	}
+
+	digest, err := h.digestService.BuildDigest(ctx, userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to build digest",
+			"user_id", userID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, DigestResponse{Digest: ToDigestDTO(digest)})
+}
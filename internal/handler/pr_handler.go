@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"net/http"
+	"net/url"
 
 	"avito/pkg/response"
 )
@@ -12,20 +13,20 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 
 	var req CreatePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode request body", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid request data", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
 		response.HandleError(w, err)
 		return
 	}
 
 	pr, err := h.prService.CreatePR(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID)
 	if err != nil {
-		h.logger.Error("Failed to create PR",
+		h.logger.WithContext(r.Context()).Error("Failed to create PR",
 			"pr_id", req.PullRequestID,
 			"author_id", req.AuthorID,
 			"error", err,
@@ -34,7 +35,7 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("PR created successfully",
+	h.logger.WithContext(r.Context()).Info("PR created successfully",
 		"pr_id", pr.PullRequestID,
 		"author_id", pr.AuthorID,
 		"reviewers_count", len(pr.AssignedReviewers),
@@ -52,20 +53,27 @@ func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 
 	var req MergePRRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode request body", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid request data", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
 		response.HandleError(w, err)
 		return
 	}
 
+	ctx, err := withIfMatch(r)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid If-Match header", "value", r.Header.Get("If-Match"))
+		response.BadRequest(w, "INVALID_INPUT", "invalid If-Match header")
+		return
+	}
+
 	pr, err := h.prService.MergePR(ctx, req.PullRequestID)
 	if err != nil {
-		h.logger.Error("Failed to merge PR",
+		h.logger.WithContext(r.Context()).Error("Failed to merge PR",
 			"pr_id", req.PullRequestID,
 			"error", err,
 		)
@@ -73,7 +81,7 @@ func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("PR merged successfully",
+	h.logger.WithContext(r.Context()).Info("PR merged successfully",
 		"pr_id", pr.PullRequestID,
 		"status", pr.Status,
 	)
@@ -90,20 +98,27 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 
 	var req ReassignReviewerRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode request body", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid request data", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
 		response.HandleError(w, err)
 		return
 	}
 
+	ctx, err := withIfMatch(r)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid If-Match header", "value", r.Header.Get("If-Match"))
+		response.BadRequest(w, "INVALID_INPUT", "invalid If-Match header")
+		return
+	}
+
 	pr, newReviewerID, err := h.prService.ReassignReviewer(ctx, req.PullRequestID, req.OldUserID)
 	if err != nil {
-		h.logger.Error("Failed to reassign reviewer",
+		h.logger.WithContext(r.Context()).Error("Failed to reassign reviewer",
 			"pr_id", req.PullRequestID,
 			"old_user_id", req.OldUserID,
 			"error", err,
@@ -112,7 +127,7 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("Reviewer reassigned successfully",
+	h.logger.WithContext(r.Context()).Info("Reviewer reassigned successfully",
 		"pr_id", pr.PullRequestID,
 		"old_reviewer", req.OldUserID,
 		"new_reviewer", newReviewerID,
@@ -125,3 +140,84 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 
 	response.OK(w, resp)
 }
+
+func (h *Handler) ExplainAssignment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		h.logger.WithContext(r.Context()).Warn("Missing pull_request_id parameter")
+		response.BadRequest(w, "INVALID_INPUT", "pull_request_id parameter is required")
+		return
+	}
+
+	explanation, err := h.prService.ExplainAssignment(ctx, prID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to explain assignment",
+			"pr_id", prID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Assignment explained",
+		"pr_id", prID,
+		"decisions_count", len(explanation.Decisions),
+	)
+
+	response.OK(w, ToExplainResponse(explanation))
+}
+
+func (h *Handler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := ParsePRFilter(r.URL.Query())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid list parameters", "query", r.URL.RawQuery, "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid list parameters")
+		return
+	}
+
+	page, err := h.prService.ListPRs(ctx, filter)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list pull requests",
+			"query", r.URL.RawQuery,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, ToPRListResponse(page))
+}
+
+func (h *Handler) GetPR(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	prID, err := url.PathUnescape(r.PathValue("pull_request_id"))
+	if err != nil || prID == "" {
+		h.logger.WithContext(r.Context()).Warn("Invalid pull_request_id parameter", "value", r.PathValue("pull_request_id"))
+		response.BadRequest(w, "INVALID_INPUT", "pull_request_id is required")
+		return
+	}
+
+	details, err := h.prService.GetPR(ctx, prID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get PR",
+			"pr_id", prID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	etag := details.ETag()
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		response.NotModified(w)
+		return
+	}
+
+	response.OK(w, ToPRDetailsResponse(details))
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"avito/internal/domain"
+	"avito/internal/service"
+)
+
+// withIfMatch переносит версию из заголовка If-Match в контекст сервиса.
+// Отсутствующий заголовок и "*" не ограничивают изменение.
+func withIfMatch(r *http.Request) (context.Context, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return r.Context(), nil
+	}
+	version, err := domain.ParseETagVersion(header)
+	if err != nil {
+		return nil, err
+	}
+	return service.WithIfMatch(r.Context(), version), nil
+}
+
+// etagMatches проверяет, есть ли etag в списке из If-None-Match.
+// Сравнение слабое, как требует RFC 9110 для If-None-Match.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito/internal/domain"
+	"avito/internal/ratelimit"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type RateLimitMiddleware struct {
+	limiter *ratelimit.Limiter
+	def     ratelimit.Limit
+	routes  map[string]ratelimit.Limit
+	enabled bool
+	logger  *logger.Logger
+}
+
+// NewRateLimitMiddleware создаёт ограничение частоты запросов. routes задаёт
+// лимиты по ключу "METHOD /pattern" (шаблон маршрута chi, например
+// "GET /users/{user_id}/digest"), остальные маршруты делят лимит def.
+func NewRateLimitMiddleware(
+	limiter *ratelimit.Limiter,
+	def ratelimit.Limit,
+	routes map[string]ratelimit.Limit,
+	enabled bool,
+	log *logger.Logger,
+) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+		def:     def,
+		routes:  routes,
+		enabled: enabled,
+		logger:  log,
+	}
+}
+
+// Handler считает запросы по principal, а без него — по IP клиента, поэтому
+// должен стоять после аутентификации. Ответ содержит заголовки
+// X-RateLimit-*, при превышении лимита — 429 с Retry-After.
+func (m *RateLimitMiddleware) Handler(next http.Handler) http.Handler {
+	if !m.enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := clientKey(r)
+		route := r.Method + " " + limitRoute(r)
+		limit, ok := m.routes[route]
+		if !ok {
+			route, limit = "*", m.def
+		}
+
+		if !allow(w, m.limiter, client+" "+route, limit) {
+			m.logger.WithContext(r.Context()).Warn("Rate limit exceeded", "client", client, "route", route)
+			response.TooManyRequests(w, "RATE_LIMITED", "too many requests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IPRateLimitMiddleware ограничивает запросы по IP до аутентификации, чтобы
+// перебор токенов и запросы с невалидными токенами не нагружали БД.
+type IPRateLimitMiddleware struct {
+	limiter *ratelimit.Limiter
+	limit   ratelimit.Limit
+	enabled bool
+	logger  *logger.Logger
+}
+
+func NewIPRateLimitMiddleware(limiter *ratelimit.Limiter, limit ratelimit.Limit, enabled bool, log *logger.Logger) *IPRateLimitMiddleware {
+	return &IPRateLimitMiddleware{
+		limiter: limiter,
+		limit:   limit,
+		enabled: enabled,
+		logger:  log,
+	}
+}
+
+// Handler ставится перед аутентификацией; лимит общий для всех маршрутов.
+func (m *IPRateLimitMiddleware) Handler(next http.Handler) http.Handler {
+	if !m.enabled {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := ipKey(r)
+		if !allow(w, m.limiter, client, m.limit) {
+			m.logger.WithContext(r.Context()).Warn("IP rate limit exceeded", "client", client)
+			response.TooManyRequests(w, "RATE_LIMITED", "too many requests")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow расходует токен из бакета key и пишет заголовки X-RateLimit-*.
+func allow(w http.ResponseWriter, limiter *ratelimit.Limiter, key string, limit ratelimit.Limit) bool {
+	res := limiter.Allow(key, limit)
+	h := w.Header()
+	h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(res.RetryAfter))))
+	}
+	return res.Allowed
+}
+
+// limitRoute возвращает шаблон маршрута chi, чтобы ключ бакета не зависел
+// от параметров пути. Middleware группы выполняется до разбора вложенных
+// роутеров, и RoutePattern там ещё оканчивается на "/*", поэтому шаблон
+// ищется по дереву корневого роутера. Для ненайденных маршрутов — "*".
+func limitRoute(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "*"
+	}
+	pattern := rctx.RoutePattern()
+	if strings.HasSuffix(pattern, "/*") && rctx.Routes != nil {
+		pattern = rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+	}
+	if pattern == "" {
+		return "*"
+	}
+	return pattern
+}
+
+func clientKey(r *http.Request) string {
+	if p := domain.PrincipalFromContext(r.Context()); p != nil {
+		return p.Subject()
+	}
+	return ipKey(r)
+}
+
+func ipKey(r *http.Request) string {
+	// RemoteAddr уже заменён на адрес клиента middleware.RealIP и может
+	// быть без порта.
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
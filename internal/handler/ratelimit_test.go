@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"avito/internal/domain"
+	"avito/internal/handler"
+	"avito/internal/ratelimit"
+	"avito/pkg/logger"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	m := handler.NewRateLimitMiddleware(
+		ratelimit.New(),
+		ratelimit.Limit{RPS: 0.001, Burst: 2},
+		map[string]ratelimit.Limit{
+			"POST /pullRequest/create":    {RPS: 0.001, Burst: 1},
+			"GET /users/{user_id}/digest": {RPS: 0.001, Burst: 1},
+		},
+		true,
+		logger.NewWithWriter(io.Discard, "error", "json"),
+	)
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	h := chi.NewRouter()
+	h.Group(func(r chi.Router) {
+		r.Use(m.Handler)
+		r.Post("/pullRequest/create", ok)
+		r.Get("/team/get", ok)
+		r.Route("/users", func(r chi.Router) {
+			r.Get("/{user_id}/digest", ok)
+		})
+	})
+
+	do := func(method, path string, p *domain.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:5555"
+		if p != nil {
+			req = req.WithContext(domain.WithPrincipal(context.Background(), p))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	ci := &domain.Principal{TokenID: 1}
+
+	if rec := do(http.MethodPost, "/pullRequest/create", ci); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "1" {
+		t.Fatalf("first create: status %d, headers %v", rec.Code, rec.Header())
+	}
+	rec := do(http.MethodPost, "/pullRequest/create", ci)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second create: status %d, want 429", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("429 headers = %v", rec.Header())
+	}
+
+	// Лимит маршрута не расходует общий бакет клиента.
+	if rec := do(http.MethodGet, "/team/get", ci); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("default route: status %d, headers %v", rec.Code, rec.Header())
+	}
+	// Другой токен и запрос без токена (по IP) считаются отдельно.
+	if rec := do(http.MethodPost, "/pullRequest/create", &domain.Principal{TokenID: 2}); rec.Code != http.StatusOK {
+		t.Errorf("other token: status %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/pullRequest/create", nil); rec.Code != http.StatusOK {
+		t.Errorf("anonymous: status %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/pullRequest/create", nil); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("anonymous repeat: status %d, want 429", rec.Code)
+	}
+
+	// Бакет определяется шаблоном маршрута, а не конкретным путём.
+	if rec := do(http.MethodGet, "/users/u1/digest", ci); rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "1" {
+		t.Errorf("first digest: status %d, headers %v", rec.Code, rec.Header())
+	}
+	if rec := do(http.MethodGet, "/users/u2/digest", ci); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("digest of another user: status %d, want 429 (same route pattern)", rec.Code)
+	}
+}
+
+func TestIPRateLimitMiddleware(t *testing.T) {
+	m := handler.NewIPRateLimitMiddleware(
+		ratelimit.New(),
+		ratelimit.Limit{RPS: 0.001, Burst: 2},
+		true,
+		logger.NewWithWriter(io.Discard, "error", "json"),
+	)
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	do := func(remoteAddr, path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Лимит общий для всех маршрутов и действует и на неаутентифицированные запросы.
+	if code := do("10.0.0.1:1", "/team/get"); code != http.StatusUnauthorized {
+		t.Fatalf("first: status %d", code)
+	}
+	if code := do("10.0.0.1:2", "/users/u1"); code != http.StatusUnauthorized {
+		t.Fatalf("second: status %d", code)
+	}
+	if code := do("10.0.0.1:3", "/pullRequest/list"); code != http.StatusTooManyRequests {
+		t.Errorf("third: status %d, want 429", code)
+	}
+	if code := do("10.0.0.2:1", "/team/get"); code != http.StatusUnauthorized {
+		t.Errorf("other IP: status %d", code)
+	}
+}
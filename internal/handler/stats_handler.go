@@ -9,11 +9,11 @@ import (
 )
 
 type StatsHandler struct {
-	statsService *service.StatsService
+	statsService service.StatsProvider
 	logger       *logger.Logger
 }
 
-func NewStatsHandler(statsService *service.StatsService, log *logger.Logger) *StatsHandler {
+func NewStatsHandler(statsService service.StatsProvider, log *logger.Logger) *StatsHandler {
 	return &StatsHandler{
 		statsService: statsService,
 		logger:       log,
@@ -22,11 +22,11 @@ func NewStatsHandler(statsService *service.StatsService, log *logger.Logger) *St
 
 func (h *StatsHandler) GetGlobalStats(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	h.logger.Info("getting global stats")
+	h.logger.WithContext(r.Context()).Info("getting global stats")
 
 	stats, err := h.statsService.GetGlobalStats(ctx)
 	if err != nil {
-		h.logger.Error("failed to get global stats", "error", err.Error())
+		h.logger.WithContext(r.Context()).Error("failed to get global stats", "error", err.Error())
 		response.HandleError(w, err)
 		return
 	}
@@ -40,7 +40,7 @@ func (h *StatsHandler) GetTeamStats(w http.ResponseWriter, r *http.Request) {
 		response.BadRequest(w, "INVALID_INPUT", "team_name is required")
 		return
 	}
-	h.logger.Info("getting team stats", "team_name", teamName)
+	h.logger.WithContext(r.Context()).Info("getting team stats", "team_name", teamName)
 	response.OK(w, "GetTeamStats not implemented")
 }
 
@@ -50,7 +50,7 @@ func (h *StatsHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 		response.BadRequest(w, "INVALID_INPUT", "user_id is required")
 		return
 	}
-	h.logger.Info("getting user stats", "user_id", userID)
+	h.logger.WithContext(r.Context()).Info("getting user stats", "user_id", userID)
 	response.OK(w, "GetUserStats not implemented")
 }
 
@@ -60,11 +60,31 @@ func (h *StatsHandler) GetWorkloadStats(w http.ResponseWriter, r *http.Request)
 		response.BadRequest(w, "INVALID_INPUT", "team_name is required")
 		return
 	}
-	h.logger.Info("getting workload stats", "team_name", teamName)
+	h.logger.WithContext(r.Context()).Info("getting workload stats", "team_name", teamName)
 	response.OK(w, "GetWorkloadStats not implemented")
 }
 
 func (h *StatsHandler) GetHealthStats(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("getting health stats")
+	h.logger.WithContext(r.Context()).Info("getting health stats")
 	response.OK(w, "GetHealthStats not implemented")
 }
+
+func (h *StatsHandler) GetPairingStats(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		response.BadRequest(w, "INVALID_INPUT", "team_name is required")
+		return
+	}
+	h.logger.WithContext(r.Context()).Info("getting pairing stats", "team_name", teamName)
+
+	matrix, err := h.statsService.GetPairingMatrix(ctx, teamName)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("failed to get pairing stats", "team_name", teamName, "error", err.Error())
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, matrix)
+}
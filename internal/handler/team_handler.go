@@ -13,13 +13,13 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 
 	var req CreateTeamRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode request body", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid request data", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
 		response.HandleError(w, err)
 		return
 	}
@@ -27,20 +27,22 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	members := make([]*domain.TeamMember, 0, len(req.Members))
 	for _, m := range req.Members {
 		members = append(members, &domain.TeamMember{
-			UserID:   m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			UserID:    m.UserID,
+			Username:  m.Username,
+			IsActive:  m.IsActive,
+			Seniority: domain.Seniority(m.Seniority),
 		})
 	}
 
 	team := &domain.Team{
-		Name:    req.TeamName,
-		Members: members,
+		Name:         req.TeamName,
+		ReviewerRule: domain.ReviewerRule(req.ReviewerRule),
+		Members:      members,
 	}
 
 	createdTeam, err := h.teamService.CreateTeamWithMembers(ctx, team)
 	if err != nil {
-		h.logger.Error("Failed to create team",
+		h.logger.WithContext(r.Context()).Error("Failed to create team",
 			"team_name", req.TeamName,
 			"error", err,
 		)
@@ -48,7 +50,7 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("Team created successfully",
+	h.logger.WithContext(r.Context()).Info("Team created successfully",
 		"team_id", createdTeam.ID,
 		"team_name", createdTeam.Name,
 		"members_count", len(createdTeam.Members),
@@ -61,19 +63,64 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	response.Created(w, resp)
 }
 
+func (h *Handler) AddTeamMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req AddTeamMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	members := make([]*domain.TeamMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		members = append(members, &domain.TeamMember{
+			UserID:    m.UserID,
+			Username:  m.Username,
+			IsActive:  m.IsActive,
+			Seniority: domain.Seniority(m.Seniority),
+		})
+	}
+
+	team, err := h.teamService.AddMembers(ctx, req.TeamName, members)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to add team members",
+			"team_name", req.TeamName,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Team members added",
+		"team_id", team.ID,
+		"team_name", team.Name,
+		"added_count", len(members),
+	)
+
+	response.OK(w, TeamResponse{Team: ToTeamDTO(team)})
+}
+
 func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
-		h.logger.Warn("Missing team_name parameter")
+		h.logger.WithContext(r.Context()).Warn("Missing team_name parameter")
 		response.BadRequest(w, "INVALID_INPUT", "team_name parameter is required")
 		return
 	}
 
 	team, err := h.teamService.GetTeamByName(ctx, teamName)
 	if err != nil {
-		h.logger.Error("Failed to get team",
+		h.logger.WithContext(r.Context()).Error("Failed to get team",
 			"team_name", teamName,
 			"error", err,
 		)
@@ -81,7 +128,7 @@ func (h *Handler) GetTeam(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("Team retrieved successfully",
+	h.logger.WithContext(r.Context()).Info("Team retrieved successfully",
 		"team_id", team.ID,
 		"team_name", team.Name,
 		"members_count", len(team.Members),
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type TokenHandler struct {
+	tokenService service.TokenService
+	logger       *logger.Logger
+}
+
+func NewTokenHandler(tokenService service.TokenService, log *logger.Logger) *TokenHandler {
+	return &TokenHandler{
+		tokenService: tokenService,
+		logger:       log,
+	}
+}
+
+func (h *TokenHandler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	token, secret, err := h.tokenService.CreateToken(ctx, req.Name, req.Scopes, req.UserID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to create API token", "name", req.Name, "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("API token created",
+		"token_id", token.ID,
+		"name", token.Name,
+		"scopes", token.Scopes,
+		"user_id", token.UserID,
+	)
+
+	response.Created(w, TokenResponse{Token: ToTokenDTO(token), Secret: secret})
+}
+
+func (h *TokenHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokens, err := h.tokenService.ListTokens(ctx)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list API tokens", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	resp := TokenListResponse{Tokens: make([]*TokenDTO, 0, len(tokens))}
+	for _, t := range tokens {
+		resp.Tokens = append(resp.Tokens, ToTokenDTO(t))
+	}
+	response.OK(w, resp)
+}
+
+func (h *TokenHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokenID, err := strconv.ParseInt(r.PathValue("token_id"), 10, 64)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid token_id parameter", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "token_id must be an integer")
+		return
+	}
+
+	if err := h.tokenService.RevokeToken(ctx, tokenID); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to revoke API token", "token_id", tokenID, "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("API token revoked", "token_id", tokenID)
+	response.NoContent(w)
+}
+
+func (h *TokenHandler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tokenID, err := strconv.ParseInt(r.PathValue("token_id"), 10, 64)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid token_id parameter", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "token_id must be an integer")
+		return
+	}
+
+	entries, err := h.tokenService.ListAudit(ctx, tokenID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list API token audit", "token_id", tokenID, "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, ToTokenAuditResponse(tokenID, entries))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"avito/internal/domain"
 	"avito/pkg/response"
 )
 
@@ -13,20 +14,20 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 
 	var req SetIsActiveRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode request body", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid request data", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
 		response.HandleError(w, err)
 		return
 	}
 
 	user, err := h.userService.SetIsActive(ctx, req.UserID, req.IsActive)
 	if err != nil {
-		h.logger.Error("Failed to set user active status",
+		h.logger.WithContext(r.Context()).Error("Failed to set user active status",
 			"user_id", req.UserID,
 			"is_active", req.IsActive,
 			"error", err,
@@ -35,7 +36,7 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("User active status updated",
+	h.logger.WithContext(r.Context()).Info("User active status updated",
 		"user_id", user.UserID,
 		"is_active", user.IsActive,
 	)
@@ -47,19 +48,49 @@ func (h *Handler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	response.OK(w, resp)
 }
 
+func (h *Handler) SetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req SetRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	user, err := h.userService.SetRole(ctx, req.UserID, domain.Role(req.Role))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to set user role",
+			"user_id", req.UserID,
+			"role", req.Role,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, UserResponse{User: ToUserDTO(user)})
+}
+
 func (h *Handler) GetPRsByReviewer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
-		h.logger.Warn("Missing user_id parameter")
+		h.logger.WithContext(r.Context()).Warn("Missing user_id parameter")
 		response.BadRequest(w, "INVALID_INPUT", "user_id parameter is required")
 		return
 	}
 
 	prs, err := h.userService.GetPRsByReviewer(ctx, userID)
 	if err != nil {
-		h.logger.Error("Failed to get PRs by reviewer",
+		h.logger.WithContext(r.Context()).Error("Failed to get PRs by reviewer",
 			"user_id", userID,
 			"error", err,
 		)
@@ -67,7 +98,7 @@ func (h *Handler) GetPRsByReviewer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("PRs retrieved for reviewer",
+	h.logger.WithContext(r.Context()).Info("PRs retrieved for reviewer",
 		"user_id", userID,
 		"count", len(prs),
 	)
@@ -83,21 +114,21 @@ func (h *Handler) GetPRsByReviewer(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) BatchDeactivate(w http.ResponseWriter, r *http.Request) {
 	var req BatchDeactivateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.logger.Warn("Failed to decode batch deactivate request", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Failed to decode batch deactivate request", "error", err)
 		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
 		return
 	}
 
 	if err := req.Validate(); err != nil {
-		h.logger.Warn("Invalid batch deactivate request", "error", err)
+		h.logger.WithContext(r.Context()).Warn("Invalid batch deactivate request", "error", err)
 		response.HandleError(w, err)
 		return
 	}
 
-	h.logger.Info("Batch deactivation request received", "team_id", req.TeamID)
+	h.logger.WithContext(r.Context()).Info("Batch deactivation request received", "team_id", req.TeamID)
 
 	if err := h.userService.ScheduleBatchDeactivate(r.Context(), req.TeamID); err != nil {
-		h.logger.Error("Failed to schedule batch deactivate", "error", err, "team_id", req.TeamID)
+		h.logger.WithContext(r.Context()).Error("Failed to schedule batch deactivate", "error", err, "team_id", req.TeamID)
 		response.HandleError(w, err)
 		return
 	}
@@ -114,14 +145,14 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 
 	userID := r.PathValue("user_id")
 	if userID == "" {
-		h.logger.Warn("Missing user_id parameter")
+		h.logger.WithContext(r.Context()).Warn("Missing user_id parameter")
 		response.BadRequest(w, "INVALID_INPUT", "user_id is required")
 		return
 	}
 
 	user, err := h.userService.GetUser(ctx, userID)
 	if err != nil {
-		h.logger.Error("Failed to get user",
+		h.logger.WithContext(r.Context()).Error("Failed to get user",
 			"user_id", userID,
 			"error", err,
 		)
@@ -129,7 +160,7 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.logger.Info("User retrieved successfully",
+	h.logger.WithContext(r.Context()).Info("User retrieved successfully",
 		"user_id", user.UserID,
 		"is_active", user.IsActive,
 	)
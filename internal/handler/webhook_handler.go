@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"avito/internal/domain"
+	"avito/internal/service"
+	"avito/pkg/logger"
+	"avito/pkg/response"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	logger         *logger.Logger
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, log *logger.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         log,
+	}
+}
+
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to decode request body", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid request data", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	sub, err := h.webhookService.CreateSubscription(ctx, &domain.WebhookSubscription{
+		URL:    req.URL,
+		Secret: req.Secret,
+		Events: req.Events,
+	})
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to create webhook subscription",
+			"url", req.URL,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Webhook subscription created",
+		"subscription_id", sub.ID,
+		"url", sub.URL,
+		"events", sub.Events,
+	)
+
+	response.Created(w, WebhookSubscriptionResponse{Subscription: ToWebhookSubscriptionDTO(sub)})
+}
+
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subs, err := h.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list webhook subscriptions", "error", err)
+		response.HandleError(w, err)
+		return
+	}
+
+	resp := WebhookListResponse{Subscriptions: make([]*WebhookSubscriptionDTO, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, ToWebhookSubscriptionDTO(sub))
+	}
+	response.OK(w, resp)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	subscriptionID, err := strconv.Atoi(r.PathValue("subscription_id"))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Invalid subscription_id parameter", "error", err)
+		response.BadRequest(w, "INVALID_INPUT", "subscription_id must be an integer")
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			response.BadRequest(w, "INVALID_INPUT", "limit must be a positive integer")
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list webhook deliveries",
+			"subscription_id", subscriptionID,
+			"error", err,
+		)
+		response.HandleError(w, err)
+		return
+	}
+
+	response.OK(w, WebhookDeliveriesResponse{
+		SubscriptionID: subscriptionID,
+		Deliveries:     ToWebhookDeliveryDTOs(deliveries),
+	})
+}
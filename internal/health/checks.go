@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// DatabaseCheck пингует БД; ответ медленнее maxLatency тоже считается
+// отказом — такой экземпляр лучше вывести из ротации.
+func DatabaseCheck(db Pinger, maxLatency time.Duration) Check {
+	return func(ctx context.Context) Component {
+		start := time.Now()
+		err := db.PingContext(ctx)
+		latency := time.Since(start)
+		details := map[string]any{"latency_ms": latency.Milliseconds()}
+		if err != nil {
+			return down(err.Error(), details)
+		}
+		if latency > maxLatency {
+			return down(fmt.Sprintf("ping took longer than %s", maxLatency), details)
+		}
+		return up(details)
+	}
+}
+
+type SchemaVersioner interface {
+	SchemaVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
+
+// MigrationsCheck сравнивает версию схемы в БД с последней известной
+// сервису миграцией.
+func MigrationsCheck(db SchemaVersioner, latest int64) Check {
+	return func(ctx context.Context) Component {
+		version, dirty, err := db.SchemaVersion(ctx)
+		if err != nil {
+			return down(err.Error(), nil)
+		}
+		details := map[string]any{"version": version, "latest": latest}
+		switch {
+		case dirty:
+			return down(fmt.Sprintf("migration %d failed and left the schema dirty", version), details)
+		case version < latest:
+			return down(fmt.Sprintf("%d pending migrations", latest-version), details)
+		}
+		return up(details)
+	}
+}
+
+// HeartbeatCheck считает воркер мёртвым, если он не отмечался дольше
+// staleAfter.
+func HeartbeatCheck(hb *Heartbeat, staleAfter time.Duration) Check {
+	return func(context.Context) Component {
+		last := hb.Last()
+		if last.IsZero() {
+			return down("worker has not started", nil)
+		}
+		age := time.Since(last)
+		details := map[string]any{"last_beat": last.UTC().Format(time.RFC3339), "age_ms": age.Milliseconds()}
+		if age > staleAfter {
+			return down(fmt.Sprintf("no heartbeat for %s", age.Truncate(time.Second)), details)
+		}
+		return up(details)
+	}
+}
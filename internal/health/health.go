@@ -0,0 +1,121 @@
+// Package health проверяет готовность сервиса принимать трафик.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Component — результат проверки одной зависимости.
+type Component struct {
+	Status  Status         `json:"status"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+type Report struct {
+	Ready        bool                 `json:"ready"`
+	ShuttingDown bool                 `json:"shutting_down,omitempty"`
+	Components   map[string]Component `json:"components"`
+}
+
+type Check func(ctx context.Context) Component
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker выполняет проверки готовности параллельно, каждую со своим
+// таймаутом: зависшая зависимость не задерживает ответ пробы.
+type Checker struct {
+	timeout      time.Duration
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add регистрирует проверку. Вызывается до начала обработки запросов.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown переводит сервис в неготовое состояние, чтобы
+// балансировщик вывел его из ротации до остановки HTTP-сервера.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) Check(ctx context.Context) Report {
+	report := Report{
+		Ready:        true,
+		ShuttingDown: c.shuttingDown.Load(),
+		Components:   make(map[string]Component, len(c.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			component := nc.check(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[nc.name] = component
+			if component.Status != StatusUp {
+				report.Ready = false
+			}
+		}()
+	}
+	wg.Wait()
+
+	if report.ShuttingDown {
+		report.Ready = false
+	}
+	return report
+}
+
+func up(details map[string]any) Component {
+	return Component{Status: StatusUp, Details: details}
+}
+
+func down(err string, details map[string]any) Component {
+	return Component{Status: StatusDown, Error: err, Details: details}
+}
+
+// Heartbeat отмечает, что фоновый воркер жив и продолжает цикл опроса.
+type Heartbeat struct {
+	last atomic.Int64
+}
+
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// Last возвращает время последнего сигнала или нулевое время, если сигналов
+// ещё не было.
+func (h *Heartbeat) Last() time.Time {
+	ns := h.last.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
@@ -0,0 +1,130 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakePinger struct {
+	delay time.Duration
+	err   error
+}
+
+func (p fakePinger) PingContext(ctx context.Context) error {
+	select {
+	case <-time.After(p.delay):
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type fakeVersioner struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (v fakeVersioner) SchemaVersion(context.Context) (int64, bool, error) {
+	return v.version, v.dirty, v.err
+}
+
+func TestDatabaseCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		pinger fakePinger
+		want   Status
+	}{
+		{"fast", fakePinger{}, StatusUp},
+		{"error", fakePinger{err: errors.New("connection refused")}, StatusDown},
+		{"slow", fakePinger{delay: 30 * time.Millisecond}, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DatabaseCheck(tt.pinger, 10*time.Millisecond)(context.Background())
+			if got.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", got.Status, tt.want, got.Error)
+			}
+			if _, ok := got.Details["latency_ms"]; !ok {
+				t.Error("latency_ms is missing")
+			}
+		})
+	}
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		db   fakeVersioner
+		want Status
+	}{
+		{"current", fakeVersioner{version: 22}, StatusUp},
+		{"behind", fakeVersioner{version: 20}, StatusDown},
+		{"dirty", fakeVersioner{version: 22, dirty: true}, StatusDown},
+		{"error", fakeVersioner{err: errors.New("boom")}, StatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MigrationsCheck(tt.db, 22)(context.Background())
+			if got.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", got.Status, tt.want, got.Error)
+			}
+		})
+	}
+}
+
+func TestHeartbeatCheck(t *testing.T) {
+	hb := &Heartbeat{}
+	check := HeartbeatCheck(hb, 50*time.Millisecond)
+
+	if got := check(context.Background()); got.Status != StatusDown {
+		t.Error("worker without heartbeats should be down")
+	}
+
+	hb.Beat()
+	if got := check(context.Background()); got.Status != StatusUp {
+		t.Errorf("fresh heartbeat: status = %s (%s)", got.Status, got.Error)
+	}
+
+	hb.last.Store(time.Now().Add(-time.Second).UnixNano())
+	if got := check(context.Background()); got.Status != StatusDown {
+		t.Error("stale heartbeat should be down")
+	}
+}
+
+func TestChecker(t *testing.T) {
+	c := NewChecker(20 * time.Millisecond)
+	c.Add("database", DatabaseCheck(fakePinger{}, time.Second))
+	c.Add("migrations", MigrationsCheck(fakeVersioner{version: 3}, 3))
+
+	report := c.Check(context.Background())
+	if !report.Ready || len(report.Components) != 2 {
+		t.Fatalf("report = %+v, want ready with 2 components", report)
+	}
+
+	c.SetShuttingDown()
+	report = c.Check(context.Background())
+	if report.Ready || !report.ShuttingDown {
+		t.Errorf("report = %+v, want not ready while shutting down", report)
+	}
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := NewChecker(20 * time.Millisecond)
+	c.Add("database", DatabaseCheck(fakePinger{delay: time.Minute}, time.Minute))
+	c.Add("migrations", MigrationsCheck(fakeVersioner{version: 3}, 3))
+
+	start := time.Now()
+	report := c.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("check took %s, timeout was not applied", elapsed)
+	}
+	if report.Ready {
+		t.Error("hanging dependency should make service not ready")
+	}
+	if report.Components["database"].Status != StatusDown || report.Components["migrations"].Status != StatusUp {
+		t.Errorf("components = %+v", report.Components)
+	}
+}
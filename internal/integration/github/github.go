@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"avito/internal/domain"
+	"avito/internal/integration"
+)
+
+const (
+	EventHeader     = "X-GitHub-Event"
+	SignatureHeader = "X-Hub-Signature-256"
+
+	EventPing        = "ping"
+	EventPullRequest = "pull_request"
+)
+
+const (
+	actionOpened         = "opened"
+	actionReopened       = "reopened"
+	actionReadyForReview = "ready_for_review"
+	actionClosed         = "closed"
+)
+
+type PullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifySignature проверяет заголовок X-Hub-Signature-256 вида "sha256=<hex>".
+func VerifySignature(secret, body []byte, signature string) bool {
+	const prefix = "sha256="
+	if len(secret) == 0 || !strings.HasPrefix(signature, prefix) {
+		return false
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// PullRequestID строит идентификатор PR сервиса из репозитория и номера PR.
+func PullRequestID(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", repository, number)
+}
+
+type Processor struct {
+	prs      integration.PRService
+	accounts integration.AccountResolver
+}
+
+func NewProcessor(prs integration.PRService, accounts integration.AccountResolver) *Processor {
+	return &Processor{
+		prs:      prs,
+		accounts: accounts,
+	}
+}
+
+func (p *Processor) Handle(ctx context.Context, event string, body []byte) (*integration.Result, error) {
+	switch event {
+	case EventPing:
+		return integration.Ignored("", "ping"), nil
+	case EventPullRequest:
+	default:
+		return integration.Ignored("", fmt.Sprintf("event %q is not handled", event)), nil
+	}
+
+	var payload PullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid pull_request payload: %w", domain.ErrInvalidInput)
+	}
+	number := payload.PullRequest.Number
+	if number == 0 {
+		number = payload.Number
+	}
+	if payload.Repository.FullName == "" || number == 0 {
+		return nil, fmt.Errorf("pull_request payload without repository or number: %w", domain.ErrInvalidInput)
+	}
+	prID := PullRequestID(payload.Repository.FullName, number)
+
+	switch payload.Action {
+	case actionOpened, actionReopened, actionReadyForReview:
+		if payload.PullRequest.Draft {
+			return integration.Ignored(prID, "pull request is a draft"), nil
+		}
+		return integration.OpenPR(ctx, p.prs, p.accounts,
+			domain.ProviderGitHub, prID, payload.PullRequest.Title, payload.PullRequest.User.Login)
+	case actionClosed:
+		if !payload.PullRequest.Merged {
+			return integration.Ignored(prID, "pull request closed without merge"), nil
+		}
+		return integration.MergePR(ctx, p.prs, prID)
+	default:
+		return integration.Ignored(prID, fmt.Sprintf("action %q is not handled", payload.Action)), nil
+	}
+}
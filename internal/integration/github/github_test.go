@@ -0,0 +1,169 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/integration"
+	"avito/internal/integration/github"
+)
+
+type fakePRService struct {
+	prs     map[string]*domain.PullRequest
+	created []string
+	merged  []string
+}
+
+func newFakePRService() *fakePRService {
+	return &fakePRService{prs: make(map[string]*domain.PullRequest)}
+}
+
+func (f *fakePRService) CreatePR(_ context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
+	if _, ok := f.prs[prID]; ok {
+		return nil, domain.ErrPRExists
+	}
+	pr := &domain.PullRequest{PullRequestID: prID, PullRequestName: prName, AuthorID: authorID}
+	f.prs[prID] = pr
+	f.created = append(f.created, prID)
+	return pr, nil
+}
+
+func (f *fakePRService) MergePR(_ context.Context, prID string) (*domain.PullRequest, error) {
+	pr, ok := f.prs[prID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	f.merged = append(f.merged, prID)
+	return pr, nil
+}
+
+type fakeAccounts map[string]string
+
+func (f fakeAccounts) ResolveUserID(_ context.Context, provider, login string) (string, error) {
+	if userID, ok := f[provider+"/"+login]; ok {
+		return userID, nil
+	}
+	return "", domain.ErrAccountNotLinked
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func newProcessor() (*github.Processor, *fakePRService) {
+	prs := newFakePRService()
+	accounts := fakeAccounts{"github/octocat": "u1"}
+	return github.NewProcessor(prs, accounts), prs
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("It's a Secret to Everybody")
+	body := fixture(t, "pull_request_opened.json")
+	signature := github.Sign(secret, body)
+
+	if !github.VerifySignature(secret, body, signature) {
+		t.Fatal("expected valid signature")
+	}
+	if github.VerifySignature([]byte("other"), body, signature) {
+		t.Error("signature with wrong secret accepted")
+	}
+	if github.VerifySignature(secret, append(body, ' '), signature) {
+		t.Error("signature for modified body accepted")
+	}
+	if github.VerifySignature(secret, body, "sha1=abc") {
+		t.Error("signature without sha256 prefix accepted")
+	}
+	if github.VerifySignature(nil, body, signature) {
+		t.Error("signature accepted with empty secret")
+	}
+}
+
+func TestProcessor_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       string
+		fixture     string
+		wantOutcome string
+		wantCreated []string
+	}{
+		{"Opened", github.EventPullRequest, "pull_request_opened.json", integration.OutcomeCreated, []string{"acme/backend#42"}},
+		{"Opened draft", github.EventPullRequest, "pull_request_opened_draft.json", integration.OutcomeIgnored, nil},
+		{"Ready for review", github.EventPullRequest, "pull_request_ready_for_review.json", integration.OutcomeCreated, []string{"acme/backend#43"}},
+		{"Reopened untracked", github.EventPullRequest, "pull_request_reopened.json", integration.OutcomeCreated, []string{"acme/backend#42"}},
+		{"Closed without merge", github.EventPullRequest, "pull_request_closed_unmerged.json", integration.OutcomeIgnored, nil},
+		{"Closed merged untracked", github.EventPullRequest, "pull_request_closed_merged.json", integration.OutcomeIgnored, nil},
+		{"Unlinked author", github.EventPullRequest, "pull_request_opened_unlinked.json", integration.OutcomeIgnored, nil},
+		{"Unhandled action", github.EventPullRequest, "pull_request_labeled.json", integration.OutcomeIgnored, nil},
+		{"Ping", github.EventPing, "ping.json", integration.OutcomeIgnored, nil},
+		{"Unhandled event", "push", "ping.json", integration.OutcomeIgnored, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, prs := newProcessor()
+			result, err := p.Handle(context.Background(), tt.event, fixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if result.Outcome != tt.wantOutcome {
+				t.Errorf("Outcome = %q (%s), want %q", result.Outcome, result.Reason, tt.wantOutcome)
+			}
+			if len(prs.created) != len(tt.wantCreated) {
+				t.Fatalf("created = %v, want %v", prs.created, tt.wantCreated)
+			}
+			for i := range tt.wantCreated {
+				if prs.created[i] != tt.wantCreated[i] {
+					t.Errorf("created = %v, want %v", prs.created, tt.wantCreated)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessor_Lifecycle(t *testing.T) {
+	p, prs := newProcessor()
+	ctx := context.Background()
+
+	steps := []struct {
+		fixture     string
+		wantOutcome string
+	}{
+		{"pull_request_opened.json", integration.OutcomeCreated},
+		{"pull_request_opened.json", integration.OutcomeIgnored},
+		{"pull_request_reopened.json", integration.OutcomeIgnored},
+		{"pull_request_closed_merged.json", integration.OutcomeMerged},
+	}
+	for _, step := range steps {
+		result, err := p.Handle(ctx, github.EventPullRequest, fixture(t, step.fixture))
+		if err != nil {
+			t.Fatalf("%s: Handle() error = %v", step.fixture, err)
+		}
+		if result.Outcome != step.wantOutcome {
+			t.Errorf("%s: Outcome = %q, want %q", step.fixture, result.Outcome, step.wantOutcome)
+		}
+	}
+
+	if pr := prs.prs["acme/backend#42"]; pr == nil || pr.AuthorID != "u1" {
+		t.Errorf("PR author was not mapped to u1: %+v", pr)
+	}
+	if len(prs.merged) != 1 || prs.merged[0] != "acme/backend#42" {
+		t.Errorf("merged = %v, want [acme/backend#42]", prs.merged)
+	}
+}
+
+func TestProcessor_HandleInvalidPayload(t *testing.T) {
+	p, _ := newProcessor()
+	_, err := p.Handle(context.Background(), github.EventPullRequest, []byte(`{"action":`))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Handle() error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,133 @@
+package gitlab
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+	"avito/internal/integration"
+)
+
+const (
+	EventHeader = "X-Gitlab-Event"
+	TokenHeader = "X-Gitlab-Token"
+
+	EventMergeRequest = "Merge Request Hook"
+)
+
+const (
+	actionOpen   = "open"
+	actionReopen = "reopen"
+	actionUpdate = "update"
+	actionMerge  = "merge"
+	actionClose  = "close"
+)
+
+type MergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID      int    `json:"iid"`
+		AuthorID int64  `json:"author_id"`
+		Title    string `json:"title"`
+		Action   string `json:"action"`
+		Draft    bool   `json:"draft"`
+		WIP      bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+func (e *MergeRequestEvent) isDraft() bool {
+	return e.ObjectAttributes.Draft || e.ObjectAttributes.WIP
+}
+
+// VerifyToken сравнивает X-Gitlab-Token с секретом за постоянное время.
+func VerifyToken(secret []byte, token string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare(secret, []byte(token)) == 1
+}
+
+// PullRequestID строит идентификатор PR сервиса из проекта и iid merge request'а.
+func PullRequestID(project string, iid int) string {
+	return fmt.Sprintf("%s!%d", project, iid)
+}
+
+type Processor struct {
+	prs      integration.PRService
+	accounts integration.AccountIDResolver
+}
+
+func NewProcessor(prs integration.PRService, accounts integration.AccountIDResolver) *Processor {
+	return &Processor{
+		prs:      prs,
+		accounts: accounts,
+	}
+}
+
+// Handle применяет Merge Request Hook к PR. Автор берётся из
+// object_attributes.author_id, а не из поля user: там тот, кто вызвал
+// событие, например снял статус черновика с чужого MR.
+func (p *Processor) Handle(ctx context.Context, event string, body []byte) (*integration.Result, error) {
+	if event != EventMergeRequest {
+		return integration.Ignored("", fmt.Sprintf("event %q is not handled", event)), nil
+	}
+
+	var payload MergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid merge request payload: %w", domain.ErrInvalidInput)
+	}
+	if payload.Project.PathWithNamespace == "" || payload.ObjectAttributes.IID == 0 {
+		return nil, fmt.Errorf("merge request payload without project or iid: %w", domain.ErrInvalidInput)
+	}
+	prID := PullRequestID(payload.Project.PathWithNamespace, payload.ObjectAttributes.IID)
+
+	switch payload.ObjectAttributes.Action {
+	case actionOpen, actionReopen:
+		if payload.isDraft() {
+			return integration.Ignored(prID, "merge request is a draft"), nil
+		}
+		return p.open(ctx, prID, &payload)
+	case actionUpdate:
+		draft := payload.Changes.Draft
+		if draft == nil {
+			return integration.Ignored(prID, "update does not change draft status"), nil
+		}
+		if draft.Current {
+			return integration.Ignored(prID, "merge request was marked as draft"), nil
+		}
+		return p.open(ctx, prID, &payload)
+	case actionMerge:
+		return integration.MergePR(ctx, p.prs, prID)
+	case actionClose:
+		return integration.Ignored(prID, "merge request closed without merge"), nil
+	default:
+		return integration.Ignored(prID, fmt.Sprintf("action %q is not handled", payload.ObjectAttributes.Action)), nil
+	}
+}
+
+func (p *Processor) open(ctx context.Context, prID string, payload *MergeRequestEvent) (*integration.Result, error) {
+	gitlabID := payload.ObjectAttributes.AuthorID
+	if gitlabID == 0 {
+		return nil, fmt.Errorf("merge request payload without author_id: %w", domain.ErrInvalidInput)
+	}
+	authorID, err := p.accounts.ResolveUserIDByExternalID(ctx, domain.ProviderGitLab, gitlabID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotLinked) {
+			return integration.Ignored(prID, fmt.Sprintf("gitlab user %d is not linked to a user", gitlabID)), nil
+		}
+		return nil, fmt.Errorf("failed to resolve author: %w", err)
+	}
+	return integration.CreatePR(ctx, p.prs, prID, payload.ObjectAttributes.Title, authorID)
+}
@@ -0,0 +1,168 @@
+package gitlab_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/integration"
+	"avito/internal/integration/gitlab"
+)
+
+type fakePRService struct {
+	prs     map[string]*domain.PullRequest
+	created []string
+	merged  []string
+}
+
+func newFakePRService() *fakePRService {
+	return &fakePRService{prs: make(map[string]*domain.PullRequest)}
+}
+
+func (f *fakePRService) CreatePR(_ context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
+	if _, ok := f.prs[prID]; ok {
+		return nil, domain.ErrPRExists
+	}
+	pr := &domain.PullRequest{PullRequestID: prID, PullRequestName: prName, AuthorID: authorID}
+	f.prs[prID] = pr
+	f.created = append(f.created, prID)
+	return pr, nil
+}
+
+func (f *fakePRService) MergePR(_ context.Context, prID string) (*domain.PullRequest, error) {
+	pr, ok := f.prs[prID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	f.merged = append(f.merged, prID)
+	return pr, nil
+}
+
+type fakeAccounts map[int64]string
+
+func (f fakeAccounts) ResolveUserIDByExternalID(_ context.Context, provider string, externalID int64) (string, error) {
+	if userID, ok := f[externalID]; ok && provider == domain.ProviderGitLab {
+		return userID, nil
+	}
+	return "", domain.ErrAccountNotLinked
+}
+
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func newProcessor() (*gitlab.Processor, *fakePRService) {
+	prs := newFakePRService()
+	accounts := fakeAccounts{1: "u1"}
+	return gitlab.NewProcessor(prs, accounts), prs
+}
+
+func TestVerifyToken(t *testing.T) {
+	secret := []byte("gitlab-secret")
+
+	if !gitlab.VerifyToken(secret, "gitlab-secret") {
+		t.Error("expected valid token")
+	}
+	if gitlab.VerifyToken(secret, "gitlab-secret ") {
+		t.Error("token with trailing space accepted")
+	}
+	if gitlab.VerifyToken(secret, "") {
+		t.Error("empty token accepted")
+	}
+	if gitlab.VerifyToken(nil, "") {
+		t.Error("token accepted with empty secret")
+	}
+}
+
+func TestProcessor_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		event       string
+		fixture     string
+		wantOutcome string
+		wantCreated []string
+	}{
+		{"Open", gitlab.EventMergeRequest, "merge_request_open.json", integration.OutcomeCreated, []string{"acme/backend!7"}},
+		{"Open draft", gitlab.EventMergeRequest, "merge_request_open_draft.json", integration.OutcomeIgnored, nil},
+		{"Open unlinked author", gitlab.EventMergeRequest, "merge_request_open_unlinked.json", integration.OutcomeIgnored, nil},
+		{"Reopen untracked", gitlab.EventMergeRequest, "merge_request_reopen.json", integration.OutcomeCreated, []string{"acme/backend!7"}},
+		{"Update draft to ready", gitlab.EventMergeRequest, "merge_request_update_ready.json", integration.OutcomeCreated, []string{"acme/backend!8"}},
+		{"Update ready to draft", gitlab.EventMergeRequest, "merge_request_update_draft.json", integration.OutcomeIgnored, nil},
+		{"Update title", gitlab.EventMergeRequest, "merge_request_update_title.json", integration.OutcomeIgnored, nil},
+		{"Merge untracked", gitlab.EventMergeRequest, "merge_request_merge.json", integration.OutcomeIgnored, nil},
+		{"Close", gitlab.EventMergeRequest, "merge_request_close.json", integration.OutcomeIgnored, nil},
+		{"Unhandled event", "Push Hook", "merge_request_open.json", integration.OutcomeIgnored, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, prs := newProcessor()
+			result, err := p.Handle(context.Background(), tt.event, fixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Handle() error = %v", err)
+			}
+			if result.Outcome != tt.wantOutcome {
+				t.Errorf("Outcome = %q (%s), want %q", result.Outcome, result.Reason, tt.wantOutcome)
+			}
+			if len(prs.created) != len(tt.wantCreated) {
+				t.Fatalf("created = %v, want %v", prs.created, tt.wantCreated)
+			}
+			for i := range tt.wantCreated {
+				if prs.created[i] != tt.wantCreated[i] {
+					t.Errorf("created = %v, want %v", prs.created, tt.wantCreated)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessor_Lifecycle(t *testing.T) {
+	p, prs := newProcessor()
+	ctx := context.Background()
+
+	steps := []struct {
+		fixture     string
+		wantOutcome string
+	}{
+		{"merge_request_open_draft.json", integration.OutcomeIgnored},
+		{"merge_request_update_ready.json", integration.OutcomeCreated},
+		{"merge_request_open.json", integration.OutcomeCreated},
+		{"merge_request_reopen.json", integration.OutcomeIgnored},
+		{"merge_request_close.json", integration.OutcomeIgnored},
+		{"merge_request_merge.json", integration.OutcomeMerged},
+	}
+	for _, step := range steps {
+		result, err := p.Handle(ctx, gitlab.EventMergeRequest, fixture(t, step.fixture))
+		if err != nil {
+			t.Fatalf("%s: Handle() error = %v", step.fixture, err)
+		}
+		if result.Outcome != step.wantOutcome {
+			t.Errorf("%s: Outcome = %q, want %q", step.fixture, result.Outcome, step.wantOutcome)
+		}
+	}
+
+	// Статус черновика снял другой пользователь (alee), автором остаётся
+	// владелец MR по author_id.
+	if pr := prs.prs["acme/backend!8"]; pr == nil || pr.AuthorID != "u1" {
+		t.Errorf("PR author was not mapped to u1: %+v", pr)
+	}
+	if len(prs.merged) != 1 || prs.merged[0] != "acme/backend!7" {
+		t.Errorf("merged = %v, want [acme/backend!7]", prs.merged)
+	}
+}
+
+func TestProcessor_HandleInvalidPayload(t *testing.T) {
+	p, _ := newProcessor()
+	_, err := p.Handle(context.Background(), gitlab.EventMergeRequest, []byte(`{"object_attributes":{"iid":1}}`))
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("Handle() error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type PRService interface {
+	CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error)
+	MergePR(ctx context.Context, prID string) (*domain.PullRequest, error)
+}
+
+type AccountResolver interface {
+	ResolveUserID(ctx context.Context, provider, login string) (string, error)
+}
+
+// AccountIDResolver находит пользователя по числовому id во внешней системе.
+type AccountIDResolver interface {
+	ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error)
+}
+
+const (
+	OutcomeCreated = "created"
+	OutcomeMerged  = "merged"
+	OutcomeIgnored = "ignored"
+)
+
+// Result описывает, как входящее событие было применено к PR.
+type Result struct {
+	Outcome       string `json:"outcome"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+func Ignored(prID, reason string) *Result {
+	return &Result{Outcome: OutcomeIgnored, PullRequestID: prID, Reason: reason}
+}
+
+// OpenPR создаёт PR от имени пользователя, привязанного к внешнему логину.
+// Повторная доставка события и непривязанные аккаунты не считаются ошибкой.
+func OpenPR(
+	ctx context.Context,
+	prs PRService,
+	accounts AccountResolver,
+	provider, prID, title, login string,
+) (*Result, error) {
+	authorID, err := accounts.ResolveUserID(ctx, provider, login)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountNotLinked) {
+			return Ignored(prID, fmt.Sprintf("%s account %q is not linked to a user", provider, login)), nil
+		}
+		return nil, fmt.Errorf("failed to resolve author: %w", err)
+	}
+	return CreatePR(ctx, prs, prID, title, authorID)
+}
+
+// CreatePR создаёт PR от имени уже найденного пользователя сервиса.
+func CreatePR(ctx context.Context, prs PRService, prID, title, authorID string) (*Result, error) {
+	if _, err := prs.CreatePR(ctx, prID, title, authorID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPRExists):
+			return Ignored(prID, "pull request already exists"), nil
+		case errors.Is(err, domain.ErrAuthorNotFound):
+			return Ignored(prID, fmt.Sprintf("author %q not found", authorID)), nil
+		default:
+			return nil, fmt.Errorf("failed to create PR: %w", err)
+		}
+	}
+	return &Result{Outcome: OutcomeCreated, PullRequestID: prID}, nil
+}
+
+func MergePR(ctx context.Context, prs PRService, prID string) (*Result, error) {
+	if _, err := prs.MergePR(ctx, prID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrPRNotFound) {
+			return Ignored(prID, "pull request is not tracked"), nil
+		}
+		return nil, fmt.Errorf("failed to merge PR: %w", err)
+	}
+	return &Result{Outcome: OutcomeMerged, PullRequestID: prID}, nil
+}
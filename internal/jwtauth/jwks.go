@@ -0,0 +1,294 @@
+// Package jwtauth проверяет JWT, подписанные RS256 или ES256, по ключам из
+// JWKS (RFC 7517). Другие алгоритмы, в том числе HS256 и none, отклоняются.
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxJWKSSize ограничивает размер загружаемого JWKS.
+const maxJWKSSize = 1 << 20
+
+// Source загружает JWKS в виде JSON.
+type Source func(ctx context.Context) ([]byte, error)
+
+// FileSource читает JWKS из файла при каждой загрузке, так что ротация ключей
+// сводится к замене файла.
+func FileSource(path string) Source {
+	return func(context.Context) ([]byte, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// URLSource загружает JWKS по HTTP.
+func URLSource(url string, client *http.Client) Source {
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build JWKS request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS response: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// KeySet хранит открытые ключи по kid. Если токен подписан неизвестным
+// ключом, набор перезагружается, но не чаще чем раз в minRefresh. Набор,
+// загруженный больше ttl назад, перезагружается при следующем обращении, так
+// что ключ, убранный из JWKS, перестаёт приниматься не позже чем через ttl.
+// Если перезагрузить устаревший набор не удалось, токены отклоняются.
+type KeySet struct {
+	source     Source
+	minRefresh time.Duration
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	loadedAt    time.Time
+	attemptedAt time.Time
+	loading     *keyLoad
+}
+
+// keyLoad — загрузка JWKS, которую ждут все одновременные запросы.
+type keyLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewKeySet сразу загружает ключи, чтобы ошибка конфигурации проявилась при
+// старте.
+func NewKeySet(ctx context.Context, source Source, minRefresh, ttl time.Duration) (*KeySet, error) {
+	s := &KeySet{
+		source:     source,
+		minRefresh: minRefresh,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+	s.mu.Lock()
+	load := s.startLoad(ctx)
+	s.mu.Unlock()
+	<-load.done
+	if load.err != nil {
+		return nil, load.err
+	}
+	return s, nil
+}
+
+// Key возвращает ключ по kid. Пустой kid допустим, если в наборе ровно один
+// ключ. Загрузка JWKS идёт без блокировки набора, и одновременные запросы
+// ждут одну и ту же загрузку.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	now := s.now()
+	key, known := s.lookup(kid)
+	if known && now.Sub(s.loadedAt) < s.ttl {
+		s.mu.Unlock()
+		return key, nil
+	}
+	load := s.loading
+	if load == nil {
+		if now.Sub(s.attemptedAt) < s.minRefresh {
+			s.mu.Unlock()
+			return nil, s.missing(kid, known)
+		}
+		load = s.startLoad(ctx)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-load.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if load.err != nil {
+		return nil, load.err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, s.missing(kid, false)
+}
+
+func (s *KeySet) missing(kid string, known bool) error {
+	if known {
+		return fmt.Errorf("%w: JWKS expired, key %q is not trusted until reload", ErrInvalidToken, kid)
+	}
+	return fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+func (s *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// startLoad запускает загрузку JWKS; вызывается под s.mu. Загрузка не
+// зависит от отмены запроса, который её начал: её результат ждут и другие.
+func (s *KeySet) startLoad(ctx context.Context) *keyLoad {
+	// Время попытки фиксируется и при ошибке, чтобы токены с чужим kid не
+	// вызывали запрос к JWKS на каждый вызов.
+	s.attemptedAt = s.now()
+	load := &keyLoad{done: make(chan struct{})}
+	s.loading = load
+
+	go func() {
+		keys, err := s.fetch(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err == nil {
+			s.keys = keys
+			s.loadedAt = s.now()
+		}
+		s.loading = nil
+		load.err = err
+		close(load.done)
+	}()
+	return load
+}
+
+func (s *KeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.source(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS разбирает JWKS и возвращает ключи подписи RSA и EC P-256.
+// Ключи шифрования и неподдерживаемых типов пропускаются.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			if k.Crv != "P-256" {
+				continue
+			}
+			key, err = k.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no supported signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("unsupported exponent")
+	}
+	key := &rsa.PublicKey{N: n, E: int(e.Int64())}
+	if key.Size() < 256 {
+		return nil, errors.New("RSA key must be at least 2048 bits")
+	}
+	return key, nil
+}
+
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	if len(x) != 32 || len(y) != 32 {
+		return nil, errors.New("invalid P-256 coordinates")
+	}
+	// Несжатая точка 0x04||X||Y; ParseUncompressedPublicKey проверяет, что
+	// она лежит на кривой.
+	point := append(append([]byte{4}, x...), y...)
+	return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
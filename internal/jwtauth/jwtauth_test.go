@@ -0,0 +1,411 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testKey struct {
+	kid  string
+	alg  string
+	priv crypto.Signer
+}
+
+func newRSAKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	return &testKey{kid: kid, alg: "RS256", priv: priv}
+}
+
+func newECKey(t *testing.T, kid string) *testKey {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate EC key: %v", err)
+	}
+	return &testKey{kid: kid, alg: "ES256", priv: priv}
+}
+
+func (k *testKey) jwk() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch pub := k.priv.Public().(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA", "kid": k.kid, "use": "sig", "alg": "RS256",
+			"n": b64(pub.N.Bytes()),
+			"e": b64(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case *ecdsa.PublicKey:
+		raw, _ := pub.Bytes()
+		return map[string]string{
+			"kty": "EC", "kid": k.kid, "use": "sig", "crv": "P-256",
+			"x": b64(raw[1:33]),
+			"y": b64(raw[33:]),
+		}
+	}
+	return nil
+}
+
+func jwks(keys ...*testKey) []byte {
+	set := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	for _, k := range keys {
+		set.Keys = append(set.Keys, k.jwk())
+	}
+	data, _ := json.Marshal(set)
+	return data
+}
+
+func (k *testKey) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": k.alg, "typ": "JWT", "kid": k.kid})
+	payload, _ := json.Marshal(claims)
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch priv := k.priv.(type) {
+	case *rsa.PrivateKey:
+		s, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		sig = s
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// jwksServer отдаёт текущий JWKS и считает запросы.
+type jwksServer struct {
+	*httptest.Server
+	mu    sync.Mutex
+	body  []byte
+	calls int
+}
+
+func newJWKSServer(t *testing.T, body []byte) *jwksServer {
+	t.Helper()
+	s := &jwksServer{body: body}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(s.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body = body
+}
+
+func (s *jwksServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestVerifier(t *testing.T) {
+	ctx := context.Background()
+	rsaKey := newRSAKey(t, "rsa-1")
+	ecKey := newECKey(t, "ec-1")
+	server := newJWKSServer(t, jwks(rsaKey, ecKey))
+
+	keys, err := NewKeySet(ctx, URLSource(server.URL, server.Client()), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+	v := NewVerifier(keys, Config{Issuer: "https://gateway", Audience: "pr-reviewer", Leeway: 30 * time.Second})
+	v.now = func() time.Time { return now }
+
+	valid := func() map[string]any {
+		return map[string]any{
+			"iss":  "https://gateway",
+			"aud":  []string{"pr-reviewer", "other"},
+			"sub":  "u1",
+			"role": "lead",
+			"exp":  now.Add(time.Hour).Unix(),
+		}
+	}
+	with := func(key string, value any) map[string]any {
+		c := valid()
+		if value == nil {
+			delete(c, key)
+		} else {
+			c[key] = value
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "RS256", token: rsaKey.sign(t, valid())},
+		{name: "ES256", token: ecKey.sign(t, valid())},
+		{name: "audience as string", token: rsaKey.sign(t, with("aud", "pr-reviewer"))},
+		{name: "expired within leeway", token: rsaKey.sign(t, with("exp", now.Add(-10*time.Second).Unix()))},
+		{name: "expired", token: rsaKey.sign(t, with("exp", now.Add(-time.Minute).Unix())), wantErr: true},
+		{name: "missing exp", token: rsaKey.sign(t, with("exp", nil)), wantErr: true},
+		{name: "not valid yet", token: rsaKey.sign(t, with("nbf", now.Add(time.Minute).Unix())), wantErr: true},
+		{name: "wrong issuer", token: rsaKey.sign(t, with("iss", "https://evil")), wantErr: true},
+		{name: "wrong audience", token: rsaKey.sign(t, with("aud", "other")), wantErr: true},
+		{name: "malformed", token: "not-a-jwt", wantErr: true},
+		{
+			name:    "signed by unknown key with known kid",
+			token:   newRSAKey(t, "rsa-1").sign(t, valid()),
+			wantErr: true,
+		},
+		{
+			name: "tampered payload",
+			token: func() string {
+				// Payload с ролью admin и подпись от исходного токена.
+				tok := rsaKey.sign(t, valid())
+				forged := rsaKey.sign(t, with("role", "admin"))
+				return forged[:strings.LastIndex(forged, ".")] + tok[strings.LastIndex(tok, "."):]
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "alg none",
+			token: func() string {
+				header, _ := json.Marshal(map[string]string{"alg": "none", "kid": "rsa-1"})
+				payload, _ := json.Marshal(valid())
+				return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm does not match key type",
+			token:   (&testKey{kid: "ec-1", alg: "RS256", priv: rsaKey.priv}).sign(t, valid()),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(ctx, tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Fatalf("expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if claims.String("sub") != "u1" {
+				t.Errorf("expected sub u1, got %q", claims.String("sub"))
+			}
+		})
+	}
+}
+
+func TestKeySet_RotationReloadsOnUnknownKid(t *testing.T) {
+	ctx := context.Background()
+	oldKey := newECKey(t, "old")
+	newKey := newECKey(t, "new")
+	server := newJWKSServer(t, jwks(oldKey))
+
+	keys, err := NewKeySet(ctx, URLSource(server.URL, server.Client()), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	v := NewVerifier(keys, Config{})
+	claims := map[string]any{"sub": "u1", "exp": now.Add(time.Hour).Unix()}
+
+	server.set(jwks(oldKey, newKey))
+	if _, err := v.Verify(ctx, newKey.sign(t, claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected reload to be throttled, got %v", err)
+	}
+	if got := server.requests(); got != 1 {
+		t.Fatalf("expected 1 JWKS request, got %d", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := v.Verify(ctx, newKey.sign(t, claims)); err != nil {
+		t.Fatalf("expected rotated key to be accepted, got %v", err)
+	}
+	if _, err := v.Verify(ctx, oldKey.sign(t, claims)); err != nil {
+		t.Fatalf("expected old key to stay valid, got %v", err)
+	}
+	if got := server.requests(); got != 2 {
+		t.Errorf("expected 2 JWKS requests, got %d", got)
+	}
+}
+
+func TestKeySet_ExpiresRemovedKeys(t *testing.T) {
+	ctx := context.Background()
+	oldKey := newECKey(t, "old")
+	newKey := newECKey(t, "new")
+	server := newJWKSServer(t, jwks(oldKey, newKey))
+
+	keys, err := NewKeySet(ctx, URLSource(server.URL, server.Client()), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	now := time.Now()
+	keys.now = func() time.Time { return now }
+	v := NewVerifier(keys, Config{})
+	claims := map[string]any{"sub": "u1", "exp": now.Add(2 * time.Hour).Unix()}
+
+	server.set(jwks(newKey))
+	if _, err := v.Verify(ctx, oldKey.sign(t, claims)); err != nil {
+		t.Fatalf("expected old key to be trusted within TTL, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := v.Verify(ctx, oldKey.sign(t, claims)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected removed key to be rejected after TTL, got %v", err)
+	}
+	if _, err := v.Verify(ctx, newKey.sign(t, claims)); err != nil {
+		t.Fatalf("expected remaining key to be accepted, got %v", err)
+	}
+	if got := server.requests(); got != 2 {
+		t.Errorf("expected 2 JWKS requests, got %d", got)
+	}
+}
+
+func TestKeySet_ConcurrentReloadFetchesOnce(t *testing.T) {
+	ctx := context.Background()
+	oldKey := newECKey(t, "old")
+	newKey := newECKey(t, "new")
+
+	var (
+		mu      sync.Mutex
+		fetches int
+		body    = jwks(oldKey)
+	)
+	release := make(chan struct{})
+	source := func(context.Context) ([]byte, error) {
+		mu.Lock()
+		fetches++
+		first := fetches == 1
+		data := body
+		mu.Unlock()
+		if !first {
+			<-release
+		}
+		return data, nil
+	}
+
+	keys, err := NewKeySet(ctx, source, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	now := time.Now().Add(2 * time.Minute)
+	keys.now = func() time.Time { return now }
+	mu.Lock()
+	body = jwks(oldKey, newKey)
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(ctx, "new")
+			errs <- err
+		}()
+	}
+	// Пока JWKS загружается, известный ключ отдаётся без ожидания.
+	for keys.loadingNow() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := keys.Key(ctx, "old"); err != nil {
+		t.Fatalf("known key during reload: %v", err)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Key(new): %v", err)
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("expected 2 JWKS fetches, got %d", fetches)
+	}
+}
+
+func (s *KeySet) loadingNow() *keyLoad {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loading
+}
+
+func TestFileSource(t *testing.T) {
+	key := newRSAKey(t, "")
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks(key), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := NewKeySet(context.Background(), FileSource(path), time.Minute, time.Hour)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	v := NewVerifier(keys, Config{})
+	token := key.sign(t, map[string]any{"sub": "u1", "exp": time.Now().Add(time.Hour).Unix()})
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Errorf("expected single key without kid to be used, got %v", err)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	if _, err := ParseJWKS([]byte(`{"keys":[{"kty":"oct","k":"c2VjcmV0"}]}`)); err == nil {
+		t.Error("expected error for JWKS without signing keys")
+	}
+	enc := newRSAKey(t, "enc").jwk()
+	enc["use"] = "enc"
+	data, _ := json.Marshal(map[string]any{"keys": []any{enc, newECKey(t, "sig").jwk()}})
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		t.Fatalf("ParseJWKS: %v", err)
+	}
+	if _, ok := keys["enc"]; ok {
+		t.Error("expected encryption key to be skipped")
+	}
+	if _, ok := keys["sig"]; !ok {
+		t.Error("expected signing key to be loaded")
+	}
+}
@@ -0,0 +1,139 @@
+// Package metrics собирает метрики сервиса в формате Prometheus.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"avito/pkg/logger"
+)
+
+const namespace = "pr_reviewer"
+
+// queueScrapeTimeout ограничивает запрос глубины очереди при сборе метрик.
+const queueScrapeTimeout = 2 * time.Second
+
+// httpBuckets включают границу 0.3 с, чтобы SLI "ответ быстрее 300 мс"
+// считался по гистограмме без интерполяции.
+var httpBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.2, 0.3, 0.5, 1, 2.5, 5}
+
+type Metrics struct {
+	registry *prometheus.Registry
+	logger   *logger.Logger
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	tasks        *prometheus.CounterVec
+	assignments  *prometheus.CounterVec
+}
+
+func New(log *logger.Logger) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		logger:   log,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route and status.",
+			Buckets:   httpBuckets,
+		}, []string{"method", "route", "status"}),
+		tasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tasks_processed_total",
+			Help:      "Background tasks processed by kind and final status.",
+		}, []string{"kind", "status"}),
+		assignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "assignments_total",
+			Help:      "Reviewer assignment decisions by strategy, kind and outcome.",
+		}, []string{"strategy", "kind", "outcome"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.tasks,
+		m.assignments,
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTP учитывает запрос. route — шаблон маршрута chi, а не путь, чтобы
+// идентификаторы не раздували число рядов.
+func (m *Metrics) ObserveHTTP(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveTask(kind, status string) {
+	m.tasks.WithLabelValues(kind, status).Inc()
+}
+
+func (m *Metrics) ObserveAssignment(strategy, kind, outcome string) {
+	m.assignments.WithLabelValues(strategy, kind, outcome).Inc()
+}
+
+// RegisterDBStats публикует статистику пула соединений из sql.DB.Stats().
+func (m *Metrics) RegisterDBStats(db *sql.DB, dbName string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// QueueDepthFunc возвращает число ожидающих задач по видам.
+type QueueDepthFunc func(ctx context.Context) (map[string]int, error)
+
+// RegisterTaskQueue публикует глубину очереди фоновых задач; она читается из
+// БД при каждом сборе метрик.
+func (m *Metrics) RegisterTaskQueue(depth QueueDepthFunc) {
+	m.registry.MustRegister(&queueCollector{
+		depth:  depth,
+		logger: m.logger,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "task_queue_depth"),
+			"Pending background tasks by kind.",
+			[]string{"kind"}, nil,
+		),
+	})
+}
+
+type queueCollector struct {
+	depth  QueueDepthFunc
+	logger *logger.Logger
+	desc   *prometheus.Desc
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueScrapeTimeout)
+	defer cancel()
+
+	counts, err := c.depth(ctx)
+	if err != nil {
+		// Без данных ряд пропадает из выборки, а не показывает ложный ноль.
+		c.logger.Warn("Failed to collect task queue depth", "error", err)
+		return
+	}
+	for kind, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), kind)
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"avito/pkg/logger"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	m := New(logger.NewWithWriter(io.Discard, "error", "json"))
+	m.ObserveHTTP(http.MethodPost, "/pullRequest/create", http.StatusCreated, 250*time.Millisecond)
+	m.ObserveHTTP(http.MethodPost, "/pullRequest/create", http.StatusCreated, 400*time.Millisecond)
+	m.ObserveTask("fill", "completed")
+	m.ObserveAssignment("fair", "reassign", "NO_CANDIDATE")
+	m.RegisterTaskQueue(func(context.Context) (map[string]int, error) {
+		return map[string]int{"deactivate": 2, "fill": 0}, nil
+	})
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`pr_reviewer_http_requests_total{method="POST",route="/pullRequest/create",status="201"} 2`,
+		`pr_reviewer_http_request_duration_seconds_bucket{method="POST",route="/pullRequest/create",status="201",le="0.3"} 1`,
+		`pr_reviewer_tasks_processed_total{kind="fill",status="completed"} 1`,
+		`pr_reviewer_assignments_total{kind="reassign",outcome="NO_CANDIDATE",strategy="fair"} 1`,
+		`pr_reviewer_task_queue_depth{kind="deactivate"} 2`,
+		`pr_reviewer_task_queue_depth{kind="fill"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output does not contain %q", want)
+		}
+	}
+}
+
+func TestMetrics_QueueDepthError(t *testing.T) {
+	m := New(logger.NewWithWriter(io.Discard, "error", "json"))
+	m.RegisterTaskQueue(func(context.Context) (map[string]int, error) {
+		return nil, errors.New("db is down")
+	})
+
+	body := scrape(t, m)
+	if strings.Contains(body, "pr_reviewer_task_queue_depth{") {
+		t.Error("expected queue depth to be omitted when the query fails")
+	}
+}
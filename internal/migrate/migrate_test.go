@@ -0,0 +1,81 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"avito/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"000002_add_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"000001_init.up.sql":        {Data: []byte("CREATE TABLE teams ();")},
+		"000001_init.down.sql":      {Data: []byte("DROP TABLE teams;")},
+		"000010_seed.up.sql":        {Data: []byte("INSERT INTO teams DEFAULT VALUES;")},
+		"embed.go":                  {Data: []byte("package migrations")},
+	}
+
+	got, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d migrations, want 3", len(got))
+	}
+	for i, want := range []int64{1, 2, 10} {
+		if got[i].Version != want {
+			t.Errorf("migrations[%d].Version = %d, want %d", i, got[i].Version, want)
+		}
+	}
+	if got[1].Name != "add_users" || got[1].Up != "CREATE TABLE users ();" || got[1].Down != "DROP TABLE users;" {
+		t.Errorf("migration 2 = %+v", got[1])
+	}
+	if got[2].Down != "" {
+		t.Errorf("migration 10 should have no down script")
+	}
+
+	m := &Migrator{migrations: got}
+	if m.Latest() != 10 {
+		t.Errorf("Latest() = %d, want 10", m.Latest())
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"empty", fstest.MapFS{}, "no migrations"},
+		{"down only", fstest.MapFS{"000001_init.down.sql": {}}, "no up file"},
+		{"bad direction", fstest.MapFS{"000001_init.sql": {}}, "invalid migration file name"},
+		{"bad version", fstest.MapFS{"init.up.sql": {}}, "invalid migration version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Встроенные миграции должны идти без пропусков и откатываться.
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Fatalf("migration %d_%s: want version %d", m.Version, m.Name, i+1)
+		}
+		if strings.TrimSpace(m.Up) == "" || strings.TrimSpace(m.Down) == "" {
+			t.Errorf("migration %d_%s has an empty up or down script", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// ChatChannel отправляет сообщение POST-запросом {"text": ...} на входящий
+// вебхук чата пользователя; этот формат принимают Slack, Mattermost и
+// Rocket.Chat.
+//
+// Адрес вебхука задаёт пользователь, поэтому канал не ходит во внутреннюю
+// сеть: соединения с loopback, приватными, link-local и прочими
+// непубличными адресами отклоняются при подключении, уже после разрешения
+// имени, а редиректы не выполняются. Если задан allowedHosts, принимаются
+// только вебхуки на этих хостах.
+type ChatChannel struct {
+	client       *http.Client
+	allowedHosts []string
+}
+
+// errForbiddenAddress возвращается при попытке подключиться к непубличному адресу.
+var errForbiddenAddress = errors.New("chat webhook address is not public")
+
+func NewChatChannel(timeout time.Duration, allowedHosts []string) *ChatChannel {
+	return newChatChannel(timeout, allowedHosts, denyPrivateAddress)
+}
+
+// newChatChannel позволяет тестам подменить проверку адреса, чтобы слать
+// вебхуки на локальный httptest-сервер.
+func newChatChannel(timeout time.Duration, allowedHosts []string, control func(string, string, syscall.RawConn) error) *ChatChannel {
+	dialer := &net.Dialer{Timeout: timeout, Control: control}
+	hosts := make([]string, 0, len(allowedHosts))
+	for _, h := range allowedHosts {
+		hosts = append(hosts, strings.ToLower(h))
+	}
+	return &ChatChannel{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		allowedHosts: hosts,
+	}
+}
+
+// denyPrivateAddress запрещает соединения с адресами, недоступными из
+// интернета; проверяется уже разрешённый IP, так что DNS-ребиндинг не помогает.
+func denyPrivateAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return fmt.Errorf("%w: %s", errForbiddenAddress, ip)
+	}
+	return nil
+}
+
+func (c *ChatChannel) allowed(webhookURL string) bool {
+	if len(c.allowedHosts) == 0 {
+		return true
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(c.allowedHosts, strings.ToLower(u.Hostname()))
+}
+
+func (c *ChatChannel) Name() string { return domain.NotifyChannelChat }
+
+type chatPayload struct {
+	Text string `json:"text"`
+}
+
+func (c *ChatChannel) Send(ctx context.Context, to Recipient, msg Message) error {
+	if to.ChatWebhookURL == "" {
+		return fmt.Errorf("user %s has no chat webhook url", to.UserID)
+	}
+	if !c.allowed(to.ChatWebhookURL) {
+		return fmt.Errorf("chat webhook host of user %s is not allowed", to.UserID)
+	}
+	body, err := json.Marshal(chatPayload{Text: msg.Subject + "\n\n" + msg.Body})
+	if err != nil {
+		return fmt.Errorf("failed to encode chat message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.ChatWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build chat request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post chat message: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("chat webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// RenderDigest формирует текст ежедневного дайджеста открытых ревью.
+func RenderDigest(d *domain.Digest) Message {
+	subject := fmt.Sprintf("Daily review digest: %d open", len(d.Items))
+	if d.Breached > 0 {
+		subject += fmt.Sprintf(", %d overdue", d.Breached)
+	}
+
+	var b strings.Builder
+	if len(d.Items) == 0 {
+		b.WriteString("You have no open reviews.")
+	}
+	for i, item := range d.Items {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "- %s %q by %s, open for %s [%s]",
+			item.PullRequestID,
+			item.PullRequestName,
+			item.AuthorID,
+			FormatAge(item.Age),
+			item.SLAStatus,
+		)
+	}
+	return Message{Subject: subject, Body: b.String()}
+}
+
+// FormatAge округляет возраст до часов: "3h", "2d 5h".
+func FormatAge(age time.Duration) string {
+	hours := int(age / time.Hour)
+	if hours < 24 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
@@ -0,0 +1,118 @@
+// Package notifier формирует уведомления пользователям из доменных событий
+// и доставляет их через каналы (email, чат-вебхук).
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"avito/internal/domain"
+)
+
+// Recipient — адреса пользователя из его настроек уведомлений.
+type Recipient struct {
+	UserID         string
+	Email          string
+	ChatWebhookURL string
+}
+
+func NewRecipient(p *domain.NotificationPreferences) Recipient {
+	return Recipient{
+		UserID:         p.UserID,
+		Email:          p.Email,
+		ChatWebhookURL: p.ChatWebhookURL,
+	}
+}
+
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Channel доставляет сообщение получателю; имя канала совпадает со значением
+// в NotificationPreferences.Channels.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, to Recipient, msg Message) error
+}
+
+// Draft — уведомление одному пользователю, ещё не запланированное к отправке.
+type Draft struct {
+	UserID  string
+	Subject string
+	Body    string
+}
+
+// Compose возвращает уведомления, которые порождает событие. События, о
+// которых пользователям не сообщают, дают пустой результат.
+func Compose(e *domain.Event) ([]Draft, error) {
+	switch e.Type {
+	case domain.EventReviewerAssigned:
+		var p domain.ReviewerEventPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", e.ID, err)
+		}
+		return []Draft{{
+			UserID:  p.ReviewerID,
+			Subject: "Review requested: " + p.PullRequestID,
+			Body:    fmt.Sprintf("You were assigned to review pull request %s.", p.PullRequestID),
+		}}, nil
+
+	case domain.EventReviewerReassigned:
+		var p domain.ReviewerEventPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", e.ID, err)
+		}
+		drafts := []Draft{{
+			UserID:  p.ReviewerID,
+			Subject: "Review requested: " + p.PullRequestID,
+			Body:    fmt.Sprintf("You were assigned to review pull request %s instead of %s.", p.PullRequestID, p.OldReviewerID),
+		}}
+		if p.OldReviewerID != "" {
+			drafts = append(drafts, Draft{
+				UserID:  p.OldReviewerID,
+				Subject: "Review reassigned: " + p.PullRequestID,
+				Body:    fmt.Sprintf("Pull request %s was reassigned to %s; your review is no longer needed.", p.PullRequestID, p.ReviewerID),
+			})
+		}
+		return drafts, nil
+
+	case domain.EventPRMerged:
+		var p domain.PREventPayload
+		if err := json.Unmarshal(e.Payload, &p); err != nil {
+			return nil, fmt.Errorf("failed to decode event %d: %w", e.ID, err)
+		}
+		drafts := make([]Draft, 0, len(p.AssignedReviewers))
+		for _, reviewerID := range p.AssignedReviewers {
+			drafts = append(drafts, Draft{
+				UserID:  reviewerID,
+				Subject: "Pull request merged: " + p.PullRequestID,
+				Body:    fmt.Sprintf("Pull request %s (%s) by %s was merged.", p.PullRequestID, p.PullRequestName, p.AuthorID),
+			})
+		}
+		return drafts, nil
+	}
+	return nil, nil
+}
+
+// Combine собирает уведомления одного пользователя в одно сообщение.
+func Combine(notifications []*domain.Notification) Message {
+	if len(notifications) == 1 {
+		return Message{Subject: notifications[0].Subject, Body: notifications[0].Body}
+	}
+	var b strings.Builder
+	for i, n := range notifications {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(n.Subject)
+		b.WriteString("\n")
+		b.WriteString(n.Body)
+	}
+	return Message{
+		Subject: fmt.Sprintf("%d updates on your reviews", len(notifications)),
+		Body:    b.String(),
+	}
+}
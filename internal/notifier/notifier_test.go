@@ -0,0 +1,178 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+)
+
+func mustEvent(t *testing.T, eventType, aggregateID string, payload any) *domain.Event {
+	t.Helper()
+	e, err := domain.NewEvent(eventType, aggregateID, payload)
+	if err != nil {
+		t.Fatalf("NewEvent: %v", err)
+	}
+	e.ID = 1
+	return e
+}
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *domain.Event
+		users []string
+	}{
+		{
+			name: "assigned reviewer",
+			event: mustEvent(t, domain.EventReviewerAssigned, "pr-1",
+				domain.ReviewerEventPayload{PullRequestID: "pr-1", ReviewerID: "u2"}),
+			users: []string{"u2"},
+		},
+		{
+			name: "reassignment notifies both reviewers",
+			event: mustEvent(t, domain.EventReviewerReassigned, "pr-1",
+				domain.ReviewerEventPayload{PullRequestID: "pr-1", ReviewerID: "u3", OldReviewerID: "u2"}),
+			users: []string{"u3", "u2"},
+		},
+		{
+			name: "merge notifies reviewers",
+			event: mustEvent(t, domain.EventPRMerged, "pr-1", domain.PREventPayload{
+				PullRequestID: "pr-1", AuthorID: "u1", AssignedReviewers: []string{"u2", "u3"},
+			}),
+			users: []string{"u2", "u3"},
+		},
+		{
+			name:  "team events are silent",
+			event: mustEvent(t, domain.EventTeamCreated, "backend", domain.TeamEventPayload{TeamName: "backend"}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drafts, err := Compose(tt.event)
+			if err != nil {
+				t.Fatalf("Compose: %v", err)
+			}
+			if len(drafts) != len(tt.users) {
+				t.Fatalf("got %d drafts, want %d", len(drafts), len(tt.users))
+			}
+			for i, d := range drafts {
+				if d.UserID != tt.users[i] {
+					t.Errorf("draft %d user = %s, want %s", i, d.UserID, tt.users[i])
+				}
+				if d.Subject == "" || d.Body == "" {
+					t.Errorf("draft %d has empty text", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	single := Combine([]*domain.Notification{{Subject: "a", Body: "b"}})
+	if single.Subject != "a" || single.Body != "b" {
+		t.Errorf("single = %+v", single)
+	}
+
+	multi := Combine([]*domain.Notification{{Subject: "a", Body: "b"}, {Subject: "c", Body: "d"}})
+	if multi.Subject != "2 updates on your reviews" {
+		t.Errorf("subject = %q", multi.Subject)
+	}
+	if !strings.Contains(multi.Body, "a\nb") || !strings.Contains(multi.Body, "c\nd") {
+		t.Errorf("body = %q", multi.Body)
+	}
+}
+
+func TestChatChannel_Send(t *testing.T) {
+	var got chatPayload
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	ch := newChatChannel(time.Second, nil, nil)
+	err := ch.Send(context.Background(), Recipient{UserID: "u1", ChatWebhookURL: receiver.URL}, Message{Subject: "s", Body: "b"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.Text != "s\n\nb" {
+		t.Errorf("text = %q", got.Text)
+	}
+}
+
+func TestChatChannel_ErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	ch := newChatChannel(time.Second, nil, nil)
+	err := ch.Send(context.Background(), Recipient{UserID: "u1", ChatWebhookURL: receiver.URL}, Message{Subject: "s", Body: "b"})
+	if err == nil {
+		t.Fatal("expected error for 500 response")
+	}
+}
+
+func TestChatChannel_RejectsPrivateAddress(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	ch := NewChatChannel(time.Second, nil)
+	err := ch.Send(context.Background(), Recipient{UserID: "u1", ChatWebhookURL: srv.URL}, Message{Subject: "s"})
+	if !errors.Is(err, errForbiddenAddress) {
+		t.Fatalf("err = %v, want %v", err, errForbiddenAddress)
+	}
+	if called {
+		t.Error("loopback webhook must not be called")
+	}
+}
+
+func TestChatChannel_AllowedHosts(t *testing.T) {
+	ch := NewChatChannel(time.Second, []string{"Hooks.Slack.com"})
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://hooks.slack.com/services/x", true},
+		{"https://hooks.slack.com:8443/services/x", true},
+		{"https://evil.example.com/hooks.slack.com", false},
+		{"https://hooks.slack.com.evil.example.com/", false},
+	}
+	for _, tt := range tests {
+		if got := ch.allowed(tt.url); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	sla := domain.ReviewSLA{Target: 48 * time.Hour, Warning: 24 * time.Hour}
+	d := domain.NewDigest("u1", []*domain.PullRequestShort{
+		{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u2", CreatedAt: now.Add(-53 * time.Hour)},
+		{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u3", CreatedAt: now.Add(-3 * time.Hour)},
+	}, sla, now)
+
+	msg := RenderDigest(d)
+	if msg.Subject != "Daily review digest: 2 open, 1 overdue" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+	want := "- pr-1 \"Add search\" by u2, open for 2d 5h [BREACHED]\n- pr-2 \"Fix login\" by u3, open for 3h [OK]"
+	if msg.Body != want {
+		t.Errorf("body = %q, want %q", msg.Body, want)
+	}
+}
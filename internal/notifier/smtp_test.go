@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer принимает одно соединение и отвечает на минимальный набор
+// команд SMTP, сохраняя полученное письмо.
+func fakeSMTPServer(t *testing.T, rejectRcpt bool) (string, <-chan receivedMail) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	mails := make(chan receivedMail, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 fake.smtp ESMTP")
+
+		var m receivedMail
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				_ = tp.PrintfLine("250-fake.smtp")
+				_ = tp.PrintfLine("250 8BITMIME")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				m.from = angleAddr(line)
+				_ = tp.PrintfLine("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				if rejectRcpt {
+					_ = tp.PrintfLine("550 no such user")
+					continue
+				}
+				m.to = append(m.to, angleAddr(line))
+				_ = tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				m.data = string(data)
+				_ = tp.PrintfLine("250 queued")
+				mails <- m
+			case cmd == "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+	return ln.Addr().String(), mails
+}
+
+func angleAddr(line string) string {
+	start := strings.IndexByte(line, '<')
+	end := strings.IndexByte(line, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func TestSMTPChannel_Send(t *testing.T) {
+	addr, mails := fakeSMTPServer(t, false)
+	ch := NewSMTPChannel(SMTPConfig{Addr: addr, From: "reviews@example.com", Timeout: 2 * time.Second})
+
+	msg := Message{Subject: "Review requested: pr-1", Body: "line one\n.line two"}
+	if err := ch.Send(context.Background(), Recipient{UserID: "u1", Email: "alice@example.com"}, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var m receivedMail
+	select {
+	case m = <-mails:
+	case <-time.After(2 * time.Second):
+		t.Fatal("server did not receive mail")
+	}
+	if m.from != "reviews@example.com" {
+		t.Errorf("MAIL FROM = %q", m.from)
+	}
+	if len(m.to) != 1 || m.to[0] != "alice@example.com" {
+		t.Errorf("RCPT TO = %v", m.to)
+	}
+
+	r := textproto.NewReader(bufio.NewReader(strings.NewReader(m.data)))
+	header, err := r.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("parse headers: %v", err)
+	}
+	if got := header.Get("Subject"); got != msg.Subject {
+		t.Errorf("Subject = %q", got)
+	}
+	if got := header.Get("To"); got != "<alice@example.com>" {
+		t.Errorf("To = %q", got)
+	}
+	if !strings.Contains(m.data, "line one\n.line two") {
+		t.Errorf("body not preserved: %q", m.data)
+	}
+}
+
+func TestSMTPChannel_RejectedRecipient(t *testing.T) {
+	addr, _ := fakeSMTPServer(t, true)
+	ch := NewSMTPChannel(SMTPConfig{Addr: addr, From: "reviews@example.com", Timeout: 2 * time.Second})
+
+	err := ch.Send(context.Background(), Recipient{UserID: "u1", Email: "ghost@example.com"}, Message{Subject: "s", Body: "b"})
+	if err == nil || !strings.Contains(err.Error(), "550") {
+		t.Fatalf("expected 550 error, got %v", err)
+	}
+}
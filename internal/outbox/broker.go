@@ -0,0 +1,99 @@
+package outbox
+
+import (
+	"sync"
+
+	"avito/internal/domain"
+)
+
+// Broker раздаёт опубликованные события подписчикам внутри процесса
+// (SSE-потокам). Релей передаёт ему события только после коммита, так что
+// подписчики не видят того, что ещё может откатиться. Подписчик, не успевающий читать, отключается: клиент
+// переподключится с Last-Event-ID и дочитает пропущенное из outbox_events.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	buffer int
+	closed bool
+}
+
+type Subscription struct {
+	C <-chan *domain.Event
+
+	ch     chan *domain.Event
+	broker *Broker
+}
+
+func NewBroker(buffer int) *Broker {
+	return &Broker{
+		subs:   make(map[*Subscription]struct{}),
+		buffer: buffer,
+	}
+}
+
+func (b *Broker) Subscribe() *Subscription {
+	ch := make(chan *domain.Event, b.buffer)
+	sub := &Subscription{C: ch, ch: ch, broker: b}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return sub
+	}
+	b.subs[sub] = struct{}{}
+	return sub
+}
+
+// Close отключает всех подписчиков, например при остановке сервера.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		b.remove(sub)
+	}
+}
+
+// Close отписывает подписчика; повторный вызов безопасен.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+func (b *Broker) remove(s *Subscription) {
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.ch)
+	}
+}
+
+func (b *Broker) Deliver(events []*domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		for _, e := range events {
+			if !sub.offer(e) {
+				b.remove(sub)
+				break
+			}
+		}
+	}
+}
+
+func (s *Subscription) offer(e *domain.Event) bool {
+	select {
+	case s.ch <- e:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *Broker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
@@ -0,0 +1,58 @@
+package outbox_test
+
+import (
+	"testing"
+
+	"avito/internal/outbox"
+)
+
+func TestBroker_FanOut(t *testing.T) {
+	broker := outbox.NewBroker(4)
+	first := broker.Subscribe()
+	second := broker.Subscribe()
+	defer first.Close()
+	defer second.Close()
+
+	broker.Deliver(events(t, 1, 2))
+
+	for _, sub := range []*outbox.Subscription{first, second} {
+		for _, want := range []int64{1, 2} {
+			if got := (<-sub.C).ID; got != want {
+				t.Errorf("received event %d, want %d", got, want)
+			}
+		}
+	}
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := outbox.NewBroker(1)
+	slow := broker.Subscribe()
+	defer slow.Close()
+
+	broker.Deliver(events(t, 1, 2))
+	if broker.Subscribers() != 0 {
+		t.Fatalf("Subscribers() = %d, want slow subscriber removed", broker.Subscribers())
+	}
+
+	if e, ok := <-slow.C; !ok || e.ID != 1 {
+		t.Fatalf("expected buffered event 1 before close")
+	}
+	if _, ok := <-slow.C; ok {
+		t.Error("channel of dropped subscriber should be closed")
+	}
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := outbox.NewBroker(1)
+	sub := broker.Subscribe()
+
+	broker.Close()
+	sub.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Error("subscription should be closed after broker shutdown")
+	}
+	if _, ok := <-broker.Subscribe().C; ok {
+		t.Error("subscribe after shutdown should return closed channel")
+	}
+}
@@ -0,0 +1,137 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository/postgres"
+	"avito/pkg/logger"
+)
+
+// Sink получает события outbox пачками в порядке записи. tx — транзакция
+// релея: sink, пишущий в ту же БД, должен использовать её, чтобы публикация
+// и отметка о ней фиксировались атомарно. Остальные sink'и получают события
+// не менее одного раза и могут отбрасывать повторы по Event.ID.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, tx *sql.Tx, events []*domain.Event) error
+}
+
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// Relay переносит неопубликованные события из outbox_events в sink'и.
+// Событие помечается опубликованным только после того, как его приняли
+// все sink'и, поэтому падение процесса между коммитом изменения и
+// публикацией приводит лишь к повторной отправке, но не к потере.
+type Relay struct {
+	db     *postgres.DB
+	sinks  []Sink
+	broker *Broker
+	cfg    Config
+	logger *logger.Logger
+}
+
+type RelayOption func(*Relay)
+
+// WithBroker раздаёт события подписчикам broker'а после коммита пачки,
+// когда они уже видны в журнале и имеют Seq.
+func WithBroker(b *Broker) RelayOption {
+	return func(r *Relay) {
+		r.broker = b
+	}
+}
+
+func NewRelay(db *postgres.DB, sinks []Sink, cfg Config, logger *logger.Logger, opts ...RelayOption) *Relay {
+	r := &Relay{
+		db:     db,
+		sinks:  sinks,
+		cfg:    cfg,
+		logger: logger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	r.logger.Info("Outbox relay started", "sinks", r.sinkNames())
+	ticker := time.NewTicker(r.cfg.PollInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Outbox relay shutting down")
+			return
+		case <-ticker.C:
+			r.drain(ctx)
+		}
+	}
+}
+
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		count, err := r.PublishBatch(ctx)
+		if err != nil {
+			r.logger.Error("Failed to publish outbox events", "error", err)
+			return
+		}
+		if count < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// PublishBatch публикует одну пачку событий и возвращает её размер.
+func (r *Relay) PublishBatch(ctx context.Context) (int, error) {
+	var published []*domain.Event
+	err := r.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txEventRepo := postgres.NewEventRepository(tx)
+
+		events, err := txEventRepo.LockUnpublished(ctx, r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		for _, sink := range r.sinks {
+			if err := sink.Publish(ctx, tx, events); err != nil {
+				return fmt.Errorf("sink %s: %w", sink.Name(), err)
+			}
+		}
+
+		if err := txEventRepo.MarkPublished(ctx, events); err != nil {
+			return err
+		}
+		published = events
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(published) > 0 {
+		if r.broker != nil {
+			r.broker.Deliver(published)
+		}
+		r.logger.Debug("Outbox events published", "count", len(published))
+	}
+	return len(published), nil
+}
+
+func (r *Relay) sinkNames() []string {
+	names := make([]string, 0, len(r.sinks))
+	for _, sink := range r.sinks {
+		names = append(names, sink.Name())
+	}
+	return names
+}
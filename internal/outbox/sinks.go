@@ -0,0 +1,186 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/notifier"
+	"avito/internal/repository/postgres"
+	"avito/pkg/logger"
+)
+
+const (
+	SinkLog     = "log"
+	SinkWebhook = "webhook"
+	SinkFile    = "file"
+	SinkNotify  = "notify"
+)
+
+// LogSink пишет каждое событие в лог приложения.
+type LogSink struct {
+	logger *logger.Logger
+}
+
+func NewLogSink(logger *logger.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (s *LogSink) Name() string { return SinkLog }
+
+func (s *LogSink) Publish(_ context.Context, _ *sql.Tx, events []*domain.Event) error {
+	for _, e := range events {
+		s.logger.Info("Domain event",
+			"event_id", e.ID,
+			"event_type", e.Type,
+			"aggregate_id", e.AggregateID,
+			"payload", string(e.Payload),
+		)
+	}
+	return nil
+}
+
+// WebhookSink раскладывает события по активным подпискам в webhook_deliveries
+// в транзакции релея; саму отправку выполняет WebhookWorker.
+type WebhookSink struct{}
+
+func NewWebhookSink() *WebhookSink {
+	return &WebhookSink{}
+}
+
+func (s *WebhookSink) Name() string { return SinkWebhook }
+
+func (s *WebhookSink) Publish(ctx context.Context, tx *sql.Tx, events []*domain.Event) error {
+	txWebhookRepo := postgres.NewWebhookRepository(tx)
+
+	subs, err := txWebhookRepo.GetActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, e := range events {
+		for _, sub := range subs {
+			if !sub.Accepts(e.Type) {
+				continue
+			}
+			if err := txWebhookRepo.CreateDelivery(ctx, sub.ID, e.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NotificationSink превращает события в уведомления пользователям, у которых
+// настроен хотя бы один канал, и планирует их с учётом тихих часов и режима
+// дайджеста; отправку выполняет NotificationWorker.
+type NotificationSink struct {
+	now func() time.Time
+}
+
+func NewNotificationSink() *NotificationSink {
+	return &NotificationSink{now: time.Now}
+}
+
+func (s *NotificationSink) Name() string { return SinkNotify }
+
+func (s *NotificationSink) Publish(ctx context.Context, tx *sql.Tx, events []*domain.Event) error {
+	txNotificationRepo := postgres.NewNotificationRepository(tx)
+
+	drafts := make(map[*domain.Event][]notifier.Draft, len(events))
+	var userIDs []string
+	for _, e := range events {
+		eventDrafts, err := notifier.Compose(e)
+		if err != nil {
+			return err
+		}
+		drafts[e] = eventDrafts
+		for _, d := range eventDrafts {
+			userIDs = append(userIDs, d.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	prefs, err := txNotificationRepo.GetPreferencesByUsers(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	for _, e := range events {
+		for _, d := range drafts[e] {
+			p, ok := prefs[d.UserID]
+			if !ok || len(p.Channels) == 0 {
+				continue
+			}
+			n := &domain.Notification{
+				UserID:       d.UserID,
+				EventID:      e.ID,
+				EventType:    e.Type,
+				Subject:      d.Subject,
+				Body:         d.Body,
+				DeliverAfter: p.NextDelivery(now),
+			}
+			if err := txNotificationRepo.Create(ctx, n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FileRecord — строка JSON Lines, которую пишет FileSink.
+type FileRecord struct {
+	ID          int64           `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// FileSink дописывает события в файл в формате JSON Lines и делает fsync
+// до того, как релей отметит их опубликованными.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Name() string { return SinkFile }
+
+func (s *FileSink) Publish(_ context.Context, _ *sql.Tx, events []*domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open event file: %w", err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		record := FileRecord{
+			ID:          e.ID,
+			Type:        e.Type,
+			AggregateID: e.AggregateID,
+			CreatedAt:   e.CreatedAt,
+			Payload:     e.Payload,
+		}
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to write event %d: %w", e.ID, err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync event file: %w", err)
+	}
+	return nil
+}
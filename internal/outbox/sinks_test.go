@@ -0,0 +1,90 @@
+package outbox_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/outbox"
+	"avito/pkg/logger"
+)
+
+func events(t *testing.T, ids ...int64) []*domain.Event {
+	t.Helper()
+	result := make([]*domain.Event, 0, len(ids))
+	for _, id := range ids {
+		e, err := domain.NewEvent(domain.EventPRMerged, "pr-1", map[string]string{"pull_request_id": "pr-1"})
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		e.ID = id
+		result = append(result, e)
+	}
+	return result
+}
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := outbox.NewFileSink(path)
+	ctx := context.Background()
+
+	if err := sink.Publish(ctx, nil, events(t, 1, 2)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	// Повторная публикация после сбоя релея дописывает те же события.
+	if err := sink.Publish(ctx, nil, events(t, 2, 3)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open event file: %v", err)
+	}
+	defer f.Close()
+
+	var ids []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record outbox.FileRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		if record.Type != domain.EventPRMerged || record.AggregateID != "pr-1" {
+			t.Errorf("unexpected record: %+v", record)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	want := []int64{1, 2, 2, 3}
+	if len(ids) != len(want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("ids = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestLogSink_LogsEveryEvent(t *testing.T) {
+	var buf bytes.Buffer
+	sink := outbox.NewLogSink(logger.NewWithWriter(&buf, "info", "json"))
+
+	if err := sink.Publish(context.Background(), nil, events(t, 10, 11)); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], `"event_id":10`) || !strings.Contains(lines[1], `"event_type":"pr.merged"`) {
+		t.Errorf("unexpected log output: %s", buf.String())
+	}
+}
@@ -0,0 +1,107 @@
+// Package ratelimit реализует ограничение частоты запросов алгоритмом
+// token bucket. Состояние хранится в памяти процесса, поэтому при нескольких
+// репликах лимит действует на каждую отдельно.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval — как часто из памяти удаляются заполненные бакеты.
+const sweepInterval = time.Minute
+
+// Limit задаёт скорость пополнения (запросов в секунду) и ёмкость бакета.
+type Limit struct {
+	RPS   float64
+	Burst int
+}
+
+// Result описывает решение по запросу и состояние бакета после него.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter — через сколько появится следующий токен; 0, если запрос
+	// разрешён.
+	RetryAfter time.Duration
+	// Reset — через сколько бакет заполнится полностью.
+	Reset time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func New() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow списывает токен из бакета key. Бакет создаётся заполненным; если
+// лимит для ключа изменился, бакет продолжает работать с новым лимитом.
+func (l *Limiter) Allow(key string, limit Limit) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.limit = limit
+	b.refill(now)
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.RPS)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = secondsToDuration((float64(limit.Burst) - b.tokens) / limit.RPS)
+	return result
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.RPS)
+	}
+	b.updated = now
+}
+
+// sweep удаляет бакеты, которые успели заполниться: новый бакет для того же
+// ключа будет в том же состоянии.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+	limit := Limit{RPS: 2, Burst: 3}
+
+	for i := range 3 {
+		res := l.Allow("client", limit)
+		if !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("request %d: got %+v", i, res)
+		}
+	}
+
+	res := l.Allow("client", limit)
+	if res.Allowed {
+		t.Fatal("expected burst to be exhausted")
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("RetryAfter = %v, want 500ms", res.RetryAfter)
+	}
+	if res.Reset != 1500*time.Millisecond {
+		t.Errorf("Reset = %v, want 1.5s", res.Reset)
+	}
+
+	if other := l.Allow("other", limit); !other.Allowed {
+		t.Error("expected separate bucket for another key")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if res := l.Allow("client", limit); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after refill: got %+v", res)
+	}
+
+	now = now.Add(time.Hour)
+	if res := l.Allow("client", limit); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("bucket must not exceed burst: got %+v", res)
+	}
+}
+
+func TestLimiter_SweepsFullBuckets(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := New()
+	l.now = func() time.Time { return now }
+
+	l.Allow("idle", Limit{RPS: 1, Burst: 1})
+	l.Allow("busy", Limit{RPS: 0.001, Burst: 1})
+
+	now = now.Add(2 * sweepInterval)
+	l.Allow("new", Limit{RPS: 1, Burst: 5})
+
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("expected refilled bucket to be swept")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("expected bucket that is still draining to be kept")
+	}
+}
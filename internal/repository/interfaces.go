@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"avito/internal/domain"
 )
@@ -14,6 +16,7 @@ type TeamRepository interface {
 	ExistsByID(ctx context.Context, teamID int) (bool, error)
 	List(ctx context.Context) ([]*domain.Team, error)
 	Count(ctx context.Context) (int, error)
+	LockVersion(ctx context.Context, teamID, expected int) error
 }
 
 type UserRepository interface {
@@ -42,13 +45,86 @@ type PullRequestRepository interface {
 	GetByReviewer(ctx context.Context, userID string, openStatusID int16) ([]*domain.PullRequestShort, error)
 	GetByAuthor(ctx context.Context, authorID string) ([]*domain.PullRequestShort, error)
 	GetOpenPRs(ctx context.Context) ([]*domain.PullRequest, error)
+	GetUnderstaffedByTeam(ctx context.Context, teamID int, maxReviewers int) ([]*domain.PullRequest, error)
 	List(ctx context.Context) ([]*domain.PullRequest, error)
+	Search(ctx context.Context, filter *domain.PRFilter) (*domain.PRPage, error)
 	Count(ctx context.Context) (int, error)
 	Merge(ctx context.Context, prID string, mergedStatusID int16) (*domain.PullRequest, error)
+	LockVersion(ctx context.Context, prID string, expected int) error
 }
 
 type TaskRepository interface {
 	CreateDeactivateTask(ctx context.Context, teamID int) error
 	GetAndLockPendingTask(ctx context.Context) (*domain.BatchDeactivateTask, error)
 	SetTaskStatus(ctx context.Context, taskID int, status string, errorMessage string) error
+	CreateFillTask(ctx context.Context, teamID int, trigger string) error
+	GetAndLockPendingFillTask(ctx context.Context) (*domain.ReviewerFillTask, error)
+	SetFillTaskStatus(ctx context.Context, taskID int, status string, filledCount int, errorMessage string) error
+}
+
+type AuditRepository interface {
+	Add(ctx context.Context, entry *domain.AssignmentAudit) error
+	GetByPR(ctx context.Context, prID string) ([]*domain.AssignmentAudit, error)
+}
+
+type DecisionRepository interface {
+	Create(ctx context.Context, d *domain.AssignmentDecision) error
+	GetByPR(ctx context.Context, prID string) ([]*domain.AssignmentDecision, error)
+	RecentPairCounts(ctx context.Context, authorID string, window int) (map[string]int, error)
+	PairingMatrix(ctx context.Context, teamID, window int) ([]*domain.PairingCount, error)
+}
+
+type ExternalAccountRepository interface {
+	Link(ctx context.Context, account *domain.ExternalAccount) error
+	Unlink(ctx context.Context, provider, login string) error
+	ResolveUserID(ctx context.Context, provider, login string) (string, error)
+	ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error)
+}
+
+type EventRepository interface {
+	Add(ctx context.Context, e *domain.Event) error
+	LockUnpublished(ctx context.Context, limit int) ([]*domain.Event, error)
+	ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.Event, error)
+	LatestSeq(ctx context.Context) (int64, error)
+	MarkPublished(ctx context.Context, events []*domain.Event) error
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	GetActiveSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	SubscriptionExists(ctx context.Context, subscriptionID int) (bool, error)
+	CreateDelivery(ctx context.Context, subscriptionID int, eventID int64) error
+	ClaimDueDelivery(ctx context.Context, lease time.Duration) (*domain.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, d *domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+type NotificationRepository interface {
+	UpsertPreferences(ctx context.Context, p *domain.NotificationPreferences) error
+	GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	GetPreferencesByUsers(ctx context.Context, userIDs []string) (map[string]*domain.NotificationPreferences, error)
+	ListDigestSubscribers(ctx context.Context) ([]*domain.NotificationPreferences, error)
+	ClaimDigest(ctx context.Context, userID string, slot time.Time) (bool, error)
+	Create(ctx context.Context, n *domain.Notification) error
+	ClaimDue(ctx context.Context, lease time.Duration) ([]*domain.Notification, error)
+	MarkChannelSent(ctx context.Context, ids []int64, channel string) error
+	RecordResult(ctx context.Context, ids []int64, status string, deliverAfter time.Time, errorMessage sql.NullString) error
+}
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, rec *domain.IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, rec *domain.IdempotencyRecord) error
+	Delete(ctx context.Context, key, token string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type APITokenRepository interface {
+	Create(ctx context.Context, t *domain.APIToken) error
+	GetByHash(ctx context.Context, hash string) (*domain.APIToken, error)
+	List(ctx context.Context) ([]*domain.APIToken, error)
+	Revoke(ctx context.Context, id int64) error
+	AddAudit(ctx context.Context, e *domain.TokenAuditEntry) error
+	ListAudit(ctx context.Context, tokenID int64, limit int) ([]*domain.TokenAuditEntry, error)
 }
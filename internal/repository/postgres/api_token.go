@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+type APITokenRepository struct {
+	db DBTX
+}
+
+func NewAPITokenRepository(db DBTX) *APITokenRepository {
+	return &APITokenRepository{db: traced(db)}
+}
+
+func (r *APITokenRepository) Create(ctx context.Context, t *domain.APIToken) error {
+	query := `
+        INSERT INTO api_tokens (name, prefix, token_hash, scopes, user_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	var userID sql.NullString
+	if t.UserID != "" {
+		userID = sql.NullString{String: t.UserID, Valid: true}
+	}
+	err := r.db.QueryRowContext(ctx, query, t.Name, t.Prefix, t.Hash, pq.Array(t.Scopes), userID).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create api token: %w", err)
+	}
+	return nil
+}
+
+func (r *APITokenRepository) GetByHash(ctx context.Context, hash string) (*domain.APIToken, error) {
+	query := `
+        SELECT id, name, prefix, token_hash, scopes, user_id, created_at, revoked_at
+        FROM api_tokens
+        WHERE token_hash = $1
+    `
+	t, err := scanAPIToken(r.db.QueryRowContext(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+	return t, nil
+}
+
+func (r *APITokenRepository) List(ctx context.Context) ([]*domain.APIToken, error) {
+	query := `
+        SELECT id, name, prefix, token_hash, scopes, user_id, created_at, revoked_at
+        FROM api_tokens
+        ORDER BY id
+    `
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api tokens: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := []*domain.APIToken{}
+	for rows.Next() {
+		t, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %w", err)
+		}
+		tokens = append(tokens, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *APITokenRepository) Revoke(ctx context.Context, id int64) error {
+	query := `
+        UPDATE api_tokens
+        SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+        WHERE id = $1
+    `
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api token: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *APITokenRepository) AddAudit(ctx context.Context, e *domain.TokenAuditEntry) error {
+	query := `
+        INSERT INTO api_token_audit (token_id, method, route, status, request_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRowContext(ctx, query, e.TokenID, e.Method, e.Route, e.Status, e.RequestID).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add api token audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *APITokenRepository) ListAudit(ctx context.Context, tokenID int64, limit int) ([]*domain.TokenAuditEntry, error) {
+	query := `
+        SELECT id, token_id, method, route, status, request_id, created_at
+        FROM api_token_audit
+        WHERE token_id = $1
+        ORDER BY id DESC
+        LIMIT $2
+    `
+	rows, err := r.db.QueryContext(ctx, query, tokenID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api token audit: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.TokenAuditEntry{}
+	for rows.Next() {
+		var e domain.TokenAuditEntry
+		if err := rows.Scan(&e.ID, &e.TokenID, &e.Method, &e.Route, &e.Status, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan api token audit entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating api token audit: %w", err)
+	}
+	return entries, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIToken(row rowScanner) (*domain.APIToken, error) {
+	var t domain.APIToken
+	var userID sql.NullString
+	var revokedAt sql.NullTime
+	if err := row.Scan(&t.ID, &t.Name, &t.Prefix, &t.Hash, pq.Array(&t.Scopes), &userID, &t.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	t.UserID = userID.String
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
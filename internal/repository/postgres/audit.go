@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type AuditRepository struct {
+	db DBTX
+}
+
+func NewAuditRepository(db DBTX) *AuditRepository {
+	return &AuditRepository{db: traced(db)}
+}
+
+func (r *AuditRepository) Add(ctx context.Context, entry *domain.AssignmentAudit) error {
+	query := `
+        INSERT INTO assignment_audit (pull_request_id, user_id, action, source, task_id)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRowContext(ctx, query,
+		entry.PullRequestID,
+		entry.UserID,
+		entry.Action,
+		entry.Source,
+		entry.TaskID,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add assignment audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) GetByPR(ctx context.Context, prID string) ([]*domain.AssignmentAudit, error) {
+	query := `
+        SELECT id, pull_request_id, user_id, action, source, task_id, created_at
+        FROM assignment_audit
+        WHERE pull_request_id = $1
+        ORDER BY id
+    `
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignment audit: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AssignmentAudit
+	for rows.Next() {
+		var entry domain.AssignmentAudit
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.PullRequestID,
+			&entry.UserID,
+			&entry.Action,
+			&entry.Source,
+			&entry.TaskID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignment audit: %w", err)
+	}
+	return entries, nil
+}
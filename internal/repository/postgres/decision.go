@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+type DecisionRepository struct {
+	db DBTX
+}
+
+func NewDecisionRepository(db DBTX) *DecisionRepository {
+	return &DecisionRepository{db: traced(db)}
+}
+
+func (r *DecisionRepository) Create(ctx context.Context, d *domain.AssignmentDecision) error {
+	query := `
+        INSERT INTO assignment_decisions
+            (pull_request_id, kind, strategy, reviewer_rule, seed, slots, candidates, candidate_levels,
+             candidate_penalties, kept_levels, exclusions, selected, rule_violated, replaced_user_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+        RETURNING id, created_at
+    `
+	excluded := d.Excluded
+	if excluded == nil {
+		excluded = []domain.Exclusion{}
+	}
+	exclusions, err := json.Marshal(excluded)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exclusions: %w", err)
+	}
+	var replaced sql.NullString
+	if d.ReplacedUserID != "" {
+		replaced = sql.NullString{String: d.ReplacedUserID, Valid: true}
+	}
+	err = r.db.QueryRowContext(ctx, query,
+		d.PullRequestID,
+		d.Kind,
+		d.Strategy,
+		d.Rule.OrDefault(),
+		d.Seed,
+		d.Slots,
+		pq.Array(d.Candidates),
+		pq.Array(levelsToStrings(d.CandidateLevels)),
+		pq.Array(intsToInt64s(d.CandidatePenalties)),
+		pq.Array(levelsToStrings(d.KeptLevels)),
+		exclusions,
+		pq.Array(d.Selected),
+		d.RuleViolated,
+		replaced,
+	).Scan(&d.ID, &d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create assignment decision: %w", err)
+	}
+	return nil
+}
+
+func (r *DecisionRepository) GetByPR(ctx context.Context, prID string) ([]*domain.AssignmentDecision, error) {
+	query := `
+        SELECT id, pull_request_id, kind, strategy, reviewer_rule, seed, slots, candidates, candidate_levels,
+               candidate_penalties, kept_levels, exclusions, selected, rule_violated, replaced_user_id, created_at
+        FROM assignment_decisions
+        WHERE pull_request_id = $1
+        ORDER BY id
+    `
+	rows, err := r.db.QueryContext(ctx, query, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignment decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var decisions []*domain.AssignmentDecision
+	for rows.Next() {
+		var d domain.AssignmentDecision
+		var replaced sql.NullString
+		var exclusions []byte
+		var candidateLevels, keptLevels []string
+		var penalties []int64
+		if err := rows.Scan(
+			&d.ID,
+			&d.PullRequestID,
+			&d.Kind,
+			&d.Strategy,
+			&d.Rule,
+			&d.Seed,
+			&d.Slots,
+			pq.Array(&d.Candidates),
+			pq.Array(&candidateLevels),
+			pq.Array(&penalties),
+			pq.Array(&keptLevels),
+			&exclusions,
+			pq.Array(&d.Selected),
+			&d.RuleViolated,
+			&replaced,
+			&d.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment decision: %w", err)
+		}
+		if err := json.Unmarshal(exclusions, &d.Excluded); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal exclusions: %w", err)
+		}
+		d.CandidateLevels = stringsToLevels(candidateLevels)
+		d.KeptLevels = stringsToLevels(keptLevels)
+		d.CandidatePenalties = int64sToInts(penalties)
+		d.ReplacedUserID = replaced.String
+		decisions = append(decisions, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assignment decisions: %w", err)
+	}
+	return decisions, nil
+}
+
+// RecentPairCounts считает, сколько раз каждый ревьюер назначался на PR автора
+// в последних window решениях.
+func (r *DecisionRepository) RecentPairCounts(ctx context.Context, authorID string, window int) (map[string]int, error) {
+	query := `
+        SELECT reviewer_id, COUNT(*)
+        FROM (
+            SELECT d.selected
+            FROM assignment_decisions d
+            INNER JOIN pull_requests p ON p.id = d.pull_request_id
+            WHERE p.author_id = $1
+            ORDER BY d.id DESC
+            LIMIT $2
+        ) recent, unnest(recent.selected) AS reviewer_id
+        GROUP BY reviewer_id
+    `
+	rows, err := r.db.QueryContext(ctx, query, authorID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent pair counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var reviewerID string
+		var count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan pair count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pair counts: %w", err)
+	}
+	return counts, nil
+}
+
+// PairingMatrix считает пары автор–ревьюер команды по последним window
+// решениям каждого автора — тому же окну, что видит стратегия fair в
+// RecentPairCounts.
+func (r *DecisionRepository) PairingMatrix(ctx context.Context, teamID, window int) ([]*domain.PairingCount, error) {
+	query := `
+        SELECT recent.author_id, reviewer_id, COUNT(*)
+        FROM (
+            SELECT p.author_id, d.selected,
+                   ROW_NUMBER() OVER (PARTITION BY p.author_id ORDER BY d.id DESC) AS rn
+            FROM assignment_decisions d
+            INNER JOIN pull_requests p ON p.id = d.pull_request_id
+            INNER JOIN users u ON u.id = p.author_id
+            WHERE u.team_id = $1
+        ) recent
+        CROSS JOIN unnest(recent.selected) AS reviewer_id
+        WHERE recent.rn <= $2
+        GROUP BY recent.author_id, reviewer_id
+        ORDER BY recent.author_id, reviewer_id
+    `
+	rows, err := r.db.QueryContext(ctx, query, teamID, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pairing matrix: %w", err)
+	}
+	defer rows.Close()
+
+	var pairs []*domain.PairingCount
+	for rows.Next() {
+		var pair domain.PairingCount
+		if err := rows.Scan(&pair.AuthorID, &pair.ReviewerID, &pair.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan pairing count: %w", err)
+		}
+		pairs = append(pairs, &pair)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pairing matrix: %w", err)
+	}
+	return pairs, nil
+}
+
+func levelsToStrings(levels []domain.Seniority) []string {
+	result := make([]string, 0, len(levels))
+	for _, l := range levels {
+		result = append(result, string(l.OrDefault()))
+	}
+	return result
+}
+
+func stringsToLevels(values []string) []domain.Seniority {
+	result := make([]domain.Seniority, 0, len(values))
+	for _, v := range values {
+		result = append(result, domain.Seniority(v))
+	}
+	return result
+}
+
+func intsToInt64s(values []int) []int64 {
+	result := make([]int64, 0, len(values))
+	for _, v := range values {
+		result = append(result, int64(v))
+	}
+	return result
+}
+
+func int64sToInts(values []int64) []int {
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		result = append(result, int(v))
+	}
+	return result
+}
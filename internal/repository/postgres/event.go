@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+// publishLockID сериализует отметку о публикации между релеями: блокировка
+// держится до коммита, поэтому publish_seq растёт в порядке коммитов.
+const publishLockID = 7340211
+
+type EventRepository struct {
+	db DBTX
+}
+
+func NewEventRepository(db DBTX) *EventRepository {
+	return &EventRepository{db: traced(db)}
+}
+
+func (r *EventRepository) Add(ctx context.Context, e *domain.Event) error {
+	query := `
+        INSERT INTO outbox_events (event_type, aggregate_id, payload)
+        VALUES ($1, $2, $3)
+        RETURNING id, created_at
+    `
+	err := r.db.QueryRowContext(ctx, query, e.Type, e.AggregateID, []byte(e.Payload)).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
+
+// LockUnpublished блокирует до limit неопубликованных событий в порядке записи.
+// Вызывается внутри транзакции, которая затем помечает их опубликованными.
+func (r *EventRepository) LockUnpublished(ctx context.Context, limit int) ([]*domain.Event, error) {
+	query := `
+        SELECT id, COALESCE(publish_seq, 0), event_type, aggregate_id, payload, created_at
+        FROM outbox_events
+        WHERE published_at IS NULL
+        ORDER BY id
+        FOR UPDATE SKIP LOCKED
+        LIMIT $1
+    `
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock unpublished events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// ListAfter возвращает опубликованные события с publish_seq больше afterSeq —
+// журнал для возобновления потоков по Last-Event-ID. В отличие от id,
+// publish_seq не может появиться «позади» уже прочитанного курсора.
+func (r *EventRepository) ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.Event, error) {
+	query := `
+        SELECT id, publish_seq, event_type, aggregate_id, payload, created_at
+        FROM outbox_events
+        WHERE publish_seq > $1
+        ORDER BY publish_seq
+        LIMIT $2
+    `
+	rows, err := r.db.QueryContext(ctx, query, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	defer rows.Close()
+
+	return scanEvents(rows)
+}
+
+// LatestSeq возвращает позицию последнего опубликованного события, 0 — если
+// журнал пуст.
+func (r *EventRepository) LatestSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(publish_seq), 0) FROM outbox_events`).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest publish seq: %w", err)
+	}
+	return seq, nil
+}
+
+// MarkPublished помечает события опубликованными и назначает им publish_seq
+// в порядке id, заполняя Event.Seq. Вызывается в транзакции релея.
+func (r *EventRepository) MarkPublished(ctx context.Context, events []*domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, publishLockID); err != nil {
+		return fmt.Errorf("failed to lock outbox publish sequence: %w", err)
+	}
+
+	ids := make([]int64, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	query := `
+        UPDATE outbox_events e
+        SET published_at = CURRENT_TIMESTAMP,
+            publish_seq = s.seq
+        FROM (
+            SELECT id, nextval('outbox_events_publish_seq') AS seq
+            FROM (SELECT id FROM outbox_events WHERE id = ANY($1) ORDER BY id) ordered
+        ) s
+        WHERE e.id = s.id
+        RETURNING e.id, e.publish_seq
+    `
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to mark events published: %w", err)
+	}
+	defer rows.Close()
+
+	seqs := make(map[int64]int64, len(events))
+	for rows.Next() {
+		var id, seq int64
+		if err := rows.Scan(&id, &seq); err != nil {
+			return fmt.Errorf("failed to scan publish sequence: %w", err)
+		}
+		seqs[id] = seq
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating publish sequences: %w", err)
+	}
+	for _, e := range events {
+		e.Seq = seqs[e.ID]
+	}
+	return nil
+}
+
+func scanEvents(rows *sql.Rows) ([]*domain.Event, error) {
+	var events []*domain.Event
+	for rows.Next() {
+		var e domain.Event
+		if err := rows.Scan(&e.ID, &e.Seq, &e.Type, &e.AggregateID, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		events = append(events, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox events: %w", err)
+	}
+	return events, nil
+}
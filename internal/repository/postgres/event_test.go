@@ -0,0 +1,65 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/repository/postgres"
+)
+
+func TestEventRepository_UnpublishedSurvivesRollback(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+	truncateTables(t)
+
+	ctx := context.Background()
+	repo := postgres.NewEventRepository(testDB.DB)
+
+	for _, prID := range []string{"pr-1", "pr-2"} {
+		e, err := domain.NewEvent(domain.EventPRCreated, prID, map[string]string{"pull_request_id": prID})
+		if err != nil {
+			t.Fatalf("NewEvent() error = %v", err)
+		}
+		if err := repo.Add(ctx, e); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	// Релей упал после получения событий, но до фиксации отметки о публикации.
+	tx, err := testDB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx() error = %v", err)
+	}
+	locked, err := postgres.NewEventRepository(tx).LockUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatalf("LockUnpublished() error = %v", err)
+	}
+	if len(locked) != 2 {
+		t.Fatalf("LockUnpublished() returned %d events, want 2", len(locked))
+	}
+	if err := postgres.NewEventRepository(tx).MarkPublished(ctx, locked[:1]); err != nil {
+		t.Fatalf("MarkPublished() error = %v", err)
+	}
+	_ = tx.Rollback()
+
+	pending, err := repo.LockUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatalf("LockUnpublished() error = %v", err)
+	}
+	if len(pending) != 2 || pending[0].AggregateID != "pr-1" {
+		t.Fatalf("events after rollback = %d, want both still unpublished", len(pending))
+	}
+
+	if err := repo.MarkPublished(ctx, pending[:1]); err != nil {
+		t.Fatalf("MarkPublished() error = %v", err)
+	}
+	pending, err = repo.LockUnpublished(ctx, 10)
+	if err != nil {
+		t.Fatalf("LockUnpublished() error = %v", err)
+	}
+	if len(pending) != 1 || pending[0].AggregateID != "pr-2" {
+		t.Errorf("unpublished after commit = %+v, want only pr-2", pending)
+	}
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type ExternalAccountRepository struct {
+	db DBTX
+}
+
+func NewExternalAccountRepository(db DBTX) *ExternalAccountRepository {
+	return &ExternalAccountRepository{db: traced(db)}
+}
+
+const constraintExternalID = "idx_external_accounts_external_id"
+
+// Link привязывает логин к пользователю. Повторная привязка без ExternalID
+// сохраняет ранее записанный id.
+func (r *ExternalAccountRepository) Link(ctx context.Context, account *domain.ExternalAccount) error {
+	query := `
+        INSERT INTO external_accounts (provider, external_login, external_id, user_id)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (provider, external_login)
+        DO UPDATE SET user_id = EXCLUDED.user_id,
+                      external_id = COALESCE(EXCLUDED.external_id, external_accounts.external_id)
+        RETURNING COALESCE(external_id, 0), created_at
+    `
+	externalID := sql.NullInt64{Int64: account.ExternalID, Valid: account.ExternalID != 0}
+	err := r.db.QueryRowContext(ctx, query, account.Provider, account.Login, externalID, account.UserID).
+		Scan(&account.ExternalID, &account.CreatedAt)
+	if err != nil {
+		if isUniqueViolation(err, constraintExternalID) {
+			return domain.ErrExternalIDTaken
+		}
+		return fmt.Errorf("failed to link external account: %w", err)
+	}
+	return nil
+}
+
+func (r *ExternalAccountRepository) Unlink(ctx context.Context, provider, login string) error {
+	query := `
+        DELETE FROM external_accounts
+        WHERE provider = $1 AND external_login = $2
+    `
+	result, err := r.db.ExecContext(ctx, query, provider, login)
+	if err != nil {
+		return fmt.Errorf("failed to unlink external account: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ExternalAccountRepository) ResolveUserID(ctx context.Context, provider, login string) (string, error) {
+	query := `
+        SELECT user_id
+        FROM external_accounts
+        WHERE provider = $1 AND external_login = $2
+    `
+	var userID string
+	err := r.db.QueryRowContext(ctx, query, provider, login).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrAccountNotLinked
+		}
+		return "", fmt.Errorf("failed to resolve external account: %w", err)
+	}
+	return userID, nil
+}
+
+// ResolveUserIDByExternalID находит пользователя по числовому id во внешней
+// системе.
+func (r *ExternalAccountRepository) ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error) {
+	query := `
+        SELECT user_id
+        FROM external_accounts
+        WHERE provider = $1 AND external_id = $2
+    `
+	var userID string
+	err := r.db.QueryRowContext(ctx, query, provider, externalID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrAccountNotLinked
+		}
+		return "", fmt.Errorf("failed to resolve external account id: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type IdempotencyRepository struct {
+	db DBTX
+}
+
+func NewIdempotencyRepository(db DBTX) *IdempotencyRepository {
+	return &IdempotencyRepository{db: traced(db)}
+}
+
+// Reserve занимает ключ под новый запрос. Просроченная запись с тем же ключом
+// и незавершённая запись с истёкшей арендой перезаписываются. Возвращает
+// false, если ключ уже занят действующей записью.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *domain.IdempotencyRecord) (bool, error) {
+	query := `
+        INSERT INTO idempotency_keys (key, fingerprint, expires_at, locked_until, token)
+        VALUES ($1, $2, $3, $4, $5)
+        ON CONFLICT (key) DO UPDATE SET
+            fingerprint = EXCLUDED.fingerprint,
+            completed = FALSE,
+            status_code = 0,
+            content_type = '',
+            body = NULL,
+            created_at = CURRENT_TIMESTAMP,
+            expires_at = EXCLUDED.expires_at,
+            locked_until = EXCLUDED.locked_until,
+            token = EXCLUDED.token
+        WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+           OR (NOT idempotency_keys.completed AND idempotency_keys.locked_until <= CURRENT_TIMESTAMP)
+        RETURNING created_at
+    `
+	err := r.db.QueryRowContext(ctx, query, rec.Key, rec.Fingerprint, rec.ExpiresAt, rec.LockedUntil, rec.Token).Scan(&rec.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return true, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	query := `
+        SELECT key, fingerprint, completed, status_code, content_type, body, created_at, expires_at, locked_until
+        FROM idempotency_keys
+        WHERE key = $1
+    `
+	var rec domain.IdempotencyRecord
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&rec.Key,
+		&rec.Fingerprint,
+		&rec.Completed,
+		&rec.StatusCode,
+		&rec.ContentType,
+		&rec.Body,
+		&rec.CreatedAt,
+		&rec.ExpiresAt,
+		&rec.LockedUntil,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	return &rec, nil
+}
+
+// Complete сохраняет ответ, если ключ всё ещё занят этим резервированием;
+// иначе возвращает domain.ErrIdempotencyLeaseLost.
+func (r *IdempotencyRepository) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	query := `
+        UPDATE idempotency_keys
+        SET completed = TRUE,
+            status_code = $3,
+            content_type = $4,
+            body = $5
+        WHERE key = $1 AND token = $2 AND NOT completed
+    `
+	result, err := r.db.ExecContext(ctx, query, rec.Key, rec.Token, rec.StatusCode, rec.ContentType, rec.Body)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return leaseHeld(result)
+}
+
+// Delete освобождает ключ, если он всё ещё занят этим резервированием.
+func (r *IdempotencyRepository) Delete(ctx context.Context, key, token string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND token = $2 AND NOT completed`
+	result, err := r.db.ExecContext(ctx, query, key, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+	return leaseHeld(result)
+}
+
+func leaseHeld(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if rows == 0 {
+		return domain.ErrIdempotencyLeaseLost
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -47,7 +47,19 @@ func truncateTables(t *testing.T) {
 	t.Helper()
 	ctx := context.Background()
 	queries := []string{
+		"TRUNCATE TABLE api_token_audit CASCADE",
+		"TRUNCATE TABLE api_tokens CASCADE",
+		"TRUNCATE TABLE idempotency_keys CASCADE",
 		"TRUNCATE TABLE batch_deactivate_tasks CASCADE",
+		"TRUNCATE TABLE reviewer_fill_tasks CASCADE",
+		"TRUNCATE TABLE assignment_audit CASCADE",
+		"TRUNCATE TABLE assignment_decisions CASCADE",
+		"TRUNCATE TABLE external_accounts CASCADE",
+		"TRUNCATE TABLE notifications CASCADE",
+		"TRUNCATE TABLE notification_preferences CASCADE",
+		"TRUNCATE TABLE webhook_deliveries CASCADE",
+		"TRUNCATE TABLE webhook_subscriptions CASCADE",
+		"TRUNCATE TABLE outbox_events CASCADE",
 		"TRUNCATE TABLE pr_reviewers CASCADE",
 		"TRUNCATE TABLE pull_requests CASCADE",
 		"TRUNCATE TABLE users CASCADE",
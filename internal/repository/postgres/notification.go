@@ -0,0 +1,305 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+type NotificationRepository struct {
+	db DBTX
+}
+
+func NewNotificationRepository(db DBTX) *NotificationRepository {
+	return &NotificationRepository{db: traced(db)}
+}
+
+func (r *NotificationRepository) UpsertPreferences(ctx context.Context, p *domain.NotificationPreferences) error {
+	query := `
+        INSERT INTO notification_preferences
+            (user_id, channels, email, chat_webhook_url, mode, quiet_start, quiet_end, timezone, digest_at, daily_digest, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, CURRENT_TIMESTAMP)
+        ON CONFLICT (user_id) DO UPDATE SET
+            channels = EXCLUDED.channels,
+            email = EXCLUDED.email,
+            chat_webhook_url = EXCLUDED.chat_webhook_url,
+            mode = EXCLUDED.mode,
+            quiet_start = EXCLUDED.quiet_start,
+            quiet_end = EXCLUDED.quiet_end,
+            timezone = EXCLUDED.timezone,
+            digest_at = EXCLUDED.digest_at,
+            daily_digest = EXCLUDED.daily_digest,
+            updated_at = EXCLUDED.updated_at
+        RETURNING last_digest_at, updated_at
+    `
+	channels := p.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+	var quietStart, quietEnd sql.NullInt16
+	if p.QuietHours != nil {
+		quietStart = sql.NullInt16{Int16: int16(p.QuietHours.Start), Valid: true} //nolint:gosec
+		quietEnd = sql.NullInt16{Int16: int16(p.QuietHours.End), Valid: true}     //nolint:gosec
+	}
+	err := r.db.QueryRowContext(ctx, query,
+		p.UserID,
+		pq.Array(channels),
+		nullString(p.Email),
+		nullString(p.ChatWebhookURL),
+		p.Mode,
+		quietStart,
+		quietEnd,
+		p.Timezone,
+		p.DigestAt,
+		p.DailyDigest,
+	).Scan(&p.LastDigestAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	query := `
+        SELECT user_id, channels, email, chat_webhook_url, mode, quiet_start, quiet_end, timezone, digest_at,
+               daily_digest, last_digest_at, updated_at
+        FROM notification_preferences
+        WHERE user_id = $1
+    `
+	prefs, err := r.queryPreferences(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(prefs) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return prefs[0], nil
+}
+
+// GetPreferencesByUsers возвращает настройки указанных пользователей;
+// пользователи без настроек в результат не попадают.
+func (r *NotificationRepository) GetPreferencesByUsers(ctx context.Context, userIDs []string) (map[string]*domain.NotificationPreferences, error) {
+	query := `
+        SELECT user_id, channels, email, chat_webhook_url, mode, quiet_start, quiet_end, timezone, digest_at,
+               daily_digest, last_digest_at, updated_at
+        FROM notification_preferences
+        WHERE user_id = ANY($1)
+    `
+	prefs, err := r.queryPreferences(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*domain.NotificationPreferences, len(prefs))
+	for _, p := range prefs {
+		result[p.UserID] = p
+	}
+	return result, nil
+}
+
+// ListDigestSubscribers возвращает пользователей с включённым ежедневным
+// дайджестом и хотя бы одним каналом.
+func (r *NotificationRepository) ListDigestSubscribers(ctx context.Context) ([]*domain.NotificationPreferences, error) {
+	query := `
+        SELECT user_id, channels, email, chat_webhook_url, mode, quiet_start, quiet_end, timezone, digest_at,
+               daily_digest, last_digest_at, updated_at
+        FROM notification_preferences
+        WHERE daily_digest = TRUE AND cardinality(channels) > 0
+        ORDER BY user_id
+    `
+	return r.queryPreferences(ctx, query)
+}
+
+// ClaimDigest отмечает отправку дайджеста за слот slot. Возвращает false, если
+// дайджест за этот слот уже забрал другой экземпляр сервиса.
+func (r *NotificationRepository) ClaimDigest(ctx context.Context, userID string, slot time.Time) (bool, error) {
+	query := `
+        UPDATE notification_preferences
+        SET last_digest_at = CURRENT_TIMESTAMP
+        WHERE user_id = $1 AND last_digest_at < $2
+    `
+	result, err := r.db.ExecContext(ctx, query, userID, slot)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim digest: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+func (r *NotificationRepository) queryPreferences(ctx context.Context, query string, args ...interface{}) ([]*domain.NotificationPreferences, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var prefs []*domain.NotificationPreferences
+	for rows.Next() {
+		var (
+			p                    domain.NotificationPreferences
+			email, chatURL       sql.NullString
+			quietStart, quietEnd sql.NullInt16
+		)
+		if err := rows.Scan(
+			&p.UserID,
+			pq.Array(&p.Channels),
+			&email,
+			&chatURL,
+			&p.Mode,
+			&quietStart,
+			&quietEnd,
+			&p.Timezone,
+			&p.DigestAt,
+			&p.DailyDigest,
+			&p.LastDigestAt,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preferences: %w", err)
+		}
+		p.Email = email.String
+		p.ChatWebhookURL = chatURL.String
+		if quietStart.Valid && quietEnd.Valid {
+			p.QuietHours = &domain.QuietHours{Start: int(quietStart.Int16), End: int(quietEnd.Int16)}
+		}
+		prefs = append(prefs, &p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	query := `
+        INSERT INTO notifications (user_id, event_id, event_type, subject, body, status, deliver_after)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        ON CONFLICT (user_id, event_id) DO NOTHING
+    `
+	_, err := r.db.ExecContext(ctx, query,
+		n.UserID,
+		sql.NullInt64{Int64: n.EventID, Valid: n.EventID != 0},
+		n.EventType,
+		n.Subject,
+		n.Body,
+		domain.NotificationStatusPending,
+		n.DeliverAfter,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue берёт все готовые к отправке уведомления одного пользователя,
+// чтобы отправить их одним сообщением, и сдвигает deliver_after на lease
+// на случай падения процесса во время отправки.
+func (r *NotificationRepository) ClaimDue(ctx context.Context, lease time.Duration) ([]*domain.Notification, error) {
+	query := `
+        UPDATE notifications
+        SET attempts = attempts + 1,
+            deliver_after = CURRENT_TIMESTAMP + make_interval(secs => $1)
+        WHERE id IN (
+            SELECT id
+            FROM notifications
+            WHERE status = $2
+              AND deliver_after <= CURRENT_TIMESTAMP
+              AND user_id = (
+                  SELECT user_id
+                  FROM notifications
+                  WHERE status = $2 AND deliver_after <= CURRENT_TIMESTAMP
+                  ORDER BY deliver_after
+                  LIMIT 1
+              )
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, user_id, event_id, event_type, subject, body, status, deliver_after, attempts, sent_channels, created_at
+    `
+	rows, err := r.db.QueryContext(ctx, query, lease.Seconds(), domain.NotificationStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*domain.Notification
+	for rows.Next() {
+		var (
+			n       domain.Notification
+			eventID sql.NullInt64
+		)
+		if err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&eventID,
+			&n.EventType,
+			&n.Subject,
+			&n.Body,
+			&n.Status,
+			&n.DeliverAfter,
+			&n.Attempts,
+			pq.Array(&n.SentChannels),
+			&n.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		n.EventID = eventID.Int64
+		notifications = append(notifications, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+	if len(notifications) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return notifications, nil
+}
+
+// MarkChannelSent отмечает доставку уведомлений в канал сразу после отправки,
+// чтобы повторная попытка группы не дублировала сообщение в этом канале.
+func (r *NotificationRepository) MarkChannelSent(ctx context.Context, ids []int64, channel string) error {
+	query := `
+        UPDATE notifications
+        SET sent_channels = array_append(sent_channels, $2)
+        WHERE id = ANY($1) AND NOT ($2 = ANY(sent_channels))
+    `
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids), channel); err != nil {
+		return fmt.Errorf("failed to mark notification channel sent: %w", err)
+	}
+	return nil
+}
+
+// RecordResult сохраняет результат отправки группы уведомлений.
+func (r *NotificationRepository) RecordResult(
+	ctx context.Context,
+	ids []int64,
+	status string,
+	deliverAfter time.Time,
+	errorMessage sql.NullString,
+) error {
+	query := `
+        UPDATE notifications
+        SET status = $2,
+            deliver_after = $3,
+            error_message = $4,
+            sent_at = CASE WHEN $5 THEN CURRENT_TIMESTAMP ELSE sent_at END
+        WHERE id = ANY($1)
+    `
+	_, err := r.db.ExecContext(ctx, query,
+		pq.Array(ids),
+		status,
+		deliverAfter,
+		errorMessage,
+		status == domain.NotificationStatusSent,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record notification result: %w", err)
+	}
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
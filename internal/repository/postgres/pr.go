@@ -15,7 +15,7 @@ type PullRequestRepository struct {
 }
 
 func NewPullRequestRepository(db DBTX) *PullRequestRepository {
-	return &PullRequestRepository{db: db}
+	return &PullRequestRepository{db: traced(db)}
 }
 
 func (r *PullRequestRepository) Create(ctx context.Context, pr *domain.PullRequest) error {
@@ -23,13 +23,14 @@ func (r *PullRequestRepository) Create(ctx context.Context, pr *domain.PullReque
 	query := `
         INSERT INTO pull_requests (id, pull_request_name, author_id, status_id, created_at)
         VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        RETURNING version
     `
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		pr.PullRequestID,
 		pr.PullRequestName,
 		pr.AuthorID,
 		pr.StatusID,
-	)
+	).Scan(&pr.Version)
 	if err != nil {
 		if isUniqueViolation(err, "pull_requests_pkey") {
 			return domain.ErrPRExists
@@ -69,7 +70,7 @@ func (r *PullRequestRepository) addReviewersBatch(ctx context.Context, prID stri
 
 func (r *PullRequestRepository) Get(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	query := `
-        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at
+        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at, version
         FROM pull_requests
         WHERE id = $1
     `
@@ -82,6 +83,7 @@ func (r *PullRequestRepository) Get(ctx context.Context, prID string) (*domain.P
 		&pr.StatusID,
 		&pr.CreatedAt,
 		&mergedAt,
+		&pr.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -101,31 +103,70 @@ func (r *PullRequestRepository) Get(ctx context.Context, prID string) (*domain.P
 	return &pr, nil
 }
 
+// Update перезаписывает PR, только если его версия в БД совпадает с pr.Version,
+// иначе возвращает domain.ErrConcurrentModification. При успехе pr.Version
+// получает новую версию.
 func (r *PullRequestRepository) Update(ctx context.Context, pr *domain.PullRequest) error {
 	pr.PrepareForDB()
 	query := `
         UPDATE pull_requests
         SET pull_request_name = $1,
             status_id = $2,
-            merged_at = $3
-        WHERE id = $4
+            merged_at = $3,
+            version = version + 1
+        WHERE id = $4 AND version = $5
+        RETURNING version
     `
-	result, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		pr.PullRequestName,
 		pr.StatusID,
 		pr.MergedAt,
 		pr.PullRequestID,
-	)
+		pr.Version,
+	).Scan(&pr.Version)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.versionMismatch(ctx, pr.PullRequestID)
+		}
 		return fmt.Errorf("failed to update pull request: %w", err)
 	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
+	return nil
+}
+
+// LockVersion блокирует строку PR до конца транзакции и проверяет, что его
+// версия не изменилась с момента чтения. Вызывается перед изменениями,
+// рассчитанными по ранее прочитанному состоянию PR.
+func (r *PullRequestRepository) LockVersion(ctx context.Context, prID string, expected int) error {
+	query := `
+        SELECT version
+        FROM pull_requests
+        WHERE id = $1
+        FOR UPDATE
+    `
+	var version int
+	if err := r.db.QueryRowContext(ctx, query, prID).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrNotFound
+		}
+		return fmt.Errorf("failed to lock pull request version: %w", err)
+	}
+	if version != expected {
+		return domain.ErrConcurrentModification
 	}
 	return nil
 }
 
+func (r *PullRequestRepository) versionMismatch(ctx context.Context, prID string) error {
+	exists, err := r.Exists(ctx, prID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrConcurrentModification
+}
+
 func (r *PullRequestRepository) Exists(ctx context.Context, prID string) (bool, error) {
 	query := `
         SELECT EXISTS(
@@ -179,7 +220,7 @@ func (r *PullRequestRepository) SetReviewers(ctx context.Context, prID string, r
 			return fmt.Errorf("failed to set reviewers: %w", err)
 		}
 	}
-	return nil
+	return r.bumpVersion(ctx, prID)
 }
 
 func (r *PullRequestRepository) AddReviewer(ctx context.Context, prID, userID string) error {
@@ -188,11 +229,14 @@ func (r *PullRequestRepository) AddReviewer(ctx context.Context, prID, userID st
         VALUES ($1, $2)
         ON CONFLICT (pull_request_id, user_id) DO NOTHING
     `
-	_, err := r.db.ExecContext(ctx, query, prID, userID)
+	result, err := r.db.ExecContext(ctx, query, prID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to add reviewer: %w", err)
 	}
-	return nil
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return nil
+	}
+	return r.bumpVersion(ctx, prID)
 }
 
 func (r *PullRequestRepository) RemoveReviewer(ctx context.Context, prID, userID string) error {
@@ -208,7 +252,7 @@ func (r *PullRequestRepository) RemoveReviewer(ctx context.Context, prID, userID
 	if rowsAffected == 0 {
 		return domain.ErrNotAssigned
 	}
-	return nil
+	return r.bumpVersion(ctx, prID)
 }
 
 func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUserID, newUserID string) error {
@@ -232,16 +276,30 @@ func (r *PullRequestRepository) ReplaceReviewer(ctx context.Context, prID, oldUs
 	if err != nil {
 		return fmt.Errorf("failed to add new reviewer: %w", err)
 	}
+	return r.bumpVersion(ctx, prID)
+}
+
+// bumpVersion увеличивает версию PR после изменения набора ревьюеров.
+func (r *PullRequestRepository) bumpVersion(ctx context.Context, prID string) error {
+	query := `
+        UPDATE pull_requests
+        SET version = version + 1
+        WHERE id = $1
+    `
+	if _, err := r.db.ExecContext(ctx, query, prID); err != nil {
+		return fmt.Errorf("failed to bump pull request version: %w", err)
+	}
 	return nil
 }
 
 func (r *PullRequestRepository) GetByReviewer(ctx context.Context, userID string, openStatusID int16) ([]*domain.PullRequestShort, error) {
 	query := `
-        SELECT p.id, p.pull_request_name, p.author_id, p.status_id
+        SELECT p.id, p.pull_request_name, p.author_id, p.status_id, p.created_at
         FROM pull_requests p
         INNER JOIN pr_reviewers pr ON p.id = pr.pull_request_id
         WHERE pr.user_id = $1
         AND p.status_id = $2
+        ORDER BY p.created_at
     `
 	rows, err := r.db.QueryContext(ctx, query, userID, openStatusID)
 	if err != nil {
@@ -257,6 +315,7 @@ func (r *PullRequestRepository) GetByReviewer(ctx context.Context, userID string
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&statusID,
+			&pr.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan PR: %w", err)
 		}
@@ -271,7 +330,7 @@ func (r *PullRequestRepository) GetByReviewer(ctx context.Context, userID string
 
 func (r *PullRequestRepository) GetByAuthor(ctx context.Context, authorID string) ([]*domain.PullRequestShort, error) {
 	query := `
-        SELECT id, pull_request_name, author_id, status_id
+        SELECT id, pull_request_name, author_id, status_id, created_at
         FROM pull_requests
         WHERE author_id = $1
         ORDER BY created_at DESC
@@ -290,6 +349,7 @@ func (r *PullRequestRepository) GetByAuthor(ctx context.Context, authorID string
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&statusID,
+			&pr.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan PR: %w", err)
 		}
@@ -304,89 +364,55 @@ func (r *PullRequestRepository) GetByAuthor(ctx context.Context, authorID string
 
 func (r *PullRequestRepository) GetOpenPRs(ctx context.Context) ([]*domain.PullRequest, error) {
 	query := `
-        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at
+        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at, version
         FROM pull_requests
         WHERE status_id = $1
         ORDER BY created_at DESC
     `
-	rows, err := r.db.QueryContext(ctx, query, domain.PRStatusIDOpen)
+	prs, err := r.queryPRs(ctx, query, domain.PRStatusIDOpen)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open PRs: %w", err)
 	}
-	defer rows.Close()
-	var prs []*domain.PullRequest
-	for rows.Next() {
-		var pr domain.PullRequest
-		var mergedAt sql.NullTime
-		if err := rows.Scan(
-			&pr.PullRequestID,
-			&pr.PullRequestName,
-			&pr.AuthorID,
-			&pr.StatusID,
-			&pr.CreatedAt,
-			&mergedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan PR: %w", err)
-		}
-		if mergedAt.Valid {
-			pr.MergedAt = &mergedAt.Time
-		}
-		pr.SyncStatus()
-		prs = append(prs, &pr)
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating PRs: %w", err)
+	return prs, nil
+}
+
+func (r *PullRequestRepository) GetUnderstaffedByTeam(ctx context.Context, teamID int, maxReviewers int) ([]*domain.PullRequest, error) {
+	query := `
+        SELECT p.id, p.pull_request_name, p.author_id, p.status_id, p.created_at, p.merged_at, p.version
+        FROM pull_requests p
+        INNER JOIN users u ON u.id = p.author_id
+        LEFT JOIN pr_reviewers rv ON rv.pull_request_id = p.id
+        WHERE u.team_id = $1
+        AND p.status_id = $2
+        GROUP BY p.id
+        HAVING COUNT(rv.user_id) < $3
+        ORDER BY p.created_at
+    `
+	prs, err := r.queryPRs(ctx, query, teamID, domain.PRStatusIDOpen, maxReviewers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get understaffed PRs: %w", err)
 	}
-	for _, pr := range prs {
-		reviewers, err := r.GetReviewers(ctx, pr.PullRequestID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get reviewers for pr %s: %w", pr.PullRequestID, err)
-		}
-		pr.AssignedReviewers = reviewers
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
 	}
 	return prs, nil
 }
 
 func (r *PullRequestRepository) List(ctx context.Context) ([]*domain.PullRequest, error) {
 	query := `
-        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at
+        SELECT id, pull_request_name, author_id, status_id, created_at, merged_at, version
         FROM pull_requests
         ORDER BY created_at DESC
     `
-	rows, err := r.db.QueryContext(ctx, query)
+	prs, err := r.queryPRs(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list PRs: %w", err)
 	}
-	defer rows.Close()
-	var prs []*domain.PullRequest
-	for rows.Next() {
-		var pr domain.PullRequest
-		var mergedAt sql.NullTime
-		if err := rows.Scan(
-			&pr.PullRequestID,
-			&pr.PullRequestName,
-			&pr.AuthorID,
-			&pr.StatusID,
-			&pr.CreatedAt,
-			&mergedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan PR: %w", err)
-		}
-		if mergedAt.Valid {
-			pr.MergedAt = &mergedAt.Time
-		}
-		pr.SyncStatus()
-		prs = append(prs, &pr)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating PRs: %w", err)
-	}
-	for _, pr := range prs {
-		reviewers, err := r.GetReviewers(ctx, pr.PullRequestID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get reviewers for pr %s: %w", pr.PullRequestID, err)
-		}
-		pr.AssignedReviewers = reviewers
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
 	}
 	return prs, nil
 }
@@ -405,10 +431,11 @@ func (r *PullRequestRepository) Merge(ctx context.Context, prID string, mergedSt
         UPDATE pull_requests
         SET
             status_id = $2,
-            merged_at = COALESCE(merged_at, $3)
+            merged_at = COALESCE(merged_at, $3),
+            version = version + 1
         WHERE
             id = $1
-        RETURNING id, pull_request_name, author_id, status_id, created_at, merged_at
+        RETURNING id, pull_request_name, author_id, status_id, created_at, merged_at, version
     `
 
 	var pr domain.PullRequest
@@ -421,6 +448,7 @@ func (r *PullRequestRepository) Merge(ctx context.Context, prID string, mergedSt
 		&pr.StatusID,
 		&pr.CreatedAt,
 		&mergedAt,
+		&pr.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
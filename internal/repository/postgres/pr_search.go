@@ -0,0 +1,188 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+// Search возвращает страницу PR по фильтру с keyset-пагинацией: следующая
+// страница начинается строго после (поле сортировки, id) последнего PR.
+func (r *PullRequestRepository) Search(ctx context.Context, f *domain.PRFilter) (*domain.PRPage, error) {
+	var (
+		where []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Status != "" {
+		where = append(where, "p.status_id = "+arg(f.Status.ToStatusID()))
+	}
+	if f.AuthorID != "" {
+		where = append(where, "p.author_id = "+arg(f.AuthorID))
+	}
+	if f.ReviewerID != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM pr_reviewers rv WHERE rv.pull_request_id = p.id AND rv.user_id = "+arg(f.ReviewerID)+")")
+	}
+	if f.TeamName != "" {
+		where = append(where, "p.author_id IN (SELECT u.id FROM users u JOIN teams t ON t.id = u.team_id WHERE t.name = "+arg(f.TeamName)+")")
+	}
+	if f.CreatedFrom != nil {
+		where = append(where, "p.created_at >= "+arg(*f.CreatedFrom))
+	}
+	if f.CreatedTo != nil {
+		where = append(where, "p.created_at < "+arg(*f.CreatedTo))
+	}
+	if f.MergedFrom != nil {
+		where = append(where, "p.merged_at >= "+arg(*f.MergedFrom))
+	}
+	if f.MergedTo != nil {
+		where = append(where, "p.merged_at < "+arg(*f.MergedTo))
+	}
+	if f.Query != "" {
+		where = append(where, `p.pull_request_name ILIKE '%' || `+arg(escapeLike(f.Query))+`::text || '%' ESCAPE '\'`)
+	}
+
+	sortColumn := "p.created_at"
+	switch f.Sort {
+	case domain.PRSortName:
+		sortColumn = "p.pull_request_name"
+	case domain.PRSortMergedAt:
+		sortColumn = "p.merged_at"
+		where = append(where, "p.merged_at IS NOT NULL")
+	}
+
+	filterSQL := ""
+	if len(where) > 0 {
+		filterSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM pull_requests p ` + filterSQL
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count pull requests: %w", err)
+	}
+
+	direction, cmp := "DESC", "<"
+	if f.Order == domain.SortAsc {
+		direction, cmp = "ASC", ">"
+	}
+	if c := f.Cursor; c != nil {
+		var value interface{} = c.Time
+		if f.Sort == domain.PRSortName {
+			value = c.Name
+		}
+		where = append(where, fmt.Sprintf("(%s, p.id) %s (%s, %s)", sortColumn, cmp, arg(value), arg(c.ID)))
+		filterSQL = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	pageQuery := fmt.Sprintf(`
+        SELECT p.id, p.pull_request_name, p.author_id, p.status_id, p.created_at, p.merged_at, p.version
+        FROM pull_requests p
+        %s
+        ORDER BY %s %s, p.id %s
+        LIMIT %s
+    `, filterSQL, sortColumn, direction, direction, arg(f.Limit+1))
+
+	prs, err := r.queryPRs(ctx, pageQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.PRPage{Total: total}
+	if len(prs) > f.Limit {
+		prs = prs[:f.Limit]
+		page.NextCursor = domain.NewPRCursor(f, prs[len(prs)-1]).Encode()
+	}
+	if err := r.loadReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+	page.Items = prs
+	return page, nil
+}
+
+// queryPRs выполняет запрос, возвращающий id, pull_request_name, author_id,
+// status_id, created_at, merged_at, version; ревьюеры не загружаются.
+func (r *PullRequestRepository) queryPRs(ctx context.Context, query string, args ...interface{}) ([]*domain.PullRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PRs: %w", err)
+	}
+	defer rows.Close()
+	prs := []*domain.PullRequest{}
+	for rows.Next() {
+		var pr domain.PullRequest
+		var mergedAt sql.NullTime
+		if err := rows.Scan(
+			&pr.PullRequestID,
+			&pr.PullRequestName,
+			&pr.AuthorID,
+			&pr.StatusID,
+			&pr.CreatedAt,
+			&mergedAt,
+			&pr.Version,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		pr.SyncStatus()
+		prs = append(prs, &pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating PRs: %w", err)
+	}
+	return prs, nil
+}
+
+// loadReviewers заполняет AssignedReviewers всех PR одним запросом.
+func (r *PullRequestRepository) loadReviewers(ctx context.Context, prs []*domain.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+	byID := make(map[string]*domain.PullRequest, len(prs))
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.AssignedReviewers = []string{}
+		byID[pr.PullRequestID] = pr
+		ids = append(ids, pr.PullRequestID)
+	}
+
+	query := `
+        SELECT pull_request_id, user_id
+        FROM pr_reviewers
+        WHERE pull_request_id = ANY($1)
+        ORDER BY pull_request_id, user_id
+    `
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get reviewers: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var prID, reviewerID string
+		if err := rows.Scan(&prID, &reviewerID); err != nil {
+			return fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		if pr, ok := byID[prID]; ok {
+			pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating reviewers: %w", err)
+	}
+	return nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,99 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/repository/postgres"
+)
+
+func TestPullRequestRepository_SearchPagination(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+	truncateTables(t)
+
+	ctx := context.Background()
+	team := &domain.Team{Name: "search-team"}
+	if err := postgres.NewTeamRepository(testDB.DB).Create(ctx, team); err != nil {
+		t.Fatalf("Create team error = %v", err)
+	}
+	userRepo := postgres.NewUserRepository(testDB.DB)
+	for _, id := range []string{"author", "reviewer"} {
+		if err := userRepo.CreateOrUpdate(ctx, &domain.User{UserID: id, Username: id, TeamID: team.ID, IsActive: true}); err != nil {
+			t.Fatalf("CreateOrUpdate(%s) error = %v", id, err)
+		}
+	}
+
+	prRepo := postgres.NewPullRequestRepository(testDB.DB)
+	names := []string{"Add search", "Fix 100% CPU", "Add export", "Refactor", "Add import"}
+	for i, name := range names {
+		pr := &domain.PullRequest{
+			PullRequestID:   "pr-" + string(rune('a'+i)),
+			PullRequestName: name,
+			AuthorID:        "author",
+			Status:          domain.PRStatusOpen,
+		}
+		if i%2 == 0 {
+			pr.AssignedReviewers = []string{"reviewer"}
+		}
+		if err := prRepo.Create(ctx, pr); err != nil {
+			t.Fatalf("Create(%s) error = %v", pr.PullRequestID, err)
+		}
+	}
+
+	filter := &domain.PRFilter{Query: "add", Sort: domain.PRSortName, Order: domain.SortAsc, Limit: 2}
+	var got []string
+	for page := 0; page < 5; page++ {
+		result, err := prRepo.Search(ctx, filter)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if result.Total != 3 {
+			t.Errorf("Total = %d, want 3", result.Total)
+		}
+		for _, pr := range result.Items {
+			got = append(got, pr.PullRequestName)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		filter.Cursor, err = domain.DecodePRCursor(result.NextCursor)
+		if err != nil {
+			t.Fatalf("DecodePRCursor() error = %v", err)
+		}
+	}
+	want := []string{"Add export", "Add import", "Add search"}
+	if len(got) != len(want) {
+		t.Fatalf("names = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("names = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Символы LIKE в запросе ищутся буквально.
+	result, err := prRepo.Search(ctx, &domain.PRFilter{Query: "100%", Sort: domain.PRSortCreatedAt, Order: domain.SortDesc, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 1 || result.Items[0].PullRequestName != "Fix 100% CPU" {
+		t.Errorf("literal search = %+v", result.Items)
+	}
+
+	result, err = prRepo.Search(ctx, &domain.PRFilter{ReviewerID: "reviewer", TeamName: "search-team", Sort: domain.PRSortCreatedAt, Order: domain.SortDesc, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if result.Total != 3 {
+		t.Errorf("reviewer filter Total = %d, want 3", result.Total)
+	}
+	for _, pr := range result.Items {
+		if len(pr.AssignedReviewers) != 1 || pr.AssignedReviewers[0] != "reviewer" {
+			t.Errorf("%s reviewers = %v", pr.PullRequestID, pr.AssignedReviewers)
+		}
+	}
+}
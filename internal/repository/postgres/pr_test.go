@@ -0,0 +1,67 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/repository/postgres"
+)
+
+func TestPullRequestRepository_OptimisticConcurrency(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+	truncateTables(t)
+
+	ctx := context.Background()
+	team := &domain.Team{Name: "version-team"}
+	if err := postgres.NewTeamRepository(testDB.DB).Create(ctx, team); err != nil {
+		t.Fatalf("Create team error = %v", err)
+	}
+	userRepo := postgres.NewUserRepository(testDB.DB)
+	for _, id := range []string{"author", "r1", "r2"} {
+		if err := userRepo.CreateOrUpdate(ctx, &domain.User{UserID: id, Username: id, TeamID: team.ID, IsActive: true}); err != nil {
+			t.Fatalf("CreateOrUpdate(%s) error = %v", id, err)
+		}
+	}
+
+	prRepo := postgres.NewPullRequestRepository(testDB.DB)
+	pr := &domain.PullRequest{
+		PullRequestID:     "pr-v",
+		PullRequestName:   "Versioned",
+		AuthorID:          "author",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []string{"r1"},
+	}
+	if err := prRepo.Create(ctx, pr); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	first, _ := prRepo.Get(ctx, pr.PullRequestID)
+	second, _ := prRepo.Get(ctx, pr.PullRequestID)
+
+	first.PullRequestName = "First writer"
+	if err := prRepo.Update(ctx, first); err != nil {
+		t.Fatalf("Update(first) error = %v", err)
+	}
+	second.PullRequestName = "Second writer"
+	if err := prRepo.Update(ctx, second); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("Update(second) error = %v, want ErrConcurrentModification", err)
+	}
+
+	if err := prRepo.ReplaceReviewer(ctx, pr.PullRequestID, "r1", "r2"); err != nil {
+		t.Fatalf("ReplaceReviewer() error = %v", err)
+	}
+	if err := prRepo.LockVersion(ctx, pr.PullRequestID, first.Version); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("LockVersion() after reviewer change error = %v, want ErrConcurrentModification", err)
+	}
+	current, _ := prRepo.Get(ctx, pr.PullRequestID)
+	if err := prRepo.LockVersion(ctx, pr.PullRequestID, current.Version); err != nil {
+		t.Errorf("LockVersion(current) error = %v", err)
+	}
+	if err := prRepo.LockVersion(ctx, "missing", 1); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("LockVersion(missing) error = %v, want ErrNotFound", err)
+	}
+}
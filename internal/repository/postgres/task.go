@@ -14,7 +14,7 @@ type TaskRepository struct {
 }
 
 func NewTaskRepository(db DBTX) *TaskRepository {
-	return &TaskRepository{db: db}
+	return &TaskRepository{db: traced(db)}
 }
 
 func (r *TaskRepository) CreateDeactivateTask(ctx context.Context, teamID int) error {
@@ -79,3 +79,85 @@ func (r *TaskRepository) SetTaskStatus(ctx context.Context, taskID int, status s
 	}
 	return nil
 }
+
+func (r *TaskRepository) CreateFillTask(ctx context.Context, teamID int, trigger string) error {
+	query := `
+        INSERT INTO reviewer_fill_tasks (team_id, trigger, status)
+        VALUES ($1, $2, $3)
+    `
+	_, err := r.db.ExecContext(ctx, query, teamID, trigger, domain.TaskStatusPending)
+	if err != nil {
+		return fmt.Errorf("failed to create fill task: %w", err)
+	}
+	return nil
+}
+
+func (r *TaskRepository) GetAndLockPendingFillTask(ctx context.Context) (*domain.ReviewerFillTask, error) {
+	query := `
+        UPDATE reviewer_fill_tasks
+        SET status = $1, processed_at = CURRENT_TIMESTAMP
+        WHERE id = (
+            SELECT id
+            FROM reviewer_fill_tasks
+            WHERE status = $2
+            ORDER BY created_at
+            FOR UPDATE SKIP LOCKED
+            LIMIT 1
+        )
+        RETURNING id, team_id, trigger, status, filled_count, error_message, created_at, processed_at
+    `
+
+	var task domain.ReviewerFillTask
+	err := r.db.QueryRowContext(ctx, query, domain.TaskStatusProcessing, domain.TaskStatusPending).Scan(
+		&task.ID,
+		&task.TeamID,
+		&task.Trigger,
+		&task.Status,
+		&task.FilledCount,
+		&task.ErrorMessage,
+		&task.CreatedAt,
+		&task.ProcessedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get and lock fill task: %w", err)
+	}
+	return &task, nil
+}
+
+func (r *TaskRepository) SetFillTaskStatus(ctx context.Context, taskID int, status string, filledCount int, errorMessage string) error {
+	var errMsg sql.NullString
+	if errorMessage != "" {
+		errMsg = sql.NullString{String: errorMessage, Valid: true}
+	}
+
+	query := `
+        UPDATE reviewer_fill_tasks
+        SET status = $1, filled_count = $2, error_message = $3
+        WHERE id = $4
+    `
+	_, err := r.db.ExecContext(ctx, query, status, filledCount, errMsg, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to set fill task status: %w", err)
+	}
+	return nil
+}
+
+// CountPending возвращает число ожидающих задач по видам.
+func (r *TaskRepository) CountPending(ctx context.Context) (map[string]int, error) {
+	query := `
+        SELECT
+            (SELECT COUNT(*) FROM batch_deactivate_tasks WHERE status = $1),
+            (SELECT COUNT(*) FROM reviewer_fill_tasks WHERE status = $1)
+    `
+	var deactivate, fill int
+	if err := r.db.QueryRowContext(ctx, query, domain.TaskStatusPending).Scan(&deactivate, &fill); err != nil {
+		return nil, fmt.Errorf("failed to count pending tasks: %w", err)
+	}
+	return map[string]int{
+		domain.TaskKindDeactivate: deactivate,
+		domain.TaskKindFill:       fill,
+	}, nil
+}
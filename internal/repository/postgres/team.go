@@ -16,16 +16,16 @@ type TeamRepository struct {
 }
 
 func NewTeamRepository(db DBTX) *TeamRepository {
-	return &TeamRepository{db: db}
+	return &TeamRepository{db: traced(db)}
 }
 
 func (r *TeamRepository) Create(ctx context.Context, team *domain.Team) error {
 	query := `
-        INSERT INTO teams (name)
-        VALUES ($1)
-        RETURNING id
+        INSERT INTO teams (name, reviewer_rule)
+        VALUES ($1, $2)
+        RETURNING id, version
     `
-	err := r.db.QueryRowContext(ctx, query, team.Name).Scan(&team.ID)
+	err := r.db.QueryRowContext(ctx, query, team.Name, team.ReviewerRule.OrDefault()).Scan(&team.ID, &team.Version)
 	if err != nil {
 		if isUniqueViolation(err, "teams_name_key") {
 			return domain.ErrTeamExists
@@ -40,9 +40,13 @@ func (r *TeamRepository) Get(ctx context.Context, teamName string) (*domain.Team
         SELECT
             t.id,
             t.name,
+            t.reviewer_rule,
+            t.version,
             u.id,
             u.username,
-            u.is_active
+            u.is_active,
+            u.seniority,
+            u.role
         FROM teams t
         LEFT JOIN users u ON t.id = u.team_id
         WHERE t.name = $1
@@ -60,6 +64,8 @@ func (r *TeamRepository) Get(ctx context.Context, teamName string) (*domain.Team
 		var userID sql.NullString
 		var userName sql.NullString
 		var userIsActive sql.NullBool
+		var userSeniority sql.NullString
+		var userRole sql.NullString
 
 		if team == nil {
 			team = &domain.Team{}
@@ -68,18 +74,24 @@ func (r *TeamRepository) Get(ctx context.Context, teamName string) (*domain.Team
 		if err := rows.Scan(
 			&team.ID,
 			&team.Name,
+			&team.ReviewerRule,
+			&team.Version,
 			&userID,
 			&userName,
 			&userIsActive,
+			&userSeniority,
+			&userRole,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan team or user: %w", err)
 		}
 
 		if userID.Valid {
 			members = append(members, &domain.TeamMember{
-				UserID:   userID.String,
-				Username: userName.String,
-				IsActive: userIsActive.Bool,
+				UserID:    userID.String,
+				Username:  userName.String,
+				IsActive:  userIsActive.Bool,
+				Seniority: domain.Seniority(userSeniority.String),
+				Role:      domain.Role(userRole.String),
 			})
 		}
 	}
@@ -99,13 +111,15 @@ func (r *TeamRepository) Get(ctx context.Context, teamName string) (*domain.Team
 func (r *TeamRepository) GetByID(ctx context.Context, teamID int) (*domain.Team, error) {
 	var team domain.Team
 	query := `
-        SELECT id, name
+        SELECT id, name, reviewer_rule, version
         FROM teams
         WHERE id = $1
     `
 	err := r.db.QueryRowContext(ctx, query, teamID).Scan(
 		&team.ID,
 		&team.Name,
+		&team.ReviewerRule,
+		&team.Version,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -146,7 +160,7 @@ func (r *TeamRepository) ExistsByID(ctx context.Context, teamID int) (bool, erro
 
 func (r *TeamRepository) List(ctx context.Context) ([]*domain.Team, error) {
 	query := `
-        SELECT id, name
+        SELECT id, name, reviewer_rule, version
         FROM teams
         ORDER BY name
     `
@@ -159,7 +173,7 @@ func (r *TeamRepository) List(ctx context.Context) ([]*domain.Team, error) {
 	var teams []*domain.Team
 	for rows.Next() {
 		var team domain.Team
-		if err := rows.Scan(&team.ID, &team.Name); err != nil {
+		if err := rows.Scan(&team.ID, &team.Name, &team.ReviewerRule, &team.Version); err != nil {
 			return nil, fmt.Errorf("failed to scan team: %w", err)
 		}
 		teams = append(teams, &team)
@@ -170,6 +184,28 @@ func (r *TeamRepository) List(ctx context.Context) ([]*domain.Team, error) {
 	return teams, nil
 }
 
+// LockVersion блокирует строку команды до конца транзакции и проверяет,
+// что её версия не изменилась с момента чтения.
+func (r *TeamRepository) LockVersion(ctx context.Context, teamID, expected int) error {
+	query := `
+        SELECT version
+        FROM teams
+        WHERE id = $1
+        FOR UPDATE
+    `
+	var version int
+	if err := r.db.QueryRowContext(ctx, query, teamID).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrTeamNotFound
+		}
+		return fmt.Errorf("failed to lock team version: %w", err)
+	}
+	if version != expected {
+		return domain.ErrConcurrentModification
+	}
+	return nil
+}
+
 func (r *TeamRepository) Count(ctx context.Context) (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM teams`
@@ -2,6 +2,7 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"avito/internal/domain"
@@ -71,3 +72,37 @@ func TestTeamRepository_Duplicate(t *testing.T) {
 		t.Errorf("Expected ErrTeamExists, got %v", err)
 	}
 }
+
+func TestTeamRepository_VersionBumpsOnMemberChange(t *testing.T) {
+	if testDB == nil {
+		t.Skip("Database not available")
+	}
+	truncateTables(t)
+
+	ctx := context.Background()
+	repo := postgres.NewTeamRepository(testDB.DB)
+	team := &domain.Team{Name: "Versioned Team"}
+	if err := repo.Create(ctx, team); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	userRepo := postgres.NewUserRepository(testDB.DB)
+	if err := userRepo.CreateOrUpdate(ctx, &domain.User{UserID: "u1", Username: "u1", TeamID: team.ID, IsActive: true}); err != nil {
+		t.Fatalf("CreateOrUpdate() error = %v", err)
+	}
+	before, err := repo.GetByID(ctx, team.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+
+	if err := userRepo.SetActive(ctx, "u1", false); err != nil {
+		t.Fatalf("SetActive() error = %v", err)
+	}
+	if err := repo.LockVersion(ctx, team.ID, before.Version); !errors.Is(err, domain.ErrConcurrentModification) {
+		t.Errorf("LockVersion() error = %v, want ErrConcurrentModification", err)
+	}
+	after, _ := repo.GetByID(ctx, team.ID)
+	if after.Version != before.Version+1 {
+		t.Errorf("Version = %d, want %d", after.Version, before.Version+1)
+	}
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("avito/internal/repository/postgres")
+
+// tracedDB оборачивает DBTX и открывает span на каждый запрос, выполненный в
+// рамках существующего трейса: опросы фоновых воркеров без родительского span
+// не создают отдельных трейсов. Span покрывает выполнение запроса до первого
+// ответа сервера; чтение строк из *sql.Rows в него не входит.
+type tracedDB struct {
+	db DBTX
+}
+
+// traced оборачивает DBTX трассировкой. Репозитории вызывают его в
+// конструкторах, поэтому запросы внутри транзакций тоже попадают в трейс.
+func traced(db DBTX) DBTX {
+	if t, ok := db.(*tracedDB); ok {
+		return t
+	}
+	return &tracedDB{db: db}
+}
+
+func (t *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !hasParentSpan(ctx) {
+		return t.db.ExecContext(ctx, query, args...)
+	}
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	res, err := t.db.ExecContext(ctx, query, args...)
+	recordQueryError(span, err)
+	return res, err
+}
+
+func (t *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if !hasParentSpan(ctx) {
+		return t.db.QueryContext(ctx, query, args...)
+	}
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	rows, err := t.db.QueryContext(ctx, query, args...)
+	recordQueryError(span, err)
+	return rows, err
+}
+
+func (t *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if !hasParentSpan(ctx) {
+		return t.db.QueryRowContext(ctx, query, args...)
+	}
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	row := t.db.QueryRowContext(ctx, query, args...)
+	// sql.ErrNoRows появляется только при Scan и ошибкой запроса не считается.
+	recordQueryError(span, row.Err())
+	return row
+}
+
+func hasParentSpan(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	query = strings.TrimSpace(query)
+	operation := queryOperation(query)
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+}
+
+func recordQueryError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// queryOperation возвращает первое слово запроса: SELECT, INSERT, WITH и т.д.
+func queryOperation(query string) string {
+	if i := strings.IndexFunc(query, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' }); i > 0 {
+		return strings.ToUpper(query[:i])
+	}
+	return strings.ToUpper(query)
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/lib/pq"
+
 	"avito/internal/domain"
 )
 
@@ -14,36 +16,62 @@ type UserRepository struct {
 }
 
 func NewUserRepository(db DBTX) *UserRepository {
-	return &UserRepository{db: db}
+	return &UserRepository{db: traced(db)}
 }
 
 func (r *UserRepository) CreateOrUpdate(ctx context.Context, user *domain.User) error {
+	var previousTeamID sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `SELECT team_id FROM users WHERE id = $1`, user.UserID).Scan(&previousTeamID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get current team of user: %w", err)
+	}
+
 	query := `
-        INSERT INTO users (id, username, team_id, is_active)
-        VALUES ($1, $2, $3, $4)
+        INSERT INTO users (id, username, team_id, is_active, seniority)
+        VALUES ($1, $2, $3, $4, $5)
         ON CONFLICT (id)
         DO UPDATE SET
             username = EXCLUDED.username,
             team_id = EXCLUDED.team_id,
-            is_active = EXCLUDED.is_active
+            is_active = EXCLUDED.is_active,
+            seniority = EXCLUDED.seniority
     `
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		user.UserID,
 		user.Username,
 		user.TeamID,
 		user.IsActive,
+		user.Seniority.OrDefault(),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create or update user: %w", err)
 	}
 
+	teamIDs := []int64{int64(user.TeamID)}
+	if previousTeamID.Valid && previousTeamID.Int64 != int64(user.TeamID) {
+		teamIDs = append(teamIDs, previousTeamID.Int64)
+	}
+	return r.bumpTeamVersions(ctx, teamIDs)
+}
+
+// bumpTeamVersions увеличивает версии команд, у которых изменился состав
+// или статусы участников.
+func (r *UserRepository) bumpTeamVersions(ctx context.Context, teamIDs []int64) error {
+	query := `
+        UPDATE teams
+        SET version = version + 1
+        WHERE id = ANY($1)
+    `
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(teamIDs)); err != nil {
+		return fmt.Errorf("failed to bump team versions: %w", err)
+	}
 	return nil
 }
 
 func (r *UserRepository) Get(ctx context.Context, userID string) (*domain.User, error) {
 	query := `
-        SELECT id, username, team_id, is_active
+        SELECT id, username, team_id, is_active, seniority, role
         FROM users
         WHERE id = $1
     `
@@ -53,6 +81,8 @@ func (r *UserRepository) Get(ctx context.Context, userID string) (*domain.User,
 		&user.Username,
 		&user.TeamID,
 		&user.IsActive,
+		&user.Seniority,
+		&user.Role,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -66,7 +96,7 @@ func (r *UserRepository) Get(ctx context.Context, userID string) (*domain.User,
 
 func (r *UserRepository) GetByTeamID(ctx context.Context, teamID int) ([]*domain.User, error) {
 	query := `
-		SELECT id, username, team_id, is_active
+		SELECT id, username, team_id, is_active, seniority, role
 		FROM users
 		WHERE team_id = $1
 		ORDER BY username
@@ -86,6 +116,8 @@ func (r *UserRepository) GetByTeamID(ctx context.Context, teamID int) ([]*domain
 			&user.Username,
 			&user.TeamID,
 			&user.IsActive,
+			&user.Seniority,
+			&user.Role,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -101,7 +133,7 @@ func (r *UserRepository) GetByTeamID(ctx context.Context, teamID int) ([]*domain
 
 func (r *UserRepository) GetActiveByTeamID(ctx context.Context, teamID int) ([]*domain.User, error) {
 	query := `
-		SELECT id, username, team_id, is_active
+		SELECT id, username, team_id, is_active, seniority, role
 		FROM users
 		WHERE team_id = $1 AND is_active = true
 		ORDER BY username
@@ -121,6 +153,8 @@ func (r *UserRepository) GetActiveByTeamID(ctx context.Context, teamID int) ([]*
 			&user.Username,
 			&user.TeamID,
 			&user.IsActive,
+			&user.Seniority,
+			&user.Role,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -140,7 +174,7 @@ func (r *UserRepository) GetActiveCandidatesForReview(ctx context.Context, teamI
 	}
 
 	query := `
-		SELECT id, username, team_id, is_active
+		SELECT id, username, team_id, is_active, seniority, role
 		FROM users
 		WHERE team_id = $1
 		  AND is_active = true
@@ -162,6 +196,8 @@ func (r *UserRepository) GetActiveCandidatesForReview(ctx context.Context, teamI
 			&user.Username,
 			&user.TeamID,
 			&user.IsActive,
+			&user.Seniority,
+			&user.Role,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -180,23 +216,39 @@ func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive
 		UPDATE users
 		SET is_active = $2
 		WHERE id = $1
+		RETURNING team_id
 	`
 
-	result, err := r.db.ExecContext(ctx, query, userID, isActive)
-	if err != nil {
+	var teamID int64
+	if err := r.db.QueryRowContext(ctx, query, userID, isActive).Scan(&teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
 		return fmt.Errorf("failed to set user active status: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+	return r.bumpTeamVersions(ctx, []int64{teamID})
+}
+
+// SetRole меняет роль пользователя. Это единственный путь изменения роли:
+// CreateOrUpdate её не трогает, новые пользователи получают member.
+func (r *UserRepository) SetRole(ctx context.Context, userID string, role domain.Role) error {
+	query := `
+		UPDATE users
+		SET role = $2
+		WHERE id = $1
+		RETURNING team_id
+	`
 
-	if rowsAffected == 0 {
-		return domain.ErrUserNotFound
+	var teamID int64
+	if err := r.db.QueryRowContext(ctx, query, userID, role).Scan(&teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to set user role: %w", err)
 	}
 
-	return nil
+	return r.bumpTeamVersions(ctx, []int64{teamID})
 }
 
 func (r *UserRepository) Exists(ctx context.Context, userID string) (bool, error) {
@@ -219,28 +271,23 @@ func (r *UserRepository) Delete(ctx context.Context, userID string) error {
 	query := `
 		DELETE FROM users
 		WHERE id = $1
+		RETURNING team_id
 	`
 
-	result, err := r.db.ExecContext(ctx, query, userID)
-	if err != nil {
+	var teamID int64
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&teamID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrUserNotFound
+		}
 		return fmt.Errorf("failed to delete user: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return domain.ErrUserNotFound
-	}
-
-	return nil
+	return r.bumpTeamVersions(ctx, []int64{teamID})
 }
 
 func (r *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
 	query := `
-		SELECT id, username, team_id, is_active
+		SELECT id, username, team_id, is_active, seniority, role
 		FROM users
 		ORDER BY username
 	`
@@ -259,6 +306,8 @@ func (r *UserRepository) List(ctx context.Context) ([]*domain.User, error) {
 			&user.Username,
 			&user.TeamID,
 			&user.IsActive,
+			&user.Seniority,
+			&user.Role,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -0,0 +1,219 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+type WebhookRepository struct {
+	db DBTX
+}
+
+func NewWebhookRepository(db DBTX) *WebhookRepository {
+	return &WebhookRepository{db: traced(db)}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	query := `
+        INSERT INTO webhook_subscriptions (url, secret, events, is_active)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, created_at
+    `
+	events := s.Events
+	if events == nil {
+		events = []string{}
+	}
+	err := r.db.QueryRowContext(ctx, query, s.URL, s.Secret, pq.Array(events), s.IsActive).Scan(&s.ID, &s.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+        SELECT id, url, secret, events, is_active, created_at
+        FROM webhook_subscriptions
+        ORDER BY id
+    `
+	return r.querySubscriptions(ctx, query)
+}
+
+func (r *WebhookRepository) GetActiveSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	query := `
+        SELECT id, url, secret, events, is_active, created_at
+        FROM webhook_subscriptions
+        WHERE is_active = TRUE
+        ORDER BY id
+    `
+	return r.querySubscriptions(ctx, query)
+}
+
+func (r *WebhookRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []*domain.WebhookSubscription
+	for rows.Next() {
+		var s domain.WebhookSubscription
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, pq.Array(&s.Events), &s.IsActive, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		subs = append(subs, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (r *WebhookRepository) SubscriptionExists(ctx context.Context, subscriptionID int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM webhook_subscriptions WHERE id = $1)`
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, subscriptionID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check webhook subscription existence: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, subscriptionID int, eventID int64) error {
+	query := `
+        INSERT INTO webhook_deliveries (subscription_id, event_id, status)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `
+	if _, err := r.db.ExecContext(ctx, query, subscriptionID, eventID, domain.DeliveryStatusPending); err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDelivery берёт одну доставку, время которой пришло, и сдвигает её
+// next_attempt_at на lease: если процесс упадёт во время отправки, доставка
+// будет повторена после истечения lease.
+func (r *WebhookRepository) ClaimDueDelivery(ctx context.Context, lease time.Duration) (*domain.WebhookDelivery, error) {
+	query := `
+        UPDATE webhook_deliveries d
+        SET attempts = d.attempts + 1,
+            next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $1)
+        FROM webhook_subscriptions s, outbox_events e
+        WHERE d.id = (
+            SELECT id
+            FROM webhook_deliveries
+            WHERE status = $2 AND next_attempt_at <= CURRENT_TIMESTAMP
+            ORDER BY next_attempt_at
+            FOR UPDATE SKIP LOCKED
+            LIMIT 1
+        )
+        AND s.id = d.subscription_id
+        AND e.id = d.event_id
+        RETURNING d.id, d.subscription_id, d.event_id, d.status, d.attempts, d.next_attempt_at, d.created_at,
+                  s.url, s.secret, e.event_type, e.aggregate_id, e.payload, e.created_at
+    `
+	d := domain.WebhookDelivery{
+		Subscription: &domain.WebhookSubscription{},
+		Event:        &domain.Event{},
+	}
+	err := r.db.QueryRowContext(ctx, query, lease.Seconds(), domain.DeliveryStatusPending).Scan(
+		&d.ID,
+		&d.SubscriptionID,
+		&d.EventID,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.CreatedAt,
+		&d.Subscription.URL,
+		&d.Subscription.Secret,
+		&d.Event.Type,
+		&d.Event.AggregateID,
+		&d.Event.Payload,
+		&d.Event.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	d.Subscription.ID = d.SubscriptionID
+	d.Event.ID = d.EventID
+	d.EventType = d.Event.Type
+	return &d, nil
+}
+
+// RecordAttempt сохраняет результат попытки доставки.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *domain.WebhookDelivery) error {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = $2,
+            next_attempt_at = $3,
+            response_code = $4,
+            error_message = $5,
+            delivered_at = CASE WHEN $6 THEN CURRENT_TIMESTAMP ELSE delivered_at END
+        WHERE id = $1
+    `
+	_, err := r.db.ExecContext(ctx, query,
+		d.ID,
+		d.Status,
+		d.NextAttemptAt,
+		d.ResponseCode,
+		d.ErrorMessage,
+		d.Status == domain.DeliveryStatusDelivered,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*domain.WebhookDelivery, error) {
+	query := `
+        SELECT d.id, d.subscription_id, d.event_id, e.event_type, d.status, d.attempts, d.next_attempt_at,
+               d.response_code, d.error_message, d.created_at, d.delivered_at
+        FROM webhook_deliveries d
+        JOIN outbox_events e ON e.id = d.event_id
+        WHERE d.subscription_id = $1
+        ORDER BY d.id DESC
+        LIMIT $2
+    `
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.EventType,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.ResponseCode,
+			&d.ErrorMessage,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
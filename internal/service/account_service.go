@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type accountRepoForAccountService interface {
+	Link(ctx context.Context, account *domain.ExternalAccount) error
+	Unlink(ctx context.Context, provider, login string) error
+	ResolveUserID(ctx context.Context, provider, login string) (string, error)
+	ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error)
+}
+
+type userRepoForAccountService interface {
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+type accountService struct {
+	accountRepo accountRepoForAccountService
+	userRepo    userRepoForAccountService
+}
+
+func NewAccountService(
+	accountRepo accountRepoForAccountService,
+	userRepo userRepoForAccountService,
+) *accountService {
+	return &accountService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *accountService) LinkAccount(ctx context.Context, account *domain.ExternalAccount) (*domain.ExternalAccount, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.LinkAccount")
+	defer span.End()
+
+	if err := account.Validate(); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.userRepo.Exists(ctx, account.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if err := s.accountRepo.Link(ctx, account); err != nil {
+		return nil, fmt.Errorf("failed to link account: %w", err)
+	}
+	return account, nil
+}
+
+func (s *accountService) UnlinkAccount(ctx context.Context, provider, login string) error {
+	ctx, span := tracer.Start(ctx, "AccountService.UnlinkAccount")
+	defer span.End()
+
+	if !domain.IsValidProvider(provider) || login == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.accountRepo.Unlink(ctx, provider, login)
+}
+
+func (s *accountService) ResolveUserID(ctx context.Context, provider, login string) (string, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.ResolveUserID")
+	defer span.End()
+
+	return s.accountRepo.ResolveUserID(ctx, provider, login)
+}
+
+func (s *accountService) ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.ResolveUserIDByExternalID")
+	defer span.End()
+
+	return s.accountRepo.ResolveUserIDByExternalID(ctx, provider, externalID)
+}
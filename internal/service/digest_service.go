@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+)
+
+type prRepoForDigestService interface {
+	GetByReviewer(ctx context.Context, userID string, openStatusID int16) ([]*domain.PullRequestShort, error)
+}
+
+type userRepoForDigestService interface {
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+type digestService struct {
+	prRepo   prRepoForDigestService
+	userRepo userRepoForDigestService
+	sla      domain.ReviewSLA
+	now      func() time.Time
+}
+
+func NewDigestService(
+	prRepo prRepoForDigestService,
+	userRepo userRepoForDigestService,
+	sla domain.ReviewSLA,
+) *digestService {
+	return &digestService{
+		prRepo:   prRepo,
+		userRepo: userRepo,
+		sla:      sla,
+		now:      time.Now,
+	}
+}
+
+// BuildDigest собирает открытые ревью пользователя с возрастом и статусом SLA.
+func (s *digestService) BuildDigest(ctx context.Context, userID string) (*domain.Digest, error) {
+	ctx, span := tracer.Start(ctx, "DigestService.BuildDigest")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrUserNotFound
+	}
+
+	prs, err := s.prRepo.GetByReviewer(ctx, userID, domain.PRStatusIDOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open reviews: %w", err)
+	}
+	return domain.NewDigest(userID, prs, s.sla, s.now()), nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/notifier"
+	"avito/internal/repository"
+	"avito/internal/repository/postgres"
+	"avito/pkg/logger"
+)
+
+// DigestWorker раз в PollInterval проверяет, у кого из пользователей
+// наступило время ежедневного дайджеста (digest_at в их часовом поясе), и
+// ставит дайджест в очередь уведомлений; отправляет его NotificationWorker.
+type DigestWorker struct {
+	db               *postgres.DB
+	notificationRepo repository.NotificationRepository
+	digestService    DigestService
+	pollInterval     time.Duration
+	logger           *logger.Logger
+}
+
+func NewDigestWorker(
+	db *postgres.DB,
+	notificationRepo repository.NotificationRepository,
+	digestService DigestService,
+	pollInterval time.Duration,
+	logger *logger.Logger,
+) *DigestWorker {
+	return &DigestWorker{
+		db:               db,
+		notificationRepo: notificationRepo,
+		digestService:    digestService,
+		pollInterval:     pollInterval,
+		logger:           logger,
+	}
+}
+
+func (w *DigestWorker) Run(ctx context.Context) {
+	w.logger.Info("Digest worker started")
+	ticker := time.NewTicker(w.pollInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Digest worker shutting down")
+			return
+		case <-ticker.C:
+			w.scheduleDue(ctx)
+		}
+	}
+}
+
+func (w *DigestWorker) scheduleDue(ctx context.Context) {
+	subscribers, err := w.notificationRepo.ListDigestSubscribers(ctx)
+	if err != nil {
+		w.logger.Error("Failed to list digest subscribers", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, prefs := range subscribers {
+		if ctx.Err() != nil {
+			return
+		}
+		if !prefs.DigestDue(now) {
+			continue
+		}
+		if err := w.schedule(ctx, prefs, now); err != nil {
+			w.logger.Error("Failed to schedule digest",
+				"user_id", prefs.UserID,
+				"error", err,
+			)
+		}
+	}
+}
+
+func (w *DigestWorker) schedule(ctx context.Context, prefs *domain.NotificationPreferences, now time.Time) error {
+	digest, err := w.digestService.BuildDigest(ctx, prefs.UserID)
+	if err != nil {
+		return err
+	}
+
+	var queued bool
+	err = w.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txNotificationRepo := postgres.NewNotificationRepository(tx)
+
+		claimed, err := txNotificationRepo.ClaimDigest(ctx, prefs.UserID, prefs.LastDigestSlot(now))
+		if err != nil {
+			return err
+		}
+		// Пустой дайджест не отправляется, но слот всё равно считается обработанным.
+		if !claimed || len(digest.Items) == 0 {
+			return nil
+		}
+
+		msg := notifier.RenderDigest(digest)
+		if err := txNotificationRepo.Create(ctx, &domain.Notification{
+			UserID:       prefs.UserID,
+			EventType:    domain.EventDigestDaily,
+			Subject:      msg.Subject,
+			Body:         msg.Body,
+			DeliverAfter: now,
+		}); err != nil {
+			return fmt.Errorf("failed to queue digest: %w", err)
+		}
+		queued = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if queued {
+		w.logger.Info("Digest queued",
+			"user_id", prefs.UserID,
+			"open_reviews", len(digest.Items),
+			"breached", digest.Breached,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type eventRepoForEventService interface {
+	ListAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.Event, error)
+	LatestSeq(ctx context.Context) (int64, error)
+}
+
+type userRepoForEventService interface {
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+type teamRepoForEventService interface {
+	Get(ctx context.Context, teamName string) (*domain.Team, error)
+}
+
+type eventService struct {
+	eventRepo eventRepoForEventService
+	userRepo  userRepoForEventService
+	teamRepo  teamRepoForEventService
+}
+
+func NewEventService(
+	eventRepo eventRepoForEventService,
+	userRepo userRepoForEventService,
+	teamRepo teamRepoForEventService,
+) *eventService {
+	return &eventService{
+		eventRepo: eventRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+// StreamFilter проверяет параметры подписки и собирает фильтр; участники
+// команды фиксируются на момент подключения.
+func (s *eventService) StreamFilter(ctx context.Context, userID, teamName string) (*domain.EventFilter, error) {
+	filter := &domain.EventFilter{UserID: userID, TeamName: teamName}
+
+	if userID != "" {
+		exists, err := s.userRepo.Exists(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists {
+			return nil, domain.ErrUserNotFound
+		}
+	}
+
+	if teamName != "" {
+		team, err := s.teamRepo.Get(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		filter.TeamMembers = make(map[string]bool, len(team.Members))
+		for _, m := range team.Members {
+			filter.TeamMembers[m.UserID] = true
+		}
+	}
+	return filter, nil
+}
+
+func (s *eventService) EventsAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.Event, error) {
+	events, err := s.eventRepo.ListAfter(ctx, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+func (s *eventService) LatestSeq(ctx context.Context) (int64, error) {
+	return s.eventRepo.LatestSeq(ctx)
+}
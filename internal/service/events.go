@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type eventRepoForServices interface {
+	Add(ctx context.Context, e *domain.Event) error
+}
+
+// recordEvent пишет событие в outbox; repo должен быть создан на транзакции,
+// в которой меняется состояние, иначе событие может потеряться или появиться без изменения.
+func recordEvent(ctx context.Context, repo eventRepoForServices, eventType, aggregateID string, payload any) error {
+	e, err := domain.NewEvent(eventType, aggregateID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to build %s event: %w", eventType, err)
+	}
+	if err := repo.Add(ctx, e); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+func recordAssignments(ctx context.Context, repo eventRepoForServices, prID string, reviewerIDs []string, source string) error {
+	for _, reviewerID := range reviewerIDs {
+		payload := domain.ReviewerEventPayload{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			Source:        source,
+		}
+		if err := recordEvent(ctx, repo, domain.EventReviewerAssigned, prID, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
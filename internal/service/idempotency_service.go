@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+	"avito/pkg/logger"
+)
+
+type idempotencyRepoForIdempotencyService interface {
+	Reserve(ctx context.Context, rec *domain.IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, rec *domain.IdempotencyRecord) error
+	Delete(ctx context.Context, key, token string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type idempotencyService struct {
+	repo  idempotencyRepoForIdempotencyService
+	ttl   time.Duration
+	lease time.Duration
+	now   func() time.Time
+}
+
+// NewIdempotencyService: ttl — сколько хранится ответ, lease — сколько
+// незавершённый запрос держит ключ; lease должен превышать таймаут запроса.
+func NewIdempotencyService(repo idempotencyRepoForIdempotencyService, ttl, lease time.Duration) *idempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl, lease: lease, now: time.Now}
+}
+
+// Begin занимает ключ за запросом. Если ключ свободен, возвращает
+// незавершённую запись с токеном резервирования: запрос нужно выполнить, а
+// затем передать её в Complete или Release. Если запрос с этим ключом уже
+// завершён, возвращается сохранённый ответ для повтора (Completed = true).
+func (s *idempotencyService) Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotencyRecord, error) {
+	token, err := newReservationToken()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	rec := &domain.IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   now.Add(s.ttl),
+		LockedUntil: now.Add(s.lease),
+		Token:       token,
+	}
+	reserved, err := s.repo.Reserve(ctx, rec)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return rec, nil
+	}
+
+	existing, err := s.repo.Get(ctx, key)
+	if errors.Is(err, domain.ErrNotFound) {
+		// Запись удалили между Reserve и Get (Release или очистка) —
+		// клиент может просто повторить запрос.
+		return nil, domain.ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, err
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if !existing.Completed {
+		return nil, domain.ErrIdempotencyInProgress
+	}
+	return existing, nil
+}
+
+func newReservationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate reservation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// Complete сохраняет ответ. Если аренда истекла и ключ уже занял повтор,
+// возвращается domain.ErrIdempotencyLeaseLost и чужая запись не меняется.
+func (s *idempotencyService) Complete(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	return s.repo.Complete(ctx, rec)
+}
+
+// Release освобождает ключ, если ответ сохранять не нужно (ошибка сервера,
+// отказ в доступе, паника хендлера),
+// чтобы клиент мог повторить запрос с тем же ключом.
+func (s *idempotencyService) Release(ctx context.Context, rec *domain.IdempotencyRecord) error {
+	return s.repo.Delete(ctx, rec.Key, rec.Token)
+}
+
+// IdempotencyPurger периодически удаляет просроченные ключи идемпотентности.
+type IdempotencyPurger struct {
+	repo         idempotencyRepoForIdempotencyService
+	pollInterval time.Duration
+	logger       *logger.Logger
+}
+
+func NewIdempotencyPurger(repo idempotencyRepoForIdempotencyService, pollInterval time.Duration, logger *logger.Logger) *IdempotencyPurger {
+	return &IdempotencyPurger{repo: repo, pollInterval: pollInterval, logger: logger}
+}
+
+func (p *IdempotencyPurger) Run(ctx context.Context) {
+	p.logger.Info("Idempotency purger started")
+	ticker := time.NewTicker(p.pollInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.logger.Info("Idempotency purger shutting down")
+			return
+		case <-ticker.C:
+			if err := p.purge(ctx); err != nil {
+				p.logger.Error("Failed to purge idempotency keys", "error", err)
+			}
+		}
+	}
+}
+
+func (p *IdempotencyPurger) purge(ctx context.Context) error {
+	deleted, err := p.repo.DeleteExpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired keys: %w", err)
+	}
+	if deleted > 0 {
+		p.logger.Info("Expired idempotency keys purged", "count", deleted)
+	}
+	return nil
+}
@@ -8,18 +8,90 @@ import (
 
 type TeamService interface {
 	CreateTeamWithMembers(ctx context.Context, team *domain.Team) (*domain.Team, error)
+	AddMembers(ctx context.Context, teamName string, members []*domain.TeamMember) (*domain.Team, error)
 	GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error)
 }
 
 // UserService интерфейс для работы с пользователями
 type UserService interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error)
+	SetRole(ctx context.Context, userID string, role domain.Role) (*domain.User, error)
 	GetPRsByReviewer(ctx context.Context, userID string) ([]*domain.PullRequestShort, error)
 	ScheduleBatchDeactivate(ctx context.Context, teamID int) error
 	GetUser(ctx context.Context, userID string) (*domain.User, error)
 }
 
+// AccountService связывает аккаунты внешних систем с пользователями сервиса
+type AccountService interface {
+	LinkAccount(ctx context.Context, account *domain.ExternalAccount) (*domain.ExternalAccount, error)
+	UnlinkAccount(ctx context.Context, provider, login string) error
+	ResolveUserID(ctx context.Context, provider, login string) (string, error)
+	ResolveUserIDByExternalID(ctx context.Context, provider string, externalID int64) (string, error)
+}
+
+// WebhookService управляет подписками на исходящие вебхуки
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+// EventService отдаёт журнал доменных событий для потоков
+type EventService interface {
+	StreamFilter(ctx context.Context, userID, teamName string) (*domain.EventFilter, error)
+	EventsAfter(ctx context.Context, afterSeq int64, limit int) ([]*domain.Event, error)
+	LatestSeq(ctx context.Context) (int64, error)
+}
+
+// NotificationService управляет настройками уведомлений пользователей
+type NotificationService interface {
+	GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *domain.NotificationPreferences) (*domain.NotificationPreferences, error)
+}
+
+// DigestService собирает ежедневный дайджест открытых ревью
+type DigestService interface {
+	BuildDigest(ctx context.Context, userID string) (*domain.Digest, error)
+}
+
+// IdempotencyService хранит ответы на запросы с заголовком Idempotency-Key
+type IdempotencyService interface {
+	Begin(ctx context.Context, key, fingerprint string) (*domain.IdempotencyRecord, error)
+	Complete(ctx context.Context, rec *domain.IdempotencyRecord) error
+	Release(ctx context.Context, rec *domain.IdempotencyRecord) error
+}
+
+// TokenService выпускает API-токены и проверяет их
+type TokenService interface {
+	CreateToken(ctx context.Context, name string, scopes []string, userID string) (*domain.APIToken, string, error)
+	ListTokens(ctx context.Context) ([]*domain.APIToken, error)
+	RevokeToken(ctx context.Context, id int64) error
+	Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error)
+	RecordMutation(ctx context.Context, entry *domain.TokenAuditEntry) error
+	ListAudit(ctx context.Context, tokenID int64) ([]*domain.TokenAuditEntry, error)
+}
+
+// Authenticator проверяет bearer-токен внешнего издателя
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*domain.Principal, error)
+}
+
+// StatsProvider отдаёт агрегированную статистику сервиса
+type StatsProvider interface {
+	GetGlobalStats(ctx context.Context) (*GlobalStats, error)
+	GetPairingMatrix(ctx context.Context, teamName string) (*PairingMatrix, error)
+}
+
 var (
-	_ TeamService = (*teamService)(nil)
-	_ UserService = (*userService)(nil)
+	_ TeamService         = (*teamService)(nil)
+	_ UserService         = (*userService)(nil)
+	_ AccountService      = (*accountService)(nil)
+	_ WebhookService      = (*webhookService)(nil)
+	_ EventService        = (*eventService)(nil)
+	_ NotificationService = (*notificationService)(nil)
+	_ DigestService       = (*digestService)(nil)
+	_ IdempotencyService  = (*idempotencyService)(nil)
+	_ TokenService        = (*tokenService)(nil)
+	_ Authenticator       = (*jwtService)(nil)
+	_ StatsProvider       = (*StatsService)(nil)
 )
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+	"avito/internal/jwtauth"
+)
+
+type jwtVerifier interface {
+	Verify(ctx context.Context, token string) (jwtauth.Claims, error)
+}
+
+type userRepoForJWTService interface {
+	Get(ctx context.Context, userID string) (*domain.User, error)
+}
+
+// JWTClaimMapping задаёт, из каких claims берутся пользователь и роль.
+type JWTClaimMapping struct {
+	UserClaim string
+	RoleClaim string
+	// DefaultScopes выдаются, если в токене нет claim scope или scp.
+	DefaultScopes []string
+}
+
+type jwtService struct {
+	verifier jwtVerifier
+	userRepo userRepoForJWTService
+	mapping  JWTClaimMapping
+}
+
+func NewJWTService(verifier jwtVerifier, userRepo userRepoForJWTService, mapping JWTClaimMapping) *jwtService {
+	return &jwtService{
+		verifier: verifier,
+		userRepo: userRepo,
+		mapping:  mapping,
+	}
+}
+
+// Authenticate проверяет JWT и собирает principal. Команда пользователя
+// берётся из БД; роль — из claim, а если его нет, тоже из БД. Пользователь,
+// которого нет в сервисе, получает principal без команды.
+func (s *jwtService) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	claims, err := s.verifier.Verify(ctx, token)
+	if errors.Is(err, jwtauth.ErrInvalidToken) {
+		return nil, fmt.Errorf("%w: %v", domain.ErrUnauthorized, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify token: %w", err)
+	}
+
+	userID := claims.String(s.mapping.UserClaim)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", domain.ErrUnauthorized, s.mapping.UserClaim)
+	}
+
+	scopes := claims.Strings("scope")
+	if len(scopes) == 0 {
+		scopes = claims.Strings("scp")
+	}
+	if len(scopes) == 0 {
+		scopes = s.mapping.DefaultScopes
+	}
+
+	principal := &domain.Principal{
+		Scopes: scopes,
+		UserID: userID,
+		Role:   highestRole(claims.Strings(s.mapping.RoleClaim)),
+	}
+
+	user, err := s.userRepo.Get(ctx, userID)
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		// Пользователь ещё не заведён в сервисе.
+	case err != nil:
+		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+	default:
+		principal.TeamID = user.TeamID
+		if principal.Role == "" {
+			principal.Role = user.Role
+		}
+	}
+	principal.Role = principal.Role.OrDefault()
+	return principal, nil
+}
+
+// highestRole выбирает старшую из известных ролей; неизвестные значения
+// игнорируются.
+func highestRole(values []string) domain.Role {
+	rank := map[domain.Role]int{domain.RoleMember: 1, domain.RoleLead: 2, domain.RoleAdmin: 3}
+	var best domain.Role
+	for _, v := range values {
+		role := domain.Role(v)
+		if rank[role] > rank[best] {
+			best = role
+		}
+	}
+	return best
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"avito/internal/domain"
+	"avito/internal/jwtauth"
+)
+
+type fakeJWTVerifier struct {
+	claims jwtauth.Claims
+	err    error
+}
+
+func (f *fakeJWTVerifier) Verify(context.Context, string) (jwtauth.Claims, error) {
+	return f.claims, f.err
+}
+
+type fakeJWTUserRepo map[string]*domain.User
+
+func (f fakeJWTUserRepo) Get(_ context.Context, userID string) (*domain.User, error) {
+	if u, ok := f[userID]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func TestJWTService_Authenticate(t *testing.T) {
+	users := fakeJWTUserRepo{
+		"u1": {UserID: "u1", TeamID: 7, Role: domain.RoleLead},
+	}
+	mapping := JWTClaimMapping{UserClaim: "preferred_username", RoleClaim: "roles", DefaultScopes: []string{domain.ScopeStatsRead}}
+
+	tests := []struct {
+		name       string
+		claims     jwtauth.Claims
+		verifyErr  error
+		wantErr    error
+		wantUser   string
+		wantTeam   int
+		wantRole   domain.Role
+		wantScopes []string
+	}{
+		{
+			name:       "role from claim, team from db",
+			claims:     jwtauth.Claims{"preferred_username": "u1", "roles": []any{"member", "admin"}, "scope": "pr:write team:admin"},
+			wantUser:   "u1",
+			wantTeam:   7,
+			wantRole:   domain.RoleAdmin,
+			wantScopes: []string{domain.ScopePRWrite, domain.ScopeTeamAdmin},
+		},
+		{
+			name:       "role from db without claim",
+			claims:     jwtauth.Claims{"preferred_username": "u1", "scp": []any{"pr:write"}},
+			wantUser:   "u1",
+			wantTeam:   7,
+			wantRole:   domain.RoleLead,
+			wantScopes: []string{domain.ScopePRWrite},
+		},
+		{
+			name:       "unknown user gets default scopes and member role",
+			claims:     jwtauth.Claims{"preferred_username": "ghost", "roles": "owner"},
+			wantUser:   "ghost",
+			wantRole:   domain.RoleMember,
+			wantScopes: []string{domain.ScopeStatsRead},
+		},
+		{
+			name:    "missing user claim",
+			claims:  jwtauth.Claims{"sub": "u1"},
+			wantErr: domain.ErrUnauthorized,
+		},
+		{
+			name:      "invalid token",
+			verifyErr: jwtauth.ErrInvalidToken,
+			wantErr:   domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewJWTService(&fakeJWTVerifier{claims: tt.claims, err: tt.verifyErr}, users, mapping)
+			p, err := svc.Authenticate(context.Background(), "token")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if p.UserID != tt.wantUser || p.TeamID != tt.wantTeam || p.Role != tt.wantRole {
+				t.Errorf("got user=%q team=%d role=%q, want user=%q team=%d role=%q",
+					p.UserID, p.TeamID, p.Role, tt.wantUser, tt.wantTeam, tt.wantRole)
+			}
+			if !slices.Equal(p.Scopes, tt.wantScopes) {
+				t.Errorf("got scopes %v, want %v", p.Scopes, tt.wantScopes)
+			}
+			if p.Subject() != "user:"+tt.wantUser {
+				t.Errorf("unexpected subject %q", p.Subject())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+type notificationRepoForNotificationService interface {
+	UpsertPreferences(ctx context.Context, p *domain.NotificationPreferences) error
+	GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error)
+}
+
+type userRepoForNotificationService interface {
+	Exists(ctx context.Context, userID string) (bool, error)
+}
+
+type notificationService struct {
+	notificationRepo notificationRepoForNotificationService
+	userRepo         userRepoForNotificationService
+}
+
+func NewNotificationService(
+	notificationRepo notificationRepoForNotificationService,
+	userRepo userRepoForNotificationService,
+) *notificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// GetPreferences возвращает настройки пользователя; если он их ещё не задавал,
+// возвращаются настройки по умолчанию без каналов (уведомления выключены).
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) (*domain.NotificationPreferences, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.GetPreferences")
+	defer span.End()
+
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return &domain.NotificationPreferences{
+				UserID:      userID,
+				Channels:    []string{},
+				Mode:        domain.NotifyModeImmediate,
+				Timezone:    "UTC",
+				DigestAt:    domain.DefaultDigestAt,
+				DailyDigest: true,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) UpdatePreferences(
+	ctx context.Context,
+	prefs *domain.NotificationPreferences,
+) (*domain.NotificationPreferences, error) {
+	ctx, span := tracer.Start(ctx, "NotificationService.UpdatePreferences")
+	defer span.End()
+
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureUser(ctx, prefs.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.notificationRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) ensureUser(ctx context.Context, userID string) error {
+	exists, err := s.userRepo.Exists(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if !exists {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/notifier"
+	"avito/internal/repository"
+	"avito/internal/webhook"
+	"avito/pkg/logger"
+)
+
+type NotificationWorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	Timeout      time.Duration
+	Backoff      webhook.Backoff
+}
+
+// NotificationWorker отправляет уведомления, созданные outbox.NotificationSink:
+// все готовые уведомления пользователя уходят одним сообщением в каждый
+// выбранный им канал.
+type NotificationWorker struct {
+	notificationRepo repository.NotificationRepository
+	channels         map[string]notifier.Channel
+	cfg              NotificationWorkerConfig
+	logger           *logger.Logger
+}
+
+func NewNotificationWorker(
+	notificationRepo repository.NotificationRepository,
+	channels []notifier.Channel,
+	cfg NotificationWorkerConfig,
+	logger *logger.Logger,
+) *NotificationWorker {
+	byName := make(map[string]notifier.Channel, len(channels))
+	for _, ch := range channels {
+		byName[ch.Name()] = ch
+	}
+	return &NotificationWorker{
+		notificationRepo: notificationRepo,
+		channels:         byName,
+		cfg:              cfg,
+		logger:           logger,
+	}
+}
+
+func (w *NotificationWorker) Run(ctx context.Context) {
+	w.logger.Info("Notification worker started")
+	ticker := time.NewTicker(w.cfg.PollInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Notification worker shutting down")
+			return
+		case <-ticker.C:
+			w.sendDue(ctx)
+		}
+	}
+}
+
+func (w *NotificationWorker) sendDue(ctx context.Context) {
+	for i := 0; i < w.cfg.BatchSize && ctx.Err() == nil; i++ {
+		sent, err := w.sendNext(ctx)
+		if err != nil {
+			w.logger.Error("Failed to send notifications", "error", err)
+			return
+		}
+		if !sent {
+			return
+		}
+	}
+}
+
+func (w *NotificationWorker) sendNext(ctx context.Context) (bool, error) {
+	group, err := w.notificationRepo.ClaimDue(ctx, 2*w.cfg.Timeout*time.Duration(max(len(w.channels), 1)))
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	userID := group[0].UserID
+
+	ids := make([]int64, 0, len(group))
+	attempts := 0
+	for _, n := range group {
+		ids = append(ids, n.ID)
+		attempts = max(attempts, n.Attempts)
+	}
+
+	sendErr := w.deliver(ctx, userID, group)
+	status, deliverAfter, errMsg := w.result(attempts, sendErr, time.Now())
+
+	if sendErr != nil {
+		w.logger.Warn("Notification delivery attempt failed",
+			"user_id", userID,
+			"notifications", len(group),
+			"attempt", attempts,
+			"status", status,
+			"error", sendErr,
+		)
+	} else {
+		w.logger.Info("Notifications sent",
+			"user_id", userID,
+			"notifications", len(group),
+		)
+	}
+
+	if err := w.notificationRepo.RecordResult(ctx, ids, status, deliverAfter, errMsg); err != nil {
+		return true, fmt.Errorf("failed to record notifications for %s: %w", userID, err)
+	}
+	return true, nil
+}
+
+// deliver отправляет группу во все каналы пользователя. В каждый канал уходят
+// только уведомления, ещё не доставленные в него на прошлых попытках, и
+// успешная отправка сразу отмечается, так что сбой одного канала не
+// повторяет сообщение в остальных. Каналы, не настроенные на сервере,
+// пропускаются; если не осталось ни одного, это ошибка.
+func (w *NotificationWorker) deliver(ctx context.Context, userID string, group []*domain.Notification) error {
+	prefs, err := w.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return errors.New("user has no notification preferences")
+		}
+		return err
+	}
+
+	to := notifier.NewRecipient(prefs)
+	var (
+		used int
+		errs []error
+	)
+	for _, name := range prefs.Channels {
+		ch, ok := w.channels[name]
+		if !ok {
+			w.logger.Warn("Notification channel is not configured", "channel", name, "user_id", userID)
+			continue
+		}
+		used++
+
+		pending := unsentTo(group, name)
+		if len(pending) == 0 {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, w.cfg.Timeout)
+		err := ch.Send(sendCtx, to, notifier.Combine(pending))
+		cancel()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		ids := make([]int64, 0, len(pending))
+		for _, n := range pending {
+			ids = append(ids, n.ID)
+			n.SentChannels = append(n.SentChannels, name)
+		}
+		if err := w.notificationRepo.MarkChannelSent(ctx, ids, name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	if used == 0 {
+		return errors.New("no configured notification channel")
+	}
+	return errors.Join(errs...)
+}
+
+func unsentTo(group []*domain.Notification, channel string) []*domain.Notification {
+	var pending []*domain.Notification
+	for _, n := range group {
+		if !slices.Contains(n.SentChannels, channel) {
+			pending = append(pending, n)
+		}
+	}
+	return pending
+}
+
+// result переводит группу в sent, откладывает следующую попытку по backoff или,
+// после MaxAttempts попыток, помечает её failed.
+func (w *NotificationWorker) result(attempts int, sendErr error, now time.Time) (string, time.Time, sql.NullString) {
+	if sendErr == nil {
+		return domain.NotificationStatusSent, now, sql.NullString{}
+	}
+	errMsg := sql.NullString{String: sendErr.Error(), Valid: true}
+	if attempts >= w.cfg.MaxAttempts {
+		return domain.NotificationStatusFailed, now, errMsg
+	}
+	return domain.NotificationStatusPending, now.Add(w.cfg.Backoff.Delay(attempts)), errMsg
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/notifier"
+	"avito/internal/repository"
+	"avito/internal/webhook"
+	"avito/pkg/logger"
+)
+
+type fakeNotificationRepo struct {
+	repository.NotificationRepository
+	prefs   map[string]*domain.NotificationPreferences
+	due     [][]*domain.Notification
+	results []string
+	marked  []string
+}
+
+func (f *fakeNotificationRepo) GetPreferences(_ context.Context, userID string) (*domain.NotificationPreferences, error) {
+	p, ok := f.prefs[userID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (f *fakeNotificationRepo) ClaimDue(_ context.Context, _ time.Duration) ([]*domain.Notification, error) {
+	if len(f.due) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	group := f.due[0]
+	f.due = f.due[1:]
+	for _, n := range group {
+		n.Attempts++
+	}
+	return group, nil
+}
+
+func (f *fakeNotificationRepo) MarkChannelSent(_ context.Context, ids []int64, channel string) error {
+	f.marked = append(f.marked, channel)
+	return nil
+}
+
+func (f *fakeNotificationRepo) RecordResult(_ context.Context, ids []int64, status string, _ time.Time, _ sql.NullString) error {
+	f.results = append(f.results, status)
+	return nil
+}
+
+type fakeChannel struct {
+	name  string
+	err   error
+	fails int
+	sent  []notifier.Message
+}
+
+func (c *fakeChannel) Name() string { return c.name }
+
+func (c *fakeChannel) Send(_ context.Context, _ notifier.Recipient, msg notifier.Message) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.fails > 0 {
+		c.fails--
+		return errors.New("timeout")
+	}
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func newTestNotificationWorker(repo repository.NotificationRepository, channels ...notifier.Channel) *NotificationWorker {
+	return NewNotificationWorker(repo, channels, NotificationWorkerConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  2,
+		Timeout:      time.Second,
+		Backoff:      webhook.Backoff{Base: time.Second, Max: time.Minute},
+	}, logger.NewWithWriter(io.Discard, "error", "json"))
+}
+
+func TestNotificationWorker_SendsGroupAsOneMessage(t *testing.T) {
+	chat := &fakeChannel{name: domain.NotifyChannelChat}
+	repo := &fakeNotificationRepo{
+		prefs: map[string]*domain.NotificationPreferences{
+			"u1": {UserID: "u1", Channels: []string{domain.NotifyChannelChat}},
+		},
+		due: [][]*domain.Notification{{
+			{ID: 1, UserID: "u1", Subject: "a", Body: "b"},
+			{ID: 2, UserID: "u1", Subject: "c", Body: "d"},
+		}},
+	}
+
+	newTestNotificationWorker(repo, chat).sendDue(context.Background())
+
+	if len(chat.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(chat.sent))
+	}
+	if len(repo.results) != 1 || repo.results[0] != domain.NotificationStatusSent {
+		t.Errorf("results = %v", repo.results)
+	}
+}
+
+func TestNotificationWorker_FailsAfterMaxAttempts(t *testing.T) {
+	chat := &fakeChannel{name: domain.NotifyChannelChat, err: errors.New("boom")}
+	group := []*domain.Notification{{ID: 1, UserID: "u1", Subject: "a", Body: "b"}}
+	repo := &fakeNotificationRepo{
+		prefs: map[string]*domain.NotificationPreferences{
+			"u1": {UserID: "u1", Channels: []string{domain.NotifyChannelChat}},
+		},
+		due: [][]*domain.Notification{group, group},
+	}
+
+	newTestNotificationWorker(repo, chat).sendDue(context.Background())
+
+	want := []string{domain.NotificationStatusPending, domain.NotificationStatusFailed}
+	if len(repo.results) != len(want) {
+		t.Fatalf("results = %v, want %v", repo.results, want)
+	}
+	for i := range want {
+		if repo.results[i] != want[i] {
+			t.Errorf("result %d = %s, want %s", i, repo.results[i], want[i])
+		}
+	}
+}
+
+func TestNotificationWorker_RetriesOnlyFailedChannels(t *testing.T) {
+	email := &fakeChannel{name: domain.NotifyChannelEmail}
+	chat := &fakeChannel{name: domain.NotifyChannelChat, fails: 1}
+	group := []*domain.Notification{{ID: 1, UserID: "u1", Subject: "a", Body: "b"}}
+	repo := &fakeNotificationRepo{
+		prefs: map[string]*domain.NotificationPreferences{
+			"u1": {UserID: "u1", Channels: []string{domain.NotifyChannelEmail, domain.NotifyChannelChat}},
+		},
+		due: [][]*domain.Notification{group, group},
+	}
+
+	newTestNotificationWorker(repo, email, chat).sendDue(context.Background())
+
+	if len(email.sent) != 1 {
+		t.Errorf("email sent %d times, want 1", len(email.sent))
+	}
+	if len(chat.sent) != 1 {
+		t.Errorf("chat sent %d times, want 1 after retry", len(chat.sent))
+	}
+	want := []string{domain.NotificationStatusPending, domain.NotificationStatusSent}
+	if len(repo.results) != 2 || repo.results[0] != want[0] || repo.results[1] != want[1] {
+		t.Errorf("results = %v, want %v", repo.results, want)
+	}
+}
+
+func TestNotificationWorker_UnconfiguredChannel(t *testing.T) {
+	repo := &fakeNotificationRepo{
+		prefs: map[string]*domain.NotificationPreferences{
+			"u1": {UserID: "u1", Channels: []string{domain.NotifyChannelEmail}},
+		},
+		due: [][]*domain.Notification{{{ID: 1, UserID: "u1", Subject: "a", Body: "b", Attempts: 1}}},
+	}
+
+	newTestNotificationWorker(repo, &fakeChannel{name: domain.NotifyChannelChat}).sendDue(context.Background())
+
+	if len(repo.results) != 1 || repo.results[0] != domain.NotificationStatusFailed {
+		t.Errorf("results = %v, want [failed]", repo.results)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand" //nolint:gosec
+	"sync"
+	"time"
 
 	"avito/internal/domain"
 	"avito/internal/repository/postgres"
@@ -15,6 +17,10 @@ type PRService interface {
 	CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error)
 	MergePR(ctx context.Context, prID string) (*domain.PullRequest, error)
 	ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error)
+	FillMissingReviewers(ctx context.Context, teamID, taskID int) (int, error)
+	ExplainAssignment(ctx context.Context, prID string) (*domain.AssignmentExplanation, error)
+	ListPRs(ctx context.Context, filter *domain.PRFilter) (*domain.PRPage, error)
+	GetPR(ctx context.Context, prID string) (*domain.PullRequestDetails, error)
 }
 
 type prRepoForPRService interface {
@@ -22,6 +28,8 @@ type prRepoForPRService interface {
 	Get(ctx context.Context, prID string) (*domain.PullRequest, error)
 	Merge(ctx context.Context, prID string, mergedStatusID int16) (*domain.PullRequest, error)
 	ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newUserID string) error
+	GetUnderstaffedByTeam(ctx context.Context, teamID int, maxReviewers int) ([]*domain.PullRequest, error)
+	Search(ctx context.Context, filter *domain.PRFilter) (*domain.PRPage, error)
 }
 
 type userRepoForPRService interface {
@@ -33,11 +41,24 @@ type teamRepoForPRService interface {
 	GetByID(ctx context.Context, teamID int) (*domain.Team, error)
 }
 
+type decisionRepoForPRService interface {
+	GetByPR(ctx context.Context, prID string) ([]*domain.AssignmentDecision, error)
+	RecentPairCounts(ctx context.Context, authorID string, window int) (map[string]int, error)
+}
+
 type prService struct {
-	db       *postgres.DB
-	prRepo   prRepoForPRService
-	userRepo userRepoForPRService
-	teamRepo teamRepoForPRService
+	db           *postgres.DB
+	prRepo       prRepoForPRService
+	userRepo     userRepoForPRService
+	teamRepo     teamRepoForPRService
+	decisionRepo decisionRepoForPRService
+
+	rndMu sync.Mutex
+	rnd   *rand.Rand
+
+	strategy       string
+	fairnessWindow int
+	observer       AssignmentObserver
 }
 
 func NewPRService(
@@ -45,16 +66,29 @@ func NewPRService(
 	prRepo prRepoForPRService,
 	userRepo userRepoForPRService,
 	teamRepo teamRepoForPRService,
+	decisionRepo decisionRepoForPRService,
+	opts ...PROption,
 ) PRService {
-	return &prService{
-		db:       db,
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+	s := &prService{
+		db:           db,
+		prRepo:       prRepo,
+		userRepo:     userRepo,
+		teamRepo:     teamRepo,
+		decisionRepo: decisionRepo,
+		rnd:          rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+		strategy:     domain.StrategyRandom,
+		observer:     noopAssignmentObserver{},
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *prService) CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PRService.CreatePR")
+	defer span.End()
+
 	author, err := s.userRepo.Get(ctx, authorID)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
@@ -62,40 +96,26 @@ func (s *prService) CreatePR(ctx context.Context, prID, prName, authorID string)
 		}
 		return nil, fmt.Errorf("failed to get author: %w", err)
 	}
-	team, err := s.teamRepo.GetByID(ctx, author.TeamID)
+	team, err := s.loadTeam(ctx, author.TeamID)
 	if err != nil {
 		if errors.Is(err, domain.ErrTeamNotFound) {
 			return nil, fmt.Errorf("author's team not found: %w", err)
 		}
 		return nil, fmt.Errorf("failed to get author's team: %w", err)
 	}
-	teamMembersDB, err := s.userRepo.GetByTeamID(ctx, team.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get team members: %w", err)
-	}
 
-	teamMembers := make([]*domain.TeamMember, 0, len(teamMembersDB))
-	for _, u := range teamMembersDB {
-		teamMembers = append(teamMembers, u.ToTeamMember())
-	}
-	teamDomain := domain.Team{
-		ID:      team.ID,
-		Name:    team.Name,
-		Members: teamMembers,
+	penalties, err := s.pairPenalties(ctx, authorID)
+	if err != nil {
+		return nil, err
 	}
+	decision := s.findReviewers(team, prID, authorID, penalties)
 
-	reviewers := s.findReviewers(&teamDomain, authorID)
-
-	reviewerIDs := make([]string, 0, len(reviewers))
-	for _, r := range reviewers {
-		reviewerIDs = append(reviewerIDs, r.UserID)
-	}
 	pr := &domain.PullRequest{
 		PullRequestID:     prID,
 		PullRequestName:   prName,
 		AuthorID:          authorID,
 		Status:            domain.PRStatusOpen,
-		AssignedReviewers: reviewerIDs,
+		AssignedReviewers: decision.Selected,
 	}
 
 	if err = pr.Validate(); err != nil {
@@ -105,55 +125,119 @@ func (s *prService) CreatePR(ctx context.Context, prID, prName, authorID string)
 
 	err = s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
 		txPRRepo := postgres.NewPullRequestRepository(tx)
+		txDecisionRepo := postgres.NewDecisionRepository(tx)
+		txEventRepo := postgres.NewEventRepository(tx)
 		if err = txPRRepo.Create(ctx, pr); err != nil {
 			return fmt.Errorf("failed to create PR in repo: %w", err)
 		}
-		return nil
+		if err = txDecisionRepo.Create(ctx, decision); err != nil {
+			return fmt.Errorf("failed to save assignment decision: %w", err)
+		}
+		if err = recordEvent(ctx, txEventRepo, domain.EventPRCreated, prID, domain.NewPREventPayload(pr)); err != nil {
+			return err
+		}
+		return recordAssignments(ctx, txEventRepo, prID, pr.AssignedReviewers, domain.DecisionKindCreate)
 	})
 	if err != nil {
 		return nil, err
 	}
+	s.observe(domain.DecisionKindCreate, decision)
 	pr.SyncStatus()
+	if decision.RuleViolated {
+		pr.PairingWarning = pairingWarning(decision.Rule)
+	}
 	return pr, nil
 }
 
-func (s *prService) findReviewers(team *domain.Team, authorID string) []*domain.TeamMember {
-	candidates := team.GetActiveMembersExcluding(authorID)
-	if len(candidates) == 0 {
-		return []*domain.TeamMember{}
+func (s *prService) loadTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	teamMembersDB, err := s.userRepo.GetByTeamID(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
 	}
-	if len(candidates) <= 2 {
-		return candidates
+
+	teamMembers := make([]*domain.TeamMember, 0, len(teamMembersDB))
+	for _, u := range teamMembersDB {
+		teamMembers = append(teamMembers, u.ToTeamMember())
 	}
+	return &domain.Team{
+		ID:           team.ID,
+		Name:         team.Name,
+		ReviewerRule: team.ReviewerRule,
+		Version:      team.Version,
+		Members:      teamMembers,
+	}, nil
+}
+
+func pairingWarning(rule domain.ReviewerRule) string {
+	return fmt.Sprintf("reviewer rule %q could not be satisfied with available candidates", rule)
+}
 
-	//nolint:gosec
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
+func (s *prService) findReviewers(
+	team *domain.Team,
+	prID, authorID string,
+	penalties map[string]int,
+) *domain.AssignmentDecision {
+	candidates, excluded := team.SplitCandidates(authorID)
+	return s.decide(selectionInput{
+		prID:       prID,
+		kind:       domain.DecisionKindCreate,
+		rule:       team.ReviewerRule,
+		candidates: candidates,
+		excluded:   excluded,
+		penalties:  penalties,
+		slots:      domain.MaxReviewers,
 	})
-	return candidates[:2]
 }
 
 func (s *prService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
+	ctx, span := tracer.Start(ctx, "PRService.MergePR")
+	defer span.End()
+
 	pr, err := s.prRepo.Get(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkIfMatch(ctx, pr); err != nil {
+		return nil, err
+	}
 	if pr.IsMerged() {
 		return pr, nil
 	}
 
-	updatedPR, err := s.prRepo.Merge(ctx, prID, domain.PRStatusIDMerged)
+	var updatedPR *domain.PullRequest
+	err = s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txPRRepo := postgres.NewPullRequestRepository(tx)
+		txEventRepo := postgres.NewEventRepository(tx)
+		if err := txPRRepo.LockVersion(ctx, prID, pr.Version); err != nil {
+			return err
+		}
+		updatedPR, err = txPRRepo.Merge(ctx, prID, domain.PRStatusIDMerged)
+		if err != nil {
+			return fmt.Errorf("failed to merge PR: %w", err)
+		}
+		return recordEvent(ctx, txEventRepo, domain.EventPRMerged, prID, domain.NewPREventPayload(updatedPR))
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to merge PR: %w", err)
+		return nil, err
 	}
 	return updatedPR, nil
 }
 
 func (s *prService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (*domain.PullRequest, string, error) {
+	ctx, span := tracer.Start(ctx, "PRService.ReassignReviewer")
+	defer span.End()
+
 	pr, err := s.prRepo.Get(ctx, prID)
 	if err != nil {
 		return nil, "", err
 	}
+	if err := checkIfMatch(ctx, pr); err != nil {
+		return nil, "", err
+	}
 	if !pr.CanBeModified() {
 		return nil, "", domain.ErrPRMerged
 	}
@@ -165,45 +249,203 @@ func (s *prService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get old reviewer: %w", err)
 	}
-	team, err := s.teamRepo.GetByID(ctx, oldReviewer.TeamID)
+	teamDomain, err := s.loadTeam(ctx, oldReviewer.TeamID)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to get old reviewer's team: %w", err)
 	}
-	teamMembersDB, err := s.userRepo.GetByTeamID(ctx, team.ID)
+
+	candidates, excluded := teamDomain.SplitCandidates(pr.AuthorID, pr.AssignedReviewers...)
+	if len(candidates) == 0 {
+		s.observe(domain.DecisionKindReassign, nil)
+		return nil, "", domain.ErrNoCandidate
+	}
+
+	penalties, err := s.pairPenalties(ctx, pr.AuthorID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get team members: %w", err)
+		return nil, "", err
 	}
+	decision := s.decide(selectionInput{
+		prID:       prID,
+		kind:       domain.DecisionKindReassign,
+		rule:       teamDomain.ReviewerRule,
+		candidates: candidates,
+		excluded:   excluded,
+		kept:       reviewerLevels(teamDomain, pr.AssignedReviewers, oldReviewerID),
+		penalties:  penalties,
+		slots:      1,
+	})
+	decision.ReplacedUserID = oldReviewerID
+	newReviewerID := decision.Selected[0]
 
-	teamMembers := make([]*domain.TeamMember, 0, len(teamMembersDB))
-	for _, u := range teamMembersDB {
-		teamMembers = append(teamMembers, u.ToTeamMember())
+	err = s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txPRRepo := postgres.NewPullRequestRepository(tx)
+		txDecisionRepo := postgres.NewDecisionRepository(tx)
+		txEventRepo := postgres.NewEventRepository(tx)
+		// Кандидат выбран по прочитанным ранее PR и составу команды: если
+		// кто-то успел их изменить, выбор мог устареть.
+		if err := txPRRepo.LockVersion(ctx, prID, pr.Version); err != nil {
+			return err
+		}
+		if err := postgres.NewTeamRepository(tx).LockVersion(ctx, teamDomain.ID, teamDomain.Version); err != nil {
+			return err
+		}
+		if err := txPRRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID); err != nil {
+			return fmt.Errorf("failed to replace reviewer in repo: %w", err)
+		}
+		if err := txDecisionRepo.Create(ctx, decision); err != nil {
+			return fmt.Errorf("failed to save assignment decision: %w", err)
+		}
+		payload := domain.ReviewerEventPayload{
+			PullRequestID: prID,
+			ReviewerID:    newReviewerID,
+			OldReviewerID: oldReviewerID,
+			Source:        domain.DecisionKindReassign,
+		}
+		return recordEvent(ctx, txEventRepo, domain.EventReviewerReassigned, prID, payload)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	s.observe(domain.DecisionKindReassign, decision)
+
+	updatedPR, err := s.prRepo.Get(ctx, prID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get updated PR: %w", err)
 	}
-	teamDomain := domain.Team{
-		ID:      team.ID,
-		Name:    team.Name,
-		Members: teamMembers,
+	if decision.RuleViolated {
+		updatedPR.PairingWarning = pairingWarning(decision.Rule)
 	}
+	return updatedPR, newReviewerID, nil
+}
 
-	excludeIDs := make([]string, 0, len(pr.AssignedReviewers)+1)
-	excludeIDs = append(excludeIDs, pr.AuthorID)
-	excludeIDs = append(excludeIDs, pr.AssignedReviewers...)
+func (s *prService) FillMissingReviewers(ctx context.Context, teamID, taskID int) (int, error) {
+	ctx, span := tracer.Start(ctx, "PRService.FillMissingReviewers")
+	defer span.End()
 
-	candidates := teamDomain.GetActiveMembersExcluding(excludeIDs...)
-	if len(candidates) == 0 {
-		return nil, "", domain.ErrNoCandidate
+	prs, err := s.prRepo.GetUnderstaffedByTeam(ctx, teamID, domain.MaxReviewers)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get understaffed PRs: %w", err)
+	}
+	if len(prs) == 0 {
+		return 0, nil
+	}
+
+	team, err := s.loadTeam(ctx, teamID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	filled := 0
+	for _, pr := range prs {
+		candidates, excluded := team.SplitCandidates(pr.AuthorID, pr.AssignedReviewers...)
+		penalties, err := s.pairPenalties(ctx, pr.AuthorID)
+		if err != nil {
+			return filled, err
+		}
+		decision := s.decide(selectionInput{
+			prID:       pr.PullRequestID,
+			kind:       domain.DecisionKindFill,
+			rule:       team.ReviewerRule,
+			candidates: candidates,
+			excluded:   excluded,
+			kept:       reviewerLevels(team, pr.AssignedReviewers, ""),
+			penalties:  penalties,
+			slots:      pr.MissingReviewers(),
+		})
+		if len(decision.Selected) == 0 {
+			s.observe(domain.DecisionKindFill, decision)
+			continue
+		}
+
+		err = s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+			txPRRepo := postgres.NewPullRequestRepository(tx)
+			txAuditRepo := postgres.NewAuditRepository(tx)
+			txDecisionRepo := postgres.NewDecisionRepository(tx)
+			txEventRepo := postgres.NewEventRepository(tx)
+			if err := txPRRepo.LockVersion(ctx, pr.PullRequestID, pr.Version); err != nil {
+				return err
+			}
+			for _, reviewerID := range decision.Selected {
+				if err := txPRRepo.AddReviewer(ctx, pr.PullRequestID, reviewerID); err != nil {
+					return err
+				}
+				entry := &domain.AssignmentAudit{
+					PullRequestID: pr.PullRequestID,
+					UserID:        reviewerID,
+					Action:        domain.AuditActionAssigned,
+					Source:        domain.AuditSourceAutoFill,
+					TaskID:        &taskID,
+				}
+				if err := txAuditRepo.Add(ctx, entry); err != nil {
+					return err
+				}
+			}
+			if err := txDecisionRepo.Create(ctx, decision); err != nil {
+				return err
+			}
+			return recordAssignments(ctx, txEventRepo, pr.PullRequestID, decision.Selected, domain.AuditSourceAutoFill)
+		})
+		if errors.Is(err, domain.ErrConcurrentModification) {
+			// PR изменили после выборки: его доберёт следующая задача, если нужно.
+			continue
+		}
+		if err != nil {
+			return filled, fmt.Errorf("failed to fill reviewers for PR %s: %w", pr.PullRequestID, err)
+		}
+		s.observe(domain.DecisionKindFill, decision)
+		filled += len(decision.Selected)
 	}
+	return filled, nil
+}
 
-	//nolint:gosec
-	newReviewer := candidates[rand.Intn(len(candidates))]
-	newReviewerID := newReviewer.UserID
+func (s *prService) ExplainAssignment(ctx context.Context, prID string) (*domain.AssignmentExplanation, error) {
+	ctx, span := tracer.Start(ctx, "PRService.ExplainAssignment")
+	defer span.End()
 
-	if err = s.prRepo.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID); err != nil {
-		return nil, "", fmt.Errorf("failed to replace reviewer in repo: %w", err)
+	pr, err := s.prRepo.Get(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	decisions, err := s.decisionRepo.GetByPR(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignment decisions: %w", err)
 	}
+	return &domain.AssignmentExplanation{
+		PullRequest: pr,
+		Decisions:   decisions,
+	}, nil
+}
 
-	updatedPR, err := s.prRepo.Get(ctx, prID)
+func (s *prService) ListPRs(ctx context.Context, filter *domain.PRFilter) (*domain.PRPage, error) {
+	ctx, span := tracer.Start(ctx, "PRService.ListPRs")
+	defer span.End()
+
+	if err := filter.Normalize(); err != nil {
+		return nil, err
+	}
+	page, err := s.prRepo.Search(ctx, filter)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get updated PR: %w", err)
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
 	}
-	return updatedPR, newReviewerID, nil
+	return page, nil
+}
+
+func (s *prService) GetPR(ctx context.Context, prID string) (*domain.PullRequestDetails, error) {
+	ctx, span := tracer.Start(ctx, "PRService.GetPR")
+	defer span.End()
+
+	pr, err := s.prRepo.Get(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]*domain.ReviewerState, 0, len(pr.AssignedReviewers))
+	for _, reviewerID := range pr.AssignedReviewers {
+		user, err := s.userRepo.Get(ctx, reviewerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviewer %s: %w", reviewerID, err)
+		}
+		reviewers = append(reviewers, domain.NewReviewerState(user))
+	}
+	return &domain.PullRequestDetails{PR: pr, Reviewers: reviewers}, nil
 }
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"avito/internal/domain"
+)
+
+type ifMatchKey struct{}
+
+// WithIfMatch передаёт в сервис версию, которую клиент ожидает увидеть у
+// изменяемого PR (заголовок If-Match). Без неё изменения безусловны.
+func WithIfMatch(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, ifMatchKey{}, version)
+}
+
+func checkIfMatch(ctx context.Context, pr *domain.PullRequest) error {
+	version, ok := ctx.Value(ifMatchKey{}).(int)
+	if !ok || version == pr.Version {
+		return nil
+	}
+	return domain.ErrPreconditionFailed
+}
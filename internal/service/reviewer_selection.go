@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand" //nolint:gosec
+	"sort"
+
+	"avito/internal/domain"
+)
+
+type PROption func(*prService)
+
+// WithRandSource задаёт источник, из которого берутся seed'ы для каждого назначения.
+func WithRandSource(src rand.Source) PROption {
+	return func(s *prService) {
+		s.rnd = rand.New(src) //nolint:gosec
+	}
+}
+
+func WithSeed(seed int64) PROption {
+	return WithRandSource(rand.NewSource(seed))
+}
+
+// WithFairness включает стратегию fair: кандидаты, ревьюившие автора в последних
+// window назначениях, выбираются реже.
+func WithFairness(window int) PROption {
+	return func(s *prService) {
+		s.strategy = domain.StrategyFair
+		s.fairnessWindow = window
+	}
+}
+
+// AssignmentObserver получает исход каждого подбора ревьюеров.
+type AssignmentObserver interface {
+	ObserveAssignment(strategy, kind, outcome string)
+}
+
+type noopAssignmentObserver struct{}
+
+func (noopAssignmentObserver) ObserveAssignment(string, string, string) {}
+
+// WithAssignmentObserver передаёт исходы подбора, например в метрики.
+func WithAssignmentObserver(o AssignmentObserver) PROption {
+	return func(s *prService) {
+		s.observer = o
+	}
+}
+
+type candidate struct {
+	id      string
+	level   domain.Seniority
+	penalty int
+}
+
+type selectionInput struct {
+	prID       string
+	kind       string
+	rule       domain.ReviewerRule
+	candidates []*domain.TeamMember
+	excluded   []domain.Exclusion
+	kept       []domain.Seniority
+	penalties  map[string]int
+	slots      int
+}
+
+func (s *prService) nextSeed() int64 {
+	s.rndMu.Lock()
+	defer s.rndMu.Unlock()
+	return s.rnd.Int63()
+}
+
+func (s *prService) observe(kind string, d *domain.AssignmentDecision) {
+	if d == nil {
+		s.observer.ObserveAssignment(s.strategy, kind, domain.AssignmentOutcomeNoCandidate)
+		return
+	}
+	s.observer.ObserveAssignment(s.strategy, kind, d.Outcome())
+}
+
+func (s *prService) pairPenalties(ctx context.Context, authorID string) (map[string]int, error) {
+	if s.strategy != domain.StrategyFair {
+		return nil, nil
+	}
+	counts, err := s.decisionRepo.RecentPairCounts(ctx, authorID, s.fairnessWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent pairings: %w", err)
+	}
+	return counts, nil
+}
+
+func (s *prService) decide(in selectionInput) *domain.AssignmentDecision {
+	pool := make([]candidate, 0, len(in.candidates))
+	for _, c := range in.candidates {
+		pool = append(pool, candidate{
+			id:      c.UserID,
+			level:   c.Seniority.OrDefault(),
+			penalty: in.penalties[c.UserID],
+		})
+	}
+	sortCandidates(pool)
+
+	candidateIDs := make([]string, 0, len(pool))
+	candidateLevels := make([]domain.Seniority, 0, len(pool))
+	candidatePenalties := make([]int, 0, len(pool))
+	for _, c := range pool {
+		candidateIDs = append(candidateIDs, c.id)
+		candidateLevels = append(candidateLevels, c.level)
+		candidatePenalties = append(candidatePenalties, c.penalty)
+	}
+
+	seed := s.nextSeed()
+	rule := in.rule.OrDefault()
+	selected, violated := selectReviewers(seed, s.strategy, pool, in.kept, in.slots, rule)
+	return &domain.AssignmentDecision{
+		PullRequestID:      in.prID,
+		Kind:               in.kind,
+		Strategy:           s.strategy,
+		Rule:               rule,
+		Seed:               seed,
+		Slots:              in.slots,
+		Candidates:         candidateIDs,
+		CandidateLevels:    candidateLevels,
+		CandidatePenalties: candidatePenalties,
+		KeptLevels:         in.kept,
+		Excluded:           in.excluded,
+		Selected:           selected,
+		RuleViolated:       violated,
+	}
+}
+
+// ReplayDecision повторяет выбор по сохранённым seed и кандидатам.
+func ReplayDecision(d *domain.AssignmentDecision) []string {
+	pool := make([]candidate, 0, len(d.Candidates))
+	for i, id := range d.Candidates {
+		c := candidate{id: id, level: domain.SeniorityMiddle}
+		if i < len(d.CandidateLevels) {
+			c.level = d.CandidateLevels[i].OrDefault()
+		}
+		if i < len(d.CandidatePenalties) {
+			c.penalty = d.CandidatePenalties[i]
+		}
+		pool = append(pool, c)
+	}
+	strategy := d.Strategy
+	if strategy == "" {
+		strategy = domain.StrategyRandom
+	}
+	selected, _ := selectReviewers(d.Seed, strategy, pool, d.KeptLevels, d.Slots, d.Rule.OrDefault())
+	return selected
+}
+
+// selectReviewers перемешивает кандидатов по seed и берёт первую в этом
+// порядке комбинацию, удовлетворяющую правилу команды. Если такой нет,
+// возвращаются первые slots кандидатов и признак нарушения правила.
+func selectReviewers(
+	seed int64,
+	strategy string,
+	candidates []candidate,
+	kept []domain.Seniority,
+	slots int,
+	rule domain.ReviewerRule,
+) ([]string, bool) {
+	if len(candidates) == 0 || slots <= 0 {
+		return []string{}, !rule.Satisfied(kept)
+	}
+
+	pool := make([]candidate, len(candidates))
+	copy(pool, candidates)
+	sortCandidates(pool)
+	if len(pool) > slots {
+		rng := rand.New(rand.NewSource(seed)) //nolint:gosec
+		if strategy == domain.StrategyFair {
+			weightedShuffle(rng, pool)
+		} else {
+			rng.Shuffle(len(pool), func(i, j int) {
+				pool[i], pool[j] = pool[j], pool[i]
+			})
+		}
+	}
+
+	k := min(slots, len(pool))
+	picked, ok := firstSatisfying(pool, kept, k, rule)
+	if !ok {
+		picked = pool[:k]
+	}
+
+	selected := make([]string, 0, len(picked))
+	for _, c := range picked {
+		selected = append(selected, c.id)
+	}
+	return selected, !ok
+}
+
+func firstSatisfying(pool []candidate, kept []domain.Seniority, k int, rule domain.ReviewerRule) ([]candidate, bool) {
+	idx := make([]int, 0, k)
+	levels := make([]domain.Seniority, 0, len(kept)+k)
+
+	var walk func(start int) bool
+	walk = func(start int) bool {
+		if len(idx) == k {
+			levels = append(levels[:0], kept...)
+			for _, i := range idx {
+				levels = append(levels, pool[i].level)
+			}
+			return rule.Satisfied(levels)
+		}
+		for i := start; i <= len(pool)-(k-len(idx)); i++ {
+			idx = append(idx, i)
+			if walk(i + 1) {
+				return true
+			}
+			idx = idx[:len(idx)-1]
+		}
+		return false
+	}
+
+	if !walk(0) {
+		return nil, false
+	}
+	result := make([]candidate, 0, k)
+	for _, i := range idx {
+		result = append(result, pool[i])
+	}
+	return result, true
+}
+
+// weightedShuffle упорядочивает кандидатов случайно с весом 1/(1+penalty)
+// (алгоритм Efraimidis–Spirakis: ключ u^(1/w), сортировка по убыванию).
+func weightedShuffle(rng *rand.Rand, pool []candidate) {
+	keys := make(map[string]float64, len(pool))
+	for _, c := range pool {
+		keys[c.id] = math.Pow(rng.Float64(), float64(1+c.penalty))
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		return keys[pool[i].id] > keys[pool[j].id]
+	})
+}
+
+func sortCandidates(pool []candidate) {
+	sort.Slice(pool, func(i, j int) bool {
+		return pool[i].id < pool[j].id
+	})
+}
+
+func reviewerLevels(team *domain.Team, reviewerIDs []string, skipID string) []domain.Seniority {
+	levels := make([]domain.Seniority, 0, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		if id == skipID {
+			continue
+		}
+		if member := team.GetMember(id); member != nil {
+			levels = append(levels, member.Seniority.OrDefault())
+		}
+	}
+	return levels
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"avito/internal/domain"
+)
+
+func members(ids ...string) []*domain.TeamMember {
+	result := make([]*domain.TeamMember, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, &domain.TeamMember{UserID: id, Username: id, IsActive: true})
+	}
+	return result
+}
+
+func pool(ids ...string) []candidate {
+	result := make([]candidate, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, candidate{id: id, level: domain.SeniorityMiddle})
+	}
+	return result
+}
+
+func TestSelectReviewers_Deterministic(t *testing.T) {
+	first, _ := selectReviewers(42, domain.StrategyRandom, pool("u5", "u1", "u4", "u2", "u3"), nil, 2, domain.ReviewerRuleNone)
+	second, _ := selectReviewers(42, domain.StrategyRandom, pool("u3", "u2", "u1", "u5", "u4"), nil, 2, domain.ReviewerRuleNone)
+
+	if len(first) != 2 {
+		t.Fatalf("selectReviewers() returned %d reviewers, want 2", len(first))
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("selectReviewers() is order dependent: %v vs %v", first, second)
+	}
+}
+
+func TestSelectReviewers_FewCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		slots      int
+		want       []string
+	}{
+		{name: "No candidates", candidates: nil, slots: 2, want: []string{}},
+		{name: "No slots", candidates: []string{"u1"}, slots: 0, want: []string{}},
+		{name: "Less than slots", candidates: []string{"u2", "u1"}, slots: 2, want: []string{"u1", "u2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := selectReviewers(7, domain.StrategyRandom, pool(tt.candidates...), nil, tt.slots, domain.ReviewerRuleNone)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectReviewers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRService_FindReviewersWithSeed(t *testing.T) {
+	team := &domain.Team{
+		ID:      1,
+		Name:    "backend",
+		Members: members("author", "u1", "u2", "u3", "u4"),
+	}
+
+	s1 := NewPRService(nil, nil, nil, nil, nil, WithSeed(1)).(*prService)
+	s2 := NewPRService(nil, nil, nil, nil, nil, WithSeed(1)).(*prService)
+
+	for i := 0; i < 5; i++ {
+		d1 := s1.findReviewers(team, "pr-1", "author", nil)
+		d2 := s2.findReviewers(team, "pr-1", "author", nil)
+		if d1.Seed != d2.Seed || !reflect.DeepEqual(d1.Selected, d2.Selected) {
+			t.Fatalf("round %d: same seed produced different decisions: %+v vs %+v", i, d1, d2)
+		}
+		if len(d1.Selected) != domain.MaxReviewers {
+			t.Errorf("round %d: selected %d reviewers, want %d", i, len(d1.Selected), domain.MaxReviewers)
+		}
+		for _, id := range d1.Selected {
+			if id == "author" {
+				t.Errorf("round %d: author selected as reviewer", i)
+			}
+		}
+	}
+}
+
+func TestReplayDecision(t *testing.T) {
+	s := NewPRService(nil, nil, nil, nil, nil, WithSeed(2025)).(*prService)
+	candidates := members("u1", "u2", "u3", "u4", "u5")
+	candidates[0].Seniority = domain.SenioritySenior
+	decision := s.decide(selectionInput{
+		prID:       "pr-1",
+		kind:       domain.DecisionKindCreate,
+		rule:       domain.ReviewerRuleSeniorRequired,
+		candidates: candidates,
+		slots:      2,
+	})
+
+	stored := &domain.AssignmentDecision{
+		Seed:            decision.Seed,
+		Slots:           decision.Slots,
+		Rule:            decision.Rule,
+		Strategy:        decision.Strategy,
+		Candidates:      decision.Candidates,
+		CandidateLevels: decision.CandidateLevels,
+		KeptLevels:      decision.KeptLevels,
+	}
+	if got := ReplayDecision(stored); !reflect.DeepEqual(got, decision.Selected) {
+		t.Errorf("ReplayDecision() = %v, want %v", got, decision.Selected)
+	}
+}
+
+func TestSelectReviewers_ExactChoice(t *testing.T) {
+	got, _ := selectReviewers(42, domain.StrategyRandom, pool("u1", "u2", "u3", "u4", "u5"), nil, 2, domain.ReviewerRuleNone)
+	want := []string{"u3", "u4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("selectReviewers(42) = %v, want %v", got, want)
+	}
+}
+
+func TestSelectReviewers_PairingRules(t *testing.T) {
+	juniorsAndSenior := []candidate{
+		{id: "j1", level: domain.SeniorityJunior},
+		{id: "j2", level: domain.SeniorityJunior},
+		{id: "j3", level: domain.SeniorityJunior},
+		{id: "s1", level: domain.SenioritySenior},
+	}
+	onlyJuniors := []candidate{
+		{id: "j1", level: domain.SeniorityJunior},
+		{id: "j2", level: domain.SeniorityJunior},
+	}
+
+	tests := []struct {
+		name         string
+		candidates   []candidate
+		kept         []domain.Seniority
+		slots        int
+		rule         domain.ReviewerRule
+		wantSenior   bool
+		maxJuniors   int
+		wantViolated bool
+	}{
+		{
+			name:       "Senior required",
+			candidates: juniorsAndSenior,
+			slots:      2,
+			rule:       domain.ReviewerRuleSeniorRequired,
+			wantSenior: true,
+			maxJuniors: 1,
+		},
+		{
+			name:       "No two juniors",
+			candidates: juniorsAndSenior,
+			slots:      2,
+			rule:       domain.ReviewerRuleNoTwoJuniors,
+			maxJuniors: 1,
+		},
+		{
+			name:         "Rule cannot be satisfied",
+			candidates:   onlyJuniors,
+			slots:        2,
+			rule:         domain.ReviewerRuleNoTwoJuniors,
+			maxJuniors:   2,
+			wantViolated: true,
+		},
+		{
+			name:       "Kept junior forces senior on reassign",
+			candidates: juniorsAndSenior,
+			kept:       []domain.Seniority{domain.SeniorityJunior},
+			slots:      1,
+			rule:       domain.ReviewerRuleNoTwoJuniors,
+			wantSenior: true,
+			maxJuniors: 0,
+		},
+	}
+
+	levels := map[string]domain.Seniority{}
+	for _, c := range juniorsAndSenior {
+		levels[c.id] = c.level
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				got, violated := selectReviewers(seed, domain.StrategyRandom, tt.candidates, tt.kept, tt.slots, tt.rule)
+				if violated != tt.wantViolated {
+					t.Fatalf("seed %d: violated = %v, want %v", seed, violated, tt.wantViolated)
+				}
+				juniors, senior := 0, false
+				for _, id := range got {
+					switch levels[id] {
+					case domain.SeniorityJunior:
+						juniors++
+					case domain.SenioritySenior:
+						senior = true
+					}
+				}
+				if tt.wantSenior && !senior {
+					t.Errorf("seed %d: expected a senior among %v", seed, got)
+				}
+				if juniors > tt.maxJuniors {
+					t.Errorf("seed %d: %d juniors selected in %v, want at most %d", seed, juniors, got, tt.maxJuniors)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectReviewers_FairStrategyPenalizesRecentPairs(t *testing.T) {
+	candidates := []candidate{
+		{id: "frequent", level: domain.SeniorityMiddle, penalty: 10},
+		{id: "u1", level: domain.SeniorityMiddle},
+		{id: "u2", level: domain.SeniorityMiddle},
+	}
+
+	const rounds = 1000
+	fair, random := 0, 0
+	for seed := int64(0); seed < rounds; seed++ {
+		got, _ := selectReviewers(seed, domain.StrategyFair, candidates, nil, 1, domain.ReviewerRuleNone)
+		if got[0] == "frequent" {
+			fair++
+		}
+		got, _ = selectReviewers(seed, domain.StrategyRandom, candidates, nil, 1, domain.ReviewerRuleNone)
+		if got[0] == "frequent" {
+			random++
+		}
+	}
+
+	if fair*5 > random {
+		t.Errorf("fair strategy picked penalized reviewer %d times, random %d times", fair, random)
+	}
+}
+
+func TestReplayDecision_Fair(t *testing.T) {
+	s := NewPRService(nil, nil, nil, nil, nil, WithSeed(7), WithFairness(10)).(*prService)
+	decision := s.decide(selectionInput{
+		prID:       "pr-1",
+		kind:       domain.DecisionKindCreate,
+		candidates: members("u1", "u2", "u3", "u4"),
+		penalties:  map[string]int{"u1": 3, "u2": 1},
+		slots:      2,
+	})
+	if decision.Strategy != domain.StrategyFair {
+		t.Fatalf("Strategy = %s, want %s", decision.Strategy, domain.StrategyFair)
+	}
+	if got := ReplayDecision(decision); !reflect.DeepEqual(got, decision.Selected) {
+		t.Errorf("ReplayDecision() = %v, want %v", got, decision.Selected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"avito/internal/domain"
 	"avito/pkg/logger"
 )
 
@@ -13,30 +14,43 @@ type userRepoForStats interface {
 
 type teamRepoForStats interface {
 	Count(ctx context.Context) (int, error)
+	Get(ctx context.Context, teamName string) (*domain.Team, error)
 }
 
 type prRepoForStats interface {
 	Count(ctx context.Context) (int, error)
 }
 
+type decisionRepoForStats interface {
+	PairingMatrix(ctx context.Context, teamID, window int) ([]*domain.PairingCount, error)
+}
+
 type StatsService struct {
-	userRepo userRepoForStats
-	teamRepo teamRepoForStats
-	prRepo   prRepoForStats
-	logger   *logger.Logger
+	userRepo     userRepoForStats
+	teamRepo     teamRepoForStats
+	prRepo       prRepoForStats
+	decisionRepo decisionRepoForStats
+	// pairingWindow — сколько последних решений по каждому автору учитывает
+	// матрица пар; совпадает с окном стратегии fair.
+	pairingWindow int
+	logger        *logger.Logger
 }
 
 func NewStatsService(
 	prRepo prRepoForStats,
 	userRepo userRepoForStats,
 	teamRepo teamRepoForStats,
+	decisionRepo decisionRepoForStats,
+	pairingWindow int,
 	logger *logger.Logger,
 ) *StatsService {
 	return &StatsService{
-		userRepo: userRepo,
-		teamRepo: teamRepo,
-		prRepo:   prRepo,
-		logger:   logger,
+		userRepo:      userRepo,
+		teamRepo:      teamRepo,
+		prRepo:        prRepo,
+		decisionRepo:  decisionRepo,
+		pairingWindow: pairingWindow,
+		logger:        logger,
 	}
 }
 
@@ -47,6 +61,9 @@ type GlobalStats struct {
 }
 
 func (s *StatsService) GetGlobalStats(ctx context.Context) (*GlobalStats, error) {
+	ctx, span := tracer.Start(ctx, "StatsService.GetGlobalStats")
+	defer span.End()
+
 	users, err := s.userRepo.Count(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count users: %w", err)
@@ -70,3 +87,55 @@ func (s *StatsService) GetGlobalStats(ctx context.Context) (*GlobalStats, error)
 
 	return stats, nil
 }
+
+type PairingStat struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Count      int    `json:"count"`
+}
+
+type PairingMatrix struct {
+	TeamName string                    `json:"team_name"`
+	Window   int                       `json:"window"`
+	Pairs    []*PairingStat            `json:"pairs"`
+	Matrix   map[string]map[string]int `json:"matrix"`
+}
+
+func (s *StatsService) GetPairingMatrix(ctx context.Context, teamName string) (*PairingMatrix, error) {
+	ctx, span := tracer.Start(ctx, "StatsService.GetPairingMatrix")
+	defer span.End()
+
+	if teamName == "" {
+		return nil, domain.ErrInvalidInput
+	}
+
+	team, err := s.teamRepo.Get(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	counts, err := s.decisionRepo.PairingMatrix(ctx, team.ID, s.pairingWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pairing matrix: %w", err)
+	}
+
+	result := &PairingMatrix{
+		TeamName: team.Name,
+		Window:   s.pairingWindow,
+		Pairs:    make([]*PairingStat, 0, len(counts)),
+		Matrix:   make(map[string]map[string]int),
+	}
+	for _, c := range counts {
+		result.Pairs = append(result.Pairs, &PairingStat{
+			AuthorID:   c.AuthorID,
+			ReviewerID: c.ReviewerID,
+			Count:      c.Count,
+		})
+		if result.Matrix[c.AuthorID] == nil {
+			result.Matrix[c.AuthorID] = make(map[string]int)
+		}
+		result.Matrix[c.AuthorID][c.ReviewerID] = c.Count
+	}
+
+	return result, nil
+}
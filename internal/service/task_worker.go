@@ -2,38 +2,105 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"avito/internal/domain"
 	"avito/internal/repository"
+	"avito/internal/repository/postgres"
 	"avito/pkg/logger"
 )
 
+// TaskObserver получает итог каждой обработанной задачи.
+type TaskObserver interface {
+	ObserveTask(kind, status string)
+}
+
+type noopTaskObserver struct{}
+
+func (noopTaskObserver) ObserveTask(string, string) {}
+
+// Heartbeat отмечает, что цикл воркера продолжает работать.
+type Heartbeat interface {
+	Beat()
+}
+
+type noopHeartbeat struct{}
+
+func (noopHeartbeat) Beat() {}
+
+type TaskWorkerOption func(*TaskWorker)
+
+func WithTaskObserver(o TaskObserver) TaskWorkerOption {
+	return func(w *TaskWorker) {
+		w.observer = o
+	}
+}
+
+// WithHeartbeat отмечает цикл до и после каждой задачи, например для
+// проверки готовности: промежуток между отметками не превышает таймаута
+// задачи.
+func WithHeartbeat(h Heartbeat) TaskWorkerOption {
+	return func(w *TaskWorker) {
+		w.heartbeat = h
+	}
+}
+
+// WithTaskTimeout ограничивает время выполнения одной задачи.
+func WithTaskTimeout(d time.Duration) TaskWorkerOption {
+	return func(w *TaskWorker) {
+		w.taskTimeout = d
+	}
+}
+
+// defaultTaskTimeout — таймаут задачи, если он не задан через WithTaskTimeout.
+const defaultTaskTimeout = 2 * time.Minute
+
 type TaskWorker struct {
+	db        *postgres.DB
 	taskRepo  repository.TaskRepository
 	userRepo  repository.UserRepository
 	prService PRService
+	observer  TaskObserver
+	heartbeat Heartbeat
 	logger    *logger.Logger
+
+	taskTimeout time.Duration
 }
 
 func NewTaskWorker(
+	db *postgres.DB,
 	taskRepo repository.TaskRepository,
 	userRepo repository.UserRepository,
 	prService PRService,
 	logger *logger.Logger,
+	opts ...TaskWorkerOption,
 ) *TaskWorker {
-	return &TaskWorker{
+	w := &TaskWorker{
+		db:        db,
 		taskRepo:  taskRepo,
 		userRepo:  userRepo,
 		prService: prService,
+		observer:  noopTaskObserver{},
+		heartbeat: noopHeartbeat{},
 		logger:    logger,
+
+		taskTimeout: defaultTaskTimeout,
+	}
+	for _, opt := range opts {
+		opt(w)
 	}
+	return w
 }
 
 func (w *TaskWorker) Run(ctx context.Context) {
 	w.logger.Info("Task worker started")
+	w.heartbeat.Beat()
 	ticker := time.NewTicker(5 * time.Second)
 
 	defer ticker.Stop()
@@ -44,7 +111,11 @@ func (w *TaskWorker) Run(ctx context.Context) {
 			w.logger.Info("Task worker shutting down")
 			return
 		case <-ticker.C:
+			w.heartbeat.Beat()
 			w.processNextTask(ctx)
+			w.heartbeat.Beat()
+			w.processNextFillTask(ctx)
+			w.heartbeat.Beat()
 		}
 	}
 }
@@ -59,19 +130,70 @@ func (w *TaskWorker) processNextTask(ctx context.Context) {
 		return
 	}
 
-	w.logger.Info("Processing task", "task_id", task.ID, "team_id", task.TeamID)
+	ctx, span := tracer.Start(ctx, "TaskWorker.Deactivate", trace.WithAttributes(
+		attribute.Int("task.id", task.ID),
+		attribute.Int("team.id", task.TeamID),
+	))
+	defer span.End()
+	ctx = logger.ContextWith(ctx, "task_id", task.ID, "task_kind", domain.TaskKindDeactivate)
+	log := w.logger.WithContext(ctx)
 
-	err = w.runDeactivation(ctx, task.TeamID)
+	log.Info("Processing task", "team_id", task.TeamID)
+
+	runCtx, cancel := context.WithTimeout(ctx, w.taskTimeout)
+	err = w.runDeactivation(runCtx, task.TeamID)
+	cancel()
 
 	if err != nil {
-		w.logger.Error("Task failed", "task_id", task.ID, "error", err)
+		w.observer.ObserveTask(domain.TaskKindDeactivate, domain.TaskStatusFailed)
+		log.Error("Task failed", "error", err)
 		if statusErr := w.taskRepo.SetTaskStatus(ctx, task.ID, domain.TaskStatusFailed, err.Error()); statusErr != nil {
-			w.logger.Error("Failed to set task status", "task_id", task.ID, "error", statusErr)
+			log.Error("Failed to set task status", "error", statusErr)
 		}
 	} else {
-		w.logger.Info("Task completed", "task_id", task.ID)
+		w.observer.ObserveTask(domain.TaskKindDeactivate, domain.TaskStatusCompleted)
+		log.Info("Task completed")
 		if statusErr := w.taskRepo.SetTaskStatus(ctx, task.ID, domain.TaskStatusCompleted, ""); statusErr != nil {
-			w.logger.Error("Failed to set task status", "task_id", task.ID, "error", statusErr)
+			log.Error("Failed to set task status", "error", statusErr)
+		}
+	}
+}
+
+func (w *TaskWorker) processNextFillTask(ctx context.Context) {
+	task, err := w.taskRepo.GetAndLockPendingFillTask(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return
+		}
+		w.logger.Error("Failed to get fill task", "error", err)
+		return
+	}
+
+	ctx, span := tracer.Start(ctx, "TaskWorker.Fill", trace.WithAttributes(
+		attribute.Int("task.id", task.ID),
+		attribute.Int("team.id", task.TeamID),
+	))
+	defer span.End()
+	ctx = logger.ContextWith(ctx, "task_id", task.ID, "task_kind", domain.TaskKindFill)
+	log := w.logger.WithContext(ctx)
+
+	log.Info("Processing fill task", "team_id", task.TeamID, "trigger", task.Trigger)
+
+	runCtx, cancel := context.WithTimeout(ctx, w.taskTimeout)
+	filled, err := w.prService.FillMissingReviewers(runCtx, task.TeamID, task.ID)
+	cancel()
+
+	if err != nil {
+		w.observer.ObserveTask(domain.TaskKindFill, domain.TaskStatusFailed)
+		log.Error("Fill task failed", "filled", filled, "error", err)
+		if statusErr := w.taskRepo.SetFillTaskStatus(ctx, task.ID, domain.TaskStatusFailed, filled, err.Error()); statusErr != nil {
+			log.Error("Failed to set fill task status", "error", statusErr)
+		}
+	} else {
+		w.observer.ObserveTask(domain.TaskKindFill, domain.TaskStatusCompleted)
+		log.Info("Fill task completed", "filled", filled)
+		if statusErr := w.taskRepo.SetFillTaskStatus(ctx, task.ID, domain.TaskStatusCompleted, filled, ""); statusErr != nil {
+			log.Error("Failed to set fill task status", "error", statusErr)
 		}
 	}
 }
@@ -83,24 +205,38 @@ func (w *TaskWorker) runDeactivation(ctx context.Context, teamID int) error {
 	}
 
 	if len(users) == 0 {
-		w.logger.Warn("No users found in team", "team_id", teamID)
+		w.logger.WithContext(ctx).Warn("No users found in team", "team_id", teamID)
 		return nil
 	}
 
-	w.logger.Info(fmt.Sprintf("Found %d users to deactivate", len(users)), "team_id", teamID)
+	w.logger.WithContext(ctx).Info(fmt.Sprintf("Found %d users to deactivate", len(users)), "team_id", teamID)
 
 	for _, user := range users {
-		if err := w.userRepo.SetActive(ctx, user.UserID, false); err != nil {
-			w.logger.Error("Failed to deactivate user", "user_id", user.UserID, "error", err)
+		if !user.IsActive {
+			continue
+		}
+		if err := w.deactivateUser(ctx, user); err != nil {
+			w.logger.WithContext(ctx).Error("Failed to deactivate user", "user_id", user.UserID, "error", err)
 			continue
 		}
 		w.triggerReassignment(ctx, user.UserID)
 	}
 
-	w.logger.Info("Batch deactivation finished for team", "team_id", teamID)
+	w.logger.WithContext(ctx).Info("Batch deactivation finished for team", "team_id", teamID)
 	return nil
 }
 
-func (w *TaskWorker) triggerReassignment(_ context.Context, userID string) {
-	w.logger.Info("Triggering reassignment", "user_id", userID)
+func (w *TaskWorker) deactivateUser(ctx context.Context, user *domain.User) error {
+	return w.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txUserRepo := postgres.NewUserRepository(tx)
+		if err := txUserRepo.SetActive(ctx, user.UserID, false); err != nil {
+			return err
+		}
+		payload := domain.UserEventPayload{UserID: user.UserID, TeamID: user.TeamID}
+		return recordEvent(ctx, postgres.NewEventRepository(tx), domain.EventUserDeactivated, user.UserID, payload)
+	})
+}
+
+func (w *TaskWorker) triggerReassignment(ctx context.Context, userID string) {
+	w.logger.WithContext(ctx).Info("Triggering reassignment", "user_id", userID)
 }
@@ -40,6 +40,9 @@ func NewTeamService(
 }
 
 func (s *teamService) CreateTeamWithMembers(ctx context.Context, team *domain.Team) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.CreateTeamWithMembers")
+	defer span.End()
+
 	if err := team.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid team: %w", err)
 	}
@@ -49,6 +52,7 @@ func (s *teamService) CreateTeamWithMembers(ctx context.Context, team *domain.Te
 	err := s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
 		txTeamRepo := postgres.NewTeamRepository(tx)
 		txUserRepo := postgres.NewUserRepository(tx)
+		txTaskRepo := postgres.NewTaskRepository(tx)
 
 		exists, err := txTeamRepo.Exists(ctx, team.Name)
 		if err != nil {
@@ -65,10 +69,11 @@ func (s *teamService) CreateTeamWithMembers(ctx context.Context, team *domain.Te
 
 		for _, member := range team.Members {
 			user := &domain.User{
-				UserID:   member.UserID,
-				Username: member.Username,
-				TeamID:   teamID,
-				IsActive: member.IsActive,
+				UserID:    member.UserID,
+				Username:  member.Username,
+				TeamID:    teamID,
+				IsActive:  member.IsActive,
+				Seniority: member.Seniority,
 			}
 			if err = txUserRepo.CreateOrUpdate(ctx, user); err != nil {
 				return fmt.Errorf("failed to create/update user %s: %w", member.UserID, err)
@@ -92,7 +97,13 @@ func (s *teamService) CreateTeamWithMembers(ctx context.Context, team *domain.Te
 		}
 		createdTeam.Members = teamMembers
 
-		return nil
+		trigger := fmt.Sprintf("%s:%s", domain.FillTriggerTeamCreated, createdTeam.Name)
+		if err = txTaskRepo.CreateFillTask(ctx, createdTeam.ID, trigger); err != nil {
+			return fmt.Errorf("failed to schedule reviewer fill: %w", err)
+		}
+
+		payload := domain.NewTeamEventPayload(createdTeam)
+		return recordEvent(ctx, postgres.NewEventRepository(tx), domain.EventTeamCreated, createdTeam.Name, payload)
 	})
 	if err != nil {
 		return nil, err
@@ -101,7 +112,66 @@ func (s *teamService) CreateTeamWithMembers(ctx context.Context, team *domain.Te
 	return createdTeam, nil
 }
 
+// AddMembers добавляет участников в существующую команду; уже существующие
+// пользователи переводятся в неё из прежней. Новые участники могут взять
+// ревью, поэтому для команды ставится задача добора ревьюеров.
+func (s *teamService) AddMembers(ctx context.Context, teamName string, members []*domain.TeamMember) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.AddMembers")
+	defer span.End()
+
+	if teamName == "" || len(members) == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	for _, member := range members {
+		if err := member.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var updated *domain.Team
+	err := s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txTeamRepo := postgres.NewTeamRepository(tx)
+		txUserRepo := postgres.NewUserRepository(tx)
+
+		team, err := txTeamRepo.Get(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("failed to get team: %w", err)
+		}
+
+		for _, member := range members {
+			user := &domain.User{
+				UserID:    member.UserID,
+				Username:  member.Username,
+				TeamID:    team.ID,
+				IsActive:  member.IsActive,
+				Seniority: member.Seniority,
+			}
+			if err := txUserRepo.CreateOrUpdate(ctx, user); err != nil {
+				return fmt.Errorf("failed to create/update user %s: %w", member.UserID, err)
+			}
+		}
+
+		trigger := fmt.Sprintf("%s:%s", domain.FillTriggerMembersAdded, team.Name)
+		if err := postgres.NewTaskRepository(tx).CreateFillTask(ctx, team.ID, trigger); err != nil {
+			return fmt.Errorf("failed to schedule reviewer fill: %w", err)
+		}
+
+		updated, err = txTeamRepo.Get(ctx, teamName)
+		if err != nil {
+			return fmt.Errorf("failed to get updated team: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
 func (s *teamService) GetTeamByName(ctx context.Context, teamName string) (*domain.Team, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.GetTeamByName")
+	defer span.End()
+
 	if teamName == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -115,6 +185,9 @@ func (s *teamService) GetTeamByName(ctx context.Context, teamName string) (*doma
 }
 
 func (s *teamService) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	ctx, span := tracer.Start(ctx, "TeamService.TeamExists")
+	defer span.End()
+
 	if teamName == "" {
 		return false, domain.ErrInvalidInput
 	}
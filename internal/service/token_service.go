@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"avito/internal/domain"
+)
+
+const defaultTokenAuditLimit = 100
+
+type tokenRepoForTokenService interface {
+	Create(ctx context.Context, t *domain.APIToken) error
+	GetByHash(ctx context.Context, hash string) (*domain.APIToken, error)
+	List(ctx context.Context) ([]*domain.APIToken, error)
+	Revoke(ctx context.Context, id int64) error
+	AddAudit(ctx context.Context, e *domain.TokenAuditEntry) error
+	ListAudit(ctx context.Context, tokenID int64, limit int) ([]*domain.TokenAuditEntry, error)
+}
+
+type userRepoForTokenService interface {
+	Get(ctx context.Context, userID string) (*domain.User, error)
+}
+
+type tokenService struct {
+	tokenRepo tokenRepoForTokenService
+	userRepo  userRepoForTokenService
+}
+
+func NewTokenService(tokenRepo tokenRepoForTokenService, userRepo userRepoForTokenService) *tokenService {
+	return &tokenService{tokenRepo: tokenRepo, userRepo: userRepo}
+}
+
+// CreateToken выпускает токен и возвращает его открытое значение — больше
+// его получить нельзя, в БД остаётся только хеш. Непустой userID привязывает
+// токен к пользователю, и запросы с ним проверяются по роли этого пользователя.
+func (s *tokenService) CreateToken(ctx context.Context, name string, scopes []string, userID string) (*domain.APIToken, string, error) {
+	plaintext, hash, err := domain.GenerateAPIToken()
+	if err != nil {
+		return nil, "", err
+	}
+	token := &domain.APIToken{
+		Name:   strings.TrimSpace(name),
+		Prefix: plaintext[:len(domain.APITokenPrefix)+4],
+		Hash:   hash,
+		Scopes: scopes,
+		UserID: userID,
+	}
+	if err := token.Validate(); err != nil {
+		return nil, "", err
+	}
+	if userID != "" {
+		if _, err := s.userRepo.Get(ctx, userID); err != nil {
+			return nil, "", fmt.Errorf("failed to get token user %s: %w", userID, err)
+		}
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+	return token, plaintext, nil
+}
+
+func (s *tokenService) ListTokens(ctx context.Context) ([]*domain.APIToken, error) {
+	return s.tokenRepo.List(ctx)
+}
+
+func (s *tokenService) RevokeToken(ctx context.Context, id int64) error {
+	return s.tokenRepo.Revoke(ctx, id)
+}
+
+// Authenticate проверяет открытое значение токена. Неизвестные и отозванные
+// токены дают domain.ErrUnauthorized.
+func (s *tokenService) Authenticate(ctx context.Context, plaintext string) (*domain.Principal, error) {
+	if !strings.HasPrefix(plaintext, domain.APITokenPrefix) {
+		return nil, domain.ErrUnauthorized
+	}
+	token, err := s.tokenRepo.GetByHash(ctx, domain.HashAPIToken(plaintext))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up token: %w", err)
+	}
+	if token.IsRevoked() {
+		return nil, domain.ErrUnauthorized
+	}
+	principal := &domain.Principal{
+		TokenID:   token.ID,
+		TokenName: token.Name,
+		Scopes:    token.Scopes,
+	}
+	if token.UserID != "" {
+		// Роль и команду читаем на каждый запрос, чтобы смена роли сразу
+		// действовала на уже выпущенные токены.
+		user, err := s.userRepo.Get(ctx, token.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token user %s: %w", token.UserID, err)
+		}
+		principal.UserID = user.UserID
+		principal.TeamID = user.TeamID
+		principal.Role = user.Role.OrDefault()
+	}
+	return principal, nil
+}
+
+func (s *tokenService) RecordMutation(ctx context.Context, entry *domain.TokenAuditEntry) error {
+	return s.tokenRepo.AddAudit(ctx, entry)
+}
+
+func (s *tokenService) ListAudit(ctx context.Context, tokenID int64) ([]*domain.TokenAuditEntry, error) {
+	return s.tokenRepo.ListAudit(ctx, tokenID, defaultTokenAuditLimit)
+}
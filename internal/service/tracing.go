@@ -0,0 +1,6 @@
+package service
+
+import "go.opentelemetry.io/otel"
+
+// tracer открывает span на методы сервисов; имена span — "Сервис.Метод".
+var tracer = otel.Tracer("avito/internal/service")
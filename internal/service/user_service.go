@@ -2,15 +2,23 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"avito/internal/domain"
+	"avito/internal/repository/postgres"
 	"avito/pkg/logger"
 )
 
+// reassignAttempts — сколько раз фоновое переназначение повторяется, если PR
+// или команду изменили параллельно (например, деактивировали ещё одного участника).
+const reassignAttempts = 3
+
 type userRepoForUserService interface {
 	Get(ctx context.Context, userID string) (*domain.User, error)
 	SetActive(ctx context.Context, userID string, isActive bool) error
+	SetRole(ctx context.Context, userID string, role domain.Role) error
 	Exists(ctx context.Context, userID string) (bool, error)
 	GetByTeamID(ctx context.Context, teamID int) ([]*domain.User, error)
 	GetActiveByTeamID(ctx context.Context, teamID int) ([]*domain.User, error)
@@ -33,6 +41,7 @@ type prServiceForUserService interface {
 }
 
 type userService struct {
+	db        *postgres.DB
 	userRepo  userRepoForUserService
 	prRepo    prRepoForUserService
 	prService prServiceForUserService
@@ -42,6 +51,7 @@ type userService struct {
 }
 
 func NewUserService(
+	db *postgres.DB,
 	userRepo userRepoForUserService,
 	prRepo prRepoForUserService,
 	prService prServiceForUserService,
@@ -50,6 +60,7 @@ func NewUserService(
 	logger *logger.Logger,
 ) *userService {
 	return &userService{
+		db:        db,
 		userRepo:  userRepo,
 		prRepo:    prRepo,
 		prService: prService,
@@ -60,6 +71,9 @@ func NewUserService(
 }
 
 func (s *userService) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetIsActive")
+	defer span.End()
+
 	if userID == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -70,47 +84,100 @@ func (s *userService) SetIsActive(ctx context.Context, userID string, isActive b
 	if user.IsActive == isActive {
 		return user, nil
 	}
-	if err := s.userRepo.SetActive(ctx, userID, isActive); err != nil {
-		return nil, fmt.Errorf("failed to set user active status: %w", err)
+	err = s.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		txUserRepo := postgres.NewUserRepository(tx)
+		if err := txUserRepo.SetActive(ctx, userID, isActive); err != nil {
+			return fmt.Errorf("failed to set user active status: %w", err)
+		}
+		eventType := domain.EventUserDeactivated
+		if isActive {
+			eventType = domain.EventUserActivated
+		}
+		// Задача на добор создаётся в той же транзакции: иначе сбой после
+		// коммита оставил бы открытые PR без ревьюеров и без задачи.
+		if isActive {
+			trigger := fmt.Sprintf("%s:%s", domain.FillTriggerUserActivated, userID)
+			if err := postgres.NewTaskRepository(tx).CreateFillTask(ctx, user.TeamID, trigger); err != nil {
+				return fmt.Errorf("failed to schedule reviewer fill: %w", err)
+			}
+		}
+		payload := domain.UserEventPayload{UserID: userID, TeamID: user.TeamID}
+		return recordEvent(ctx, postgres.NewEventRepository(tx), eventType, userID, payload)
+	})
+	if err != nil {
+		return nil, err
 	}
 	user.IsActive = isActive
 	if !isActive {
-		go s.triggerReassignment(userID)
+		// Фоновое переназначение переживает запрос, но сохраняет его
+		// request_id и трейс для логов.
+		go s.triggerReassignment(context.WithoutCancel(ctx), userID)
+	} else {
+		s.logger.WithContext(ctx).Info("Задача на добор ревьюеров создана", "userID", userID, "teamID", user.TeamID)
+	}
+	return user, nil
+}
+
+// SetRole назначает пользователю роль. Права проверяет authz: менять роли
+// может только администратор.
+func (s *userService) SetRole(ctx context.Context, userID string, role domain.Role) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.SetRole")
+	defer span.End()
+
+	if userID == "" || !role.IsValid() {
+		return nil, domain.ErrInvalidInput
 	}
+	if err := s.userRepo.SetRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	s.logger.WithContext(ctx).Info("User role changed", "user_id", userID, "role", role)
 	return user, nil
 }
 
-func (s *userService) triggerReassignment(userID string) {
-	ctx := context.Background()
-	s.logger.Info("Запуск фонового переназначения для деактивированного пользователя", "userID", userID)
+func (s *userService) triggerReassignment(ctx context.Context, userID string) {
+	s.logger.WithContext(ctx).Info("Запуск фонового переназначения для деактивированного пользователя", "userID", userID)
 
 	openPRs, err := s.prRepo.GetByReviewer(ctx, userID, domain.PRStatusIDOpen)
 	if err != nil {
-		s.logger.Error("Не удалось получить PR для переназначения", "userID", userID, "error", err.Error())
+		s.logger.WithContext(ctx).Error("Не удалось получить PR для переназначения", "userID", userID, "error", err.Error())
 		return
 	}
 	if len(openPRs) == 0 {
-		s.logger.Info("У пользователя нет открытых PR для переназначения", "userID", userID)
+		s.logger.WithContext(ctx).Info("У пользователя нет открытых PR для переназначения", "userID", userID)
 		return
 	}
-	s.logger.Info(fmt.Sprintf("Найдено %d PR для переназначения", len(openPRs)), "userID", userID)
+	s.logger.WithContext(ctx).Info(fmt.Sprintf("Найдено %d PR для переназначения", len(openPRs)), "userID", userID)
 
 	for _, pr := range openPRs {
-		_, _, err := s.prService.ReassignReviewer(ctx, pr.PullRequestID, userID)
+		var err error
+		for attempt := 1; attempt <= reassignAttempts; attempt++ {
+			_, _, err = s.prService.ReassignReviewer(ctx, pr.PullRequestID, userID)
+			if !errors.Is(err, domain.ErrConcurrentModification) {
+				break
+			}
+		}
 		if err != nil {
-			s.logger.Error("Не удалось переназначить PR",
+			s.logger.WithContext(ctx).Error("Не удалось переназначить PR",
 				"prID", pr.PullRequestID,
 				"userID", userID,
 				"error", err.Error(),
 			)
 		} else {
-			s.logger.Info("PR успешно переназначен", "prID", pr.PullRequestID)
+			s.logger.WithContext(ctx).Info("PR успешно переназначен", "prID", pr.PullRequestID)
 		}
 	}
-	s.logger.Info("Фоновое переназначение завершено", "userID", userID)
+	s.logger.WithContext(ctx).Info("Фоновое переназначение завершено", "userID", userID)
 }
 
 func (s *userService) GetUser(ctx context.Context, userID string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUser")
+	defer span.End()
+
 	if userID == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -122,6 +189,9 @@ func (s *userService) GetUser(ctx context.Context, userID string) (*domain.User,
 }
 
 func (s *userService) GetPRsByReviewer(ctx context.Context, userID string) ([]*domain.PullRequestShort, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetPRsByReviewer")
+	defer span.End()
+
 	if userID == "" {
 		return nil, domain.ErrInvalidInput
 	}
@@ -139,6 +209,9 @@ func (s *userService) GetPRsByReviewer(ctx context.Context, userID string) ([]*d
 }
 
 func (s *userService) GetUsersByTeam(ctx context.Context, teamID int) ([]*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUsersByTeam")
+	defer span.End()
+
 	users, err := s.userRepo.GetByTeamID(ctx, teamID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users by team: %w", err)
@@ -147,6 +220,9 @@ func (s *userService) GetUsersByTeam(ctx context.Context, teamID int) ([]*domain
 }
 
 func (s *userService) GetActiveUsersByTeam(ctx context.Context, teamID int) ([]*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetActiveUsersByTeam")
+	defer span.End()
+
 	users, err := s.userRepo.GetActiveByTeamID(ctx, teamID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active users by team: %w", err)
@@ -155,6 +231,9 @@ func (s *userService) GetActiveUsersByTeam(ctx context.Context, teamID int) ([]*
 }
 
 func (s *userService) ScheduleBatchDeactivate(ctx context.Context, teamID int) error {
+	ctx, span := tracer.Start(ctx, "UserService.ScheduleBatchDeactivate")
+	defer span.End()
+
 	exists, err := s.teamRepo.ExistsByID(ctx, teamID)
 	if err != nil {
 		return fmt.Errorf("failed to check team existence: %w", err)
@@ -167,6 +246,6 @@ func (s *userService) ScheduleBatchDeactivate(ctx context.Context, teamID int) e
 		return fmt.Errorf("failed to schedule task: %w", err)
 	}
 
-	s.logger.Info("Задача на массовую деактивацию успешно создана", "team_id", teamID)
+	s.logger.WithContext(ctx).Info("Задача на массовую деактивацию успешно создана", "team_id", teamID)
 	return nil
 }
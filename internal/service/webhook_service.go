@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+const defaultDeliveryLogLimit = 50
+
+type webhookRepoForWebhookService interface {
+	CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	SubscriptionExists(ctx context.Context, subscriptionID int) (bool, error)
+	ListDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*domain.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepo webhookRepoForWebhookService
+}
+
+func NewWebhookService(webhookRepo webhookRepoForWebhookService) *webhookService {
+	return &webhookService{webhookRepo: webhookRepo}
+}
+
+func (s *webhookService) CreateSubscription(
+	ctx context.Context,
+	sub *domain.WebhookSubscription,
+) (*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	sub.IsActive = true
+	if err := s.webhookRepo.CreateSubscription(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListSubscriptions")
+	defer span.End()
+
+	subs, err := s.webhookRepo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	if subs == nil {
+		subs = []*domain.WebhookSubscription{}
+	}
+	return subs, nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID, limit int) ([]*domain.WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "WebhookService.ListDeliveries")
+	defer span.End()
+
+	if subscriptionID <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	if limit <= 0 {
+		limit = defaultDeliveryLogLimit
+	}
+	exists, err := s.webhookRepo.SubscriptionExists(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check subscription existence: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+	"avito/internal/webhook"
+	"avito/pkg/logger"
+)
+
+type webhookSender interface {
+	Send(ctx context.Context, url, secret string, deliveryID int64, e *domain.Event) (int, error)
+}
+
+type WebhookWorkerConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	Timeout      time.Duration
+	Backoff      webhook.Backoff
+}
+
+// WebhookWorker доставляет подписчикам записи webhook_deliveries, созданные
+// outbox.WebhookSink, с повторами по backoff.
+type WebhookWorker struct {
+	webhookRepo repository.WebhookRepository
+	sender      webhookSender
+	cfg         WebhookWorkerConfig
+	logger      *logger.Logger
+}
+
+func NewWebhookWorker(
+	webhookRepo repository.WebhookRepository,
+	sender webhookSender,
+	cfg WebhookWorkerConfig,
+	logger *logger.Logger,
+) *WebhookWorker {
+	return &WebhookWorker{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+func (w *WebhookWorker) Run(ctx context.Context) {
+	w.logger.Info("Webhook worker started")
+	ticker := time.NewTicker(w.cfg.PollInterval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Webhook worker shutting down")
+			return
+		case <-ticker.C:
+			w.deliverDue(ctx)
+		}
+	}
+}
+
+func (w *WebhookWorker) deliverDue(ctx context.Context) {
+	for i := 0; i < w.cfg.BatchSize && ctx.Err() == nil; i++ {
+		delivered, err := w.deliverNext(ctx)
+		if err != nil {
+			w.logger.Error("Failed to deliver webhook", "error", err)
+			return
+		}
+		if !delivered {
+			return
+		}
+	}
+}
+
+func (w *WebhookWorker) deliverNext(ctx context.Context) (bool, error) {
+	d, err := w.webhookRepo.ClaimDueDelivery(ctx, 2*w.cfg.Timeout)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	code, sendErr := w.sender.Send(ctx, d.Subscription.URL, d.Subscription.Secret, d.ID, d.Event)
+	w.applyResult(d, code, sendErr, time.Now())
+
+	if sendErr != nil {
+		w.logger.Warn("Webhook delivery attempt failed",
+			"delivery_id", d.ID,
+			"subscription_id", d.SubscriptionID,
+			"event", d.EventType,
+			"attempt", d.Attempts,
+			"status", d.Status,
+			"error", sendErr,
+		)
+	} else {
+		w.logger.Info("Webhook delivered",
+			"delivery_id", d.ID,
+			"subscription_id", d.SubscriptionID,
+			"event", d.EventType,
+			"attempt", d.Attempts,
+		)
+	}
+
+	if err := w.webhookRepo.RecordAttempt(ctx, d); err != nil {
+		return true, fmt.Errorf("failed to record delivery %d: %w", d.ID, err)
+	}
+	return true, nil
+}
+
+// applyResult переводит доставку в delivered, откладывает следующую попытку
+// по backoff или, после MaxAttempts попыток, помечает её failed.
+func (w *WebhookWorker) applyResult(d *domain.WebhookDelivery, code int, sendErr error, now time.Time) {
+	d.ResponseCode = sql.NullInt32{Int32: int32(code), Valid: code != 0} //nolint:gosec
+	if sendErr == nil {
+		d.Status = domain.DeliveryStatusDelivered
+		d.ErrorMessage = sql.NullString{}
+		return
+	}
+
+	d.ErrorMessage = sql.NullString{String: sendErr.Error(), Valid: true}
+	if d.Attempts >= w.cfg.MaxAttempts {
+		d.Status = domain.DeliveryStatusFailed
+		return
+	}
+	d.Status = domain.DeliveryStatusPending
+	d.NextAttemptAt = now.Add(w.cfg.Backoff.Delay(d.Attempts))
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+	"avito/internal/webhook"
+	"avito/pkg/logger"
+)
+
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+	due      []*domain.WebhookDelivery
+	recorded []domain.WebhookDelivery
+}
+
+func (f *fakeWebhookRepo) ClaimDueDelivery(_ context.Context, _ time.Duration) (*domain.WebhookDelivery, error) {
+	if len(f.due) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	d := f.due[0]
+	f.due = f.due[1:]
+	d.Attempts++
+	return d, nil
+}
+
+func (f *fakeWebhookRepo) RecordAttempt(_ context.Context, d *domain.WebhookDelivery) error {
+	f.recorded = append(f.recorded, *d)
+	if d.Status == domain.DeliveryStatusPending {
+		f.due = append(f.due, d)
+	}
+	return nil
+}
+
+func newTestWebhookWorker(repo repository.WebhookRepository, maxAttempts int) *WebhookWorker {
+	return NewWebhookWorker(repo, webhook.NewSender(time.Second), WebhookWorkerConfig{
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  maxAttempts,
+		Timeout:      time.Second,
+		Backoff:      webhook.Backoff{Base: time.Second, Max: time.Minute},
+	}, logger.NewWithWriter(io.Discard, "error", "json"))
+}
+
+func pendingDelivery(url string) *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             1,
+		SubscriptionID: 1,
+		EventID:        7,
+		EventType:      domain.EventPRMerged,
+		Status:         domain.DeliveryStatusPending,
+		Subscription:   &domain.WebhookSubscription{ID: 1, URL: url, Secret: "secret"},
+		Event: &domain.Event{
+			ID:          7,
+			Type:        domain.EventPRMerged,
+			AggregateID: "pr-1",
+			Payload:     []byte(`{"pull_request_id":"pr-1"}`),
+		},
+	}
+}
+
+func TestWebhookWorker_RetriesUntilDelivered(t *testing.T) {
+	var calls atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !webhook.Verify([]byte("secret"), body, r.Header.Get(webhook.SignatureHeader)) {
+			t.Error("receiver got invalid signature")
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	repo := &fakeWebhookRepo{due: []*domain.WebhookDelivery{pendingDelivery(receiver.URL)}}
+	w := newTestWebhookWorker(repo, 5)
+
+	w.deliverDue(context.Background())
+
+	if len(repo.recorded) != 3 {
+		t.Fatalf("recorded %d attempts, want 3", len(repo.recorded))
+	}
+	for i, attempt := range repo.recorded[:2] {
+		if attempt.Status != domain.DeliveryStatusPending || attempt.ResponseCode.Int32 != http.StatusBadGateway {
+			t.Errorf("attempt %d = %s/%d, want pending/502", i+1, attempt.Status, attempt.ResponseCode.Int32)
+		}
+	}
+	last := repo.recorded[2]
+	if last.Status != domain.DeliveryStatusDelivered || last.Attempts != 3 || last.ErrorMessage.Valid {
+		t.Errorf("last attempt = %+v, want delivered on attempt 3", last)
+	}
+}
+
+func TestWebhookWorker_GivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := &fakeWebhookRepo{due: []*domain.WebhookDelivery{pendingDelivery(receiver.URL)}}
+	w := newTestWebhookWorker(repo, 2)
+
+	w.deliverDue(context.Background())
+
+	if len(repo.recorded) != 2 {
+		t.Fatalf("recorded %d attempts, want 2", len(repo.recorded))
+	}
+	if last := repo.recorded[1]; last.Status != domain.DeliveryStatusFailed {
+		t.Errorf("last status = %s, want failed", last.Status)
+	}
+}
+
+func TestWebhookWorker_ApplyResultBackoff(t *testing.T) {
+	w := newTestWebhookWorker(nil, 5)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	d := &domain.WebhookDelivery{Attempts: 3}
+	w.applyResult(d, 0, io.ErrUnexpectedEOF, now)
+
+	if d.Status != domain.DeliveryStatusPending {
+		t.Fatalf("Status = %s, want pending", d.Status)
+	}
+	if want := now.Add(4 * time.Second); !d.NextAttemptAt.Equal(want) {
+		t.Errorf("NextAttemptAt = %v, want %v", d.NextAttemptAt, want)
+	}
+	if d.ResponseCode.Valid {
+		t.Error("ResponseCode should be NULL when no response was received")
+	}
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	var handlerTraceID string
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(Middleware)
+	r.Get("/team/{name}", func(w http.ResponseWriter, r *http.Request) {
+		handlerTraceID = trace.SpanContextFromContext(r.Context()).TraceID().String()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	const parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/team/backend", nil)
+	req.Header.Set("traceparent", "00-"+parentTraceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name() != "GET /team/{name}" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span kind = %v", span.SpanKind())
+	}
+	if got := span.SpanContext().TraceID().String(); got != parentTraceID {
+		t.Errorf("trace id = %s, want %s from traceparent", got, parentTraceID)
+	}
+	if handlerTraceID != parentTraceID {
+		t.Errorf("handler context trace id = %s", handlerTraceID)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error for 5xx", span.Status().Code)
+	}
+
+	attrs := map[string]bool{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = true
+		if kv.Key == semconv.HTTPRouteKey && kv.Value.AsString() != "/team/{name}" {
+			t.Errorf("http.route = %q", kv.Value.AsString())
+		}
+	}
+	for _, key := range []string{"http.route", "http.response.status_code", "http.request_id"} {
+		if !attrs[key] {
+			t.Errorf("attribute %s is missing", key)
+		}
+	}
+}
+
+func TestMiddlewareWithoutParent(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Parent().IsValid() {
+		t.Error("span without traceparent should be a root span")
+	}
+	if spans[0].Status().Code == codes.Error {
+		t.Error("2xx response should not mark span as error")
+	}
+	if spans[1].Name() != http.MethodGet {
+		t.Errorf("unmatched route span name = %q, want bare method", spans[1].Name())
+	}
+}
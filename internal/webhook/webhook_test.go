@@ -0,0 +1,100 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/webhook"
+)
+
+func testEvent(t *testing.T) *domain.Event {
+	t.Helper()
+	e, err := domain.NewEvent(domain.EventReviewerAssigned, "pr-1", domain.ReviewerEventPayload{
+		PullRequestID: "pr-1",
+		ReviewerID:    "u2",
+		Source:        domain.DecisionKindCreate,
+	})
+	if err != nil {
+		t.Fatalf("NewEvent() error = %v", err)
+	}
+	e.ID = 17
+	e.CreatedAt = time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC)
+	return e
+}
+
+func TestSender_Send(t *testing.T) {
+	secret := "subscriber-secret"
+	var (
+		gotHeaders http.Header
+		gotBody    []byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeaders = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	code, err := webhook.NewSender(time.Second).Send(context.Background(), receiver.URL, secret, 5, testEvent(t))
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if code != http.StatusNoContent {
+		t.Errorf("Send() code = %d, want %d", code, http.StatusNoContent)
+	}
+
+	if !webhook.Verify([]byte(secret), gotBody, gotHeaders.Get(webhook.SignatureHeader)) {
+		t.Error("receiver could not verify signature")
+	}
+	if got := gotHeaders.Get(webhook.EventHeader); got != domain.EventReviewerAssigned {
+		t.Errorf("%s = %q, want %q", webhook.EventHeader, got, domain.EventReviewerAssigned)
+	}
+	if got := gotHeaders.Get(webhook.DeliveryHeader); got != "5" {
+		t.Errorf("%s = %q, want 5", webhook.DeliveryHeader, got)
+	}
+
+	var msg struct {
+		ID   int64                       `json:"id"`
+		Type string                      `json:"type"`
+		Data domain.ReviewerEventPayload `json:"data"`
+	}
+	if err := json.Unmarshal(gotBody, &msg); err != nil {
+		t.Fatalf("invalid message body: %v", err)
+	}
+	if msg.ID != 17 || msg.Type != domain.EventReviewerAssigned || msg.Data.ReviewerID != "u2" {
+		t.Errorf("unexpected message: %+v", msg)
+	}
+}
+
+func TestSender_SendErrorStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	code, err := webhook.NewSender(time.Second).Send(context.Background(), receiver.URL, "s", 1, testEvent(t))
+	var statusErr *webhook.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Send() error = %v, want StatusError 503", err)
+	}
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Send() code = %d, want 503", code)
+	}
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	b := webhook.Backoff{Base: time.Second, Max: 10 * time.Second}
+	want := []time.Duration{time.Second, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for attempt, w := range want {
+		if got := b.Delay(attempt); got != w {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+}
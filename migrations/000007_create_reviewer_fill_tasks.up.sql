@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS reviewer_fill_tasks (
+    id SERIAL PRIMARY KEY,
+    team_id INT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    trigger VARCHAR(255) NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+
+    filled_count INT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_reviewer_fill_tasks_status
+ON reviewer_fill_tasks(status)
+WHERE status = 'pending';
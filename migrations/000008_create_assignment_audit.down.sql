@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_assignment_audit_pull_request_id;
+DROP TABLE IF EXISTS assignment_audit;
@@ -0,0 +1,13 @@
+CREATE TABLE assignment_decisions (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    seed BIGINT NOT NULL,
+    slots INT NOT NULL,
+    candidates TEXT[] NOT NULL DEFAULT '{}',
+    selected TEXT[] NOT NULL DEFAULT '{}',
+    replaced_user_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_assignment_decisions_pull_request_id ON assignment_decisions(pull_request_id);
@@ -0,0 +1,3 @@
+ALTER TABLE assignment_decisions
+    DROP COLUMN IF EXISTS exclusions,
+    DROP COLUMN IF EXISTS strategy;
@@ -0,0 +1,3 @@
+ALTER TABLE assignment_decisions
+    ADD COLUMN IF NOT EXISTS strategy VARCHAR(50) NOT NULL DEFAULT 'random',
+    ADD COLUMN IF NOT EXISTS exclusions JSONB NOT NULL DEFAULT '[]';
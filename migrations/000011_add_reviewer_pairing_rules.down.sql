@@ -0,0 +1,11 @@
+ALTER TABLE assignment_decisions
+    DROP COLUMN IF EXISTS rule_violated,
+    DROP COLUMN IF EXISTS kept_levels,
+    DROP COLUMN IF EXISTS candidate_levels,
+    DROP COLUMN IF EXISTS reviewer_rule;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS reviewer_rule;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS seniority;
@@ -0,0 +1,11 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS seniority VARCHAR(20) NOT NULL DEFAULT 'middle';
+
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS reviewer_rule VARCHAR(50) NOT NULL DEFAULT 'none';
+
+ALTER TABLE assignment_decisions
+    ADD COLUMN IF NOT EXISTS reviewer_rule VARCHAR(50) NOT NULL DEFAULT 'none',
+    ADD COLUMN IF NOT EXISTS candidate_levels TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS kept_levels TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS rule_violated BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pull_requests_author_created;
+
+ALTER TABLE assignment_decisions
+    DROP COLUMN IF EXISTS candidate_penalties;
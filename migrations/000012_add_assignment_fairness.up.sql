@@ -0,0 +1,4 @@
+ALTER TABLE assignment_decisions
+    ADD COLUMN IF NOT EXISTS candidate_penalties INT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created ON pull_requests(author_id, created_at);
@@ -0,0 +1,10 @@
+CREATE TABLE external_accounts (
+    provider VARCHAR(20) NOT NULL,
+    external_login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (provider, external_login)
+);
+
+CREATE INDEX idx_external_accounts_user_id ON external_accounts(user_id);
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    channels TEXT[] NOT NULL DEFAULT '{}',
+    email VARCHAR(255),
+    chat_webhook_url TEXT,
+    mode VARCHAR(20) NOT NULL DEFAULT 'immediate',
+    quiet_start SMALLINT,
+    quiet_end SMALLINT,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    digest_at SMALLINT NOT NULL DEFAULT 540,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES outbox_events(id) ON DELETE CASCADE,
+    event_type VARCHAR(64) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    deliver_after TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts INT NOT NULL DEFAULT 0,
+    error_message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+
+    UNIQUE (user_id, event_id)
+);
+
+CREATE INDEX idx_notifications_due
+ON notifications(deliver_after)
+WHERE status = 'pending';
@@ -0,0 +1,6 @@
+DELETE FROM notifications WHERE event_id IS NULL;
+ALTER TABLE notifications ALTER COLUMN event_id SET NOT NULL;
+
+ALTER TABLE notification_preferences
+    DROP COLUMN IF EXISTS last_digest_at,
+    DROP COLUMN IF EXISTS daily_digest;
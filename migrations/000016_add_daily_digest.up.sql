@@ -0,0 +1,5 @@
+ALTER TABLE notification_preferences
+    ADD COLUMN IF NOT EXISTS daily_digest BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS last_digest_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE notifications ALTER COLUMN event_id DROP NOT NULL;
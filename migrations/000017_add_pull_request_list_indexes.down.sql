@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_requests_merged_at_id;
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id ON pull_requests(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at_id ON pull_requests(merged_at, id) WHERE merged_at IS NOT NULL;
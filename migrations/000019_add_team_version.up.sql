@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN version INT NOT NULL DEFAULT 1;
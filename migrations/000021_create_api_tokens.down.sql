@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS api_token_audit;
+DROP TABLE IF EXISTS api_tokens;
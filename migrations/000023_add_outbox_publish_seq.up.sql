@@ -0,0 +1,23 @@
+-- Позиция события в журнале публикаций. Назначается релеем при отметке о
+-- публикации под транзакционной advisory-блокировкой, поэтому порядок
+-- publish_seq совпадает с порядком коммитов, в отличие от id.
+CREATE SEQUENCE IF NOT EXISTS outbox_events_publish_seq;
+
+ALTER TABLE outbox_events
+    ADD COLUMN IF NOT EXISTS publish_seq BIGINT;
+
+UPDATE outbox_events e
+SET publish_seq = s.seq
+FROM (
+    SELECT id, nextval('outbox_events_publish_seq') AS seq
+    FROM (
+        SELECT id FROM outbox_events
+        WHERE published_at IS NOT NULL AND publish_seq IS NULL
+        ORDER BY id
+    ) ordered
+) s
+WHERE e.id = s.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_events_publish_seq
+ON outbox_events(publish_seq)
+WHERE publish_seq IS NOT NULL;
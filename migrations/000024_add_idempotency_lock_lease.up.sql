@@ -0,0 +1,4 @@
+-- Срок, до которого незавершённый ключ считается занятым. Если процесс упал
+-- посреди запроса, ключ освобождается по истечении аренды, а не через TTL.
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
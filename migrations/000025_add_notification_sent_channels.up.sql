@@ -0,0 +1,4 @@
+-- Каналы, в которые уведомление уже доставлено: повторная попытка
+-- отправляет его только в оставшиеся.
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS sent_channels TEXT[] NOT NULL DEFAULT '{}';
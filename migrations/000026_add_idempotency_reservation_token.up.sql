@@ -0,0 +1,5 @@
+-- Токен резервирования: завершить или освободить ключ может только запрос,
+-- который его занял. Если аренда истекла и ключ занял повтор, ответ
+-- первого запроса не перезапишет чужую запись.
+ALTER TABLE idempotency_keys
+    ADD COLUMN IF NOT EXISTS token VARCHAR(64) NOT NULL DEFAULT '';
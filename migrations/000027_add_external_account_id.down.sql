@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_external_accounts_external_id;
+
+ALTER TABLE external_accounts
+    DROP COLUMN IF EXISTS external_id;
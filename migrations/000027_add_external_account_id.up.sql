@@ -0,0 +1,8 @@
+-- Числовой id пользователя во внешней системе. Merge Request Hook GitLab
+-- указывает автора MR только через object_attributes.author_id.
+ALTER TABLE external_accounts
+    ADD COLUMN IF NOT EXISTS external_id BIGINT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_external_accounts_external_id
+ON external_accounts(provider, external_id)
+WHERE external_id IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Ключи идемпотентности хранятся с префиксом клиента ("token:<id>:<key>").
+ALTER TABLE idempotency_keys ALTER COLUMN key TYPE VARCHAR(320);
@@ -0,0 +1,9 @@
+// Package migrations встраивает SQL-миграции в бинарник сервиса.
+package migrations
+
+import "embed"
+
+// FS содержит файлы NNNNNN_name.up.sql и NNNNNN_name.down.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -7,15 +7,43 @@ import (
 	"os"
 )
 
+type fieldsKey struct{}
+
+// ContextWith добавляет в контекст поля, которые WithContext допишет к
+// каждой записи, например task_id для всех логов обработки задачи.
+func ContextWith(ctx context.Context, args ...any) context.Context {
+	prev, _ := ctx.Value(fieldsKey{}).([]any)
+	fields := make([]any, 0, len(prev)+len(args))
+	fields = append(fields, prev...)
+	fields = append(fields, args...)
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// ContextExtractor достаёт из контекста пары ключ-значение для WithContext.
+// Так поля приложения (request_id, trace_id, principal) попадают в логи без
+// зависимости пакета от роутера, трейсинга и доменных типов.
+type ContextExtractor func(ctx context.Context) []any
+
+type Option func(*Logger)
+
+// WithContextExtractor регистрирует extractor; поля выводятся в порядке
+// регистрации.
+func WithContextExtractor(fn ContextExtractor) Option {
+	return func(l *Logger) {
+		l.extractors = append(l.extractors, fn)
+	}
+}
+
 type Logger struct {
 	*slog.Logger
+	extractors []ContextExtractor
 }
 
-func New(level, format string) *Logger {
-	return NewWithWriter(os.Stdout, level, format)
+func New(level, format string, opts ...Option) *Logger {
+	return NewWithWriter(os.Stdout, level, format, opts...)
 }
 
-func NewWithWriter(w io.Writer, level, format string) *Logger {
+func NewWithWriter(w io.Writer, level, format string, opts ...Option) *Logger {
 	var logLevel slog.Level
 	switch level {
 	case "debug":
@@ -30,27 +58,42 @@ func NewWithWriter(w io.Writer, level, format string) *Logger {
 		logLevel = slog.LevelInfo
 	}
 
-	opts := &slog.HandlerOptions{
+	handlerOpts := &slog.HandlerOptions{
 		Level:     logLevel,
 		AddSource: level == "debug",
 	}
 
 	var handler slog.Handler
 	if format == "json" {
-		handler = slog.NewJSONHandler(w, opts)
+		handler = slog.NewJSONHandler(w, handlerOpts)
 	} else {
-		handler = slog.NewTextHandler(w, opts)
+		handler = slog.NewTextHandler(w, handlerOpts)
 	}
 
-	return &Logger{
+	l := &Logger{
 		Logger: slog.New(handler),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
+// WithContext возвращает логгер с полями из контекста: полями
+// зарегистрированных extractor'ов и добавленными через ContextWith.
+// Отсутствующие поля пропускаются.
 func (l *Logger) WithContext(ctx context.Context) *Logger {
-	return &Logger{
-		Logger: l.Logger.With(),
+	args := make([]any, 0, 12)
+	for _, extract := range l.extractors {
+		args = append(args, extract(ctx)...)
+	}
+	if fields, ok := ctx.Value(fieldsKey{}).([]any); ok {
+		args = append(args, fields...)
+	}
+	if len(args) == 0 {
+		return l
 	}
+	return l.with(args...)
 }
 
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
@@ -58,20 +101,22 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	return &Logger{
-		Logger: l.Logger.With(args...),
-	}
+	return l.with(args...)
 }
 
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	return &Logger{
-		Logger: l.Logger.With(key, value),
-	}
+	return l.with(key, value)
 }
 
 func (l *Logger) WithError(err error) *Logger {
+	return l.with("error", err)
+}
+
+// with сохраняет extractor'ы у производного логгера.
+func (l *Logger) with(args ...any) *Logger {
 	return &Logger{
-		Logger: l.Logger.With("error", err),
+		Logger:     l.Logger.With(args...),
+		extractors: l.extractors,
 	}
 }
 
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+type requestIDKey struct{}
+
+func requestID(ctx context.Context) []any {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return []any{"request_id", id}
+	}
+	return nil
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+	}
+	return entry
+}
+
+func TestWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, "info", "json",
+		WithContextExtractor(requestID),
+		WithContextExtractor(func(context.Context) []any { return []any{"trace_id", "4bf92f35"} }),
+	)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "host/abc-000001")
+	ctx = ContextWith(ctx, "task_id", 42)
+	ctx = ContextWith(ctx, "task_kind", "fill")
+
+	// Производные логгеры сохраняют extractor'ы.
+	log.WithField("component", "worker").WithContext(ctx).Info("message", "extra", "value")
+
+	entry := decode(t, &buf)
+	want := map[string]any{
+		"request_id": "host/abc-000001",
+		"trace_id":   "4bf92f35",
+		"component":  "worker",
+		"task_id":    float64(42),
+		"task_kind":  "fill",
+		"extra":      "value",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+}
+
+func TestWithContextEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewWithWriter(&buf, "info", "json", WithContextExtractor(requestID))
+
+	if got := log.WithContext(context.Background()); got != log {
+		t.Error("WithContext without fields should return the same logger")
+	}
+
+	log.WithContext(ContextWith(context.Background(), "task_id", 1)).Info("message")
+
+	entry := decode(t, &buf)
+	if _, ok := entry["request_id"]; ok {
+		t.Error("unexpected field request_id")
+	}
+	if entry["task_id"] != float64(1) {
+		t.Errorf("task_id = %v", entry["task_id"])
+	}
+}
@@ -28,6 +28,18 @@ func HandleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrNoCandidate):
 		Conflict(w, "NO_CANDIDATE", "no available candidate for assignment")
 
+	case errors.Is(err, domain.ErrExternalIDTaken):
+		Conflict(w, "EXTERNAL_ID_TAKEN", "external account id is linked to another login")
+
+	case errors.Is(err, domain.ErrConcurrentModification):
+		Conflict(w, "CONCURRENT_MODIFICATION", "resource was modified concurrently, retry the request")
+
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		Conflict(w, "IDEMPOTENCY_IN_PROGRESS", "request with this idempotency key is in progress")
+
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		UnprocessableEntity(w, "IDEMPOTENCY_KEY_REUSED", "idempotency key was used with a different request")
+
 	case errors.Is(err, domain.ErrNotFound):
 		NotFound(w, "NOT_FOUND", "resource not found")
 
@@ -40,9 +52,24 @@ func HandleError(w http.ResponseWriter, err error) {
 	case errors.Is(err, domain.ErrAuthorNotFound):
 		NotFound(w, "NOT_FOUND", "author not found")
 
+	case errors.Is(err, domain.ErrAccountNotLinked):
+		NotFound(w, "NOT_FOUND", "external account is not linked to a user")
+
 	case errors.Is(err, domain.ErrInvalidInput):
 		BadRequest(w, "INVALID_INPUT", "invalid input data")
 
+	case errors.Is(err, domain.ErrUnauthorized):
+		Unauthorized(w, "UNAUTHORIZED", "unauthorized")
+
+	case errors.Is(err, domain.ErrInsufficientScope):
+		Forbidden(w, "INSUFFICIENT_SCOPE", "token does not have the required scope")
+
+	case errors.Is(err, domain.ErrForbidden):
+		Forbidden(w, "FORBIDDEN", "action is not allowed for this user")
+
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		PreconditionFailed(w, "PRECONDITION_FAILED", "resource version does not match If-Match")
+
 	default:
 		InternalError(w, "internal server error")
 	}
@@ -65,15 +92,40 @@ func MapDomainErrorToHTTP(err error) int {
 	case errors.Is(err, domain.ErrNoCandidate):
 		return http.StatusConflict
 
+	case errors.Is(err, domain.ErrExternalIDTaken):
+		return http.StatusConflict
+
+	case errors.Is(err, domain.ErrConcurrentModification):
+		return http.StatusConflict
+
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		return http.StatusConflict
+
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return http.StatusUnprocessableEntity
+
 	case errors.Is(err, domain.ErrNotFound),
 		errors.Is(err, domain.ErrTeamNotFound),
 		errors.Is(err, domain.ErrUserNotFound),
-		errors.Is(err, domain.ErrAuthorNotFound):
+		errors.Is(err, domain.ErrAuthorNotFound),
+		errors.Is(err, domain.ErrAccountNotLinked):
 		return http.StatusNotFound
 
 	case errors.Is(err, domain.ErrInvalidInput):
 		return http.StatusBadRequest
 
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+
+	case errors.Is(err, domain.ErrInsufficientScope):
+		return http.StatusForbidden
+
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+
 	default:
 		return http.StatusInternalServerError
 	}
@@ -96,15 +148,40 @@ func MapDomainErrorToCode(err error) string {
 	case errors.Is(err, domain.ErrNoCandidate):
 		return "NO_CANDIDATE"
 
+	case errors.Is(err, domain.ErrExternalIDTaken):
+		return "EXTERNAL_ID_TAKEN"
+
+	case errors.Is(err, domain.ErrConcurrentModification):
+		return "CONCURRENT_MODIFICATION"
+
+	case errors.Is(err, domain.ErrIdempotencyInProgress):
+		return "IDEMPOTENCY_IN_PROGRESS"
+
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		return "IDEMPOTENCY_KEY_REUSED"
+
 	case errors.Is(err, domain.ErrNotFound),
 		errors.Is(err, domain.ErrTeamNotFound),
 		errors.Is(err, domain.ErrUserNotFound),
-		errors.Is(err, domain.ErrAuthorNotFound):
+		errors.Is(err, domain.ErrAuthorNotFound),
+		errors.Is(err, domain.ErrAccountNotLinked):
 		return "NOT_FOUND"
 
 	case errors.Is(err, domain.ErrInvalidInput):
 		return "INVALID_INPUT"
 
+	case errors.Is(err, domain.ErrUnauthorized):
+		return "UNAUTHORIZED"
+
+	case errors.Is(err, domain.ErrInsufficientScope):
+		return "INSUFFICIENT_SCOPE"
+
+	case errors.Is(err, domain.ErrForbidden):
+		return "FORBIDDEN"
+
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		return "PRECONDITION_FAILED"
+
 	default:
 		return "INTERNAL_ERROR"
 	}
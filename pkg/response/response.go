@@ -33,6 +33,10 @@ func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func NotModified(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNotModified)
+}
+
 type ErrorResponse struct {
 	Error ErrorDetail `json:"error"`
 }
@@ -55,6 +59,14 @@ func BadRequest(w http.ResponseWriter, code, message string) {
 	Error(w, http.StatusBadRequest, code, message)
 }
 
+func Unauthorized(w http.ResponseWriter, code, message string) {
+	Error(w, http.StatusUnauthorized, code, message)
+}
+
+func Forbidden(w http.ResponseWriter, code, message string) {
+	Error(w, http.StatusForbidden, code, message)
+}
+
 func NotFound(w http.ResponseWriter, code, message string) {
 	Error(w, http.StatusNotFound, code, message)
 }
@@ -63,6 +75,18 @@ func Conflict(w http.ResponseWriter, code, message string) {
 	Error(w, http.StatusConflict, code, message)
 }
 
+func UnprocessableEntity(w http.ResponseWriter, code, message string) {
+	Error(w, http.StatusUnprocessableEntity, code, message)
+}
+
+func PreconditionFailed(w http.ResponseWriter, code, message string) {
+	Error(w, http.StatusPreconditionFailed, code, message)
+}
+
+func TooManyRequests(w http.ResponseWriter, code, message string) {
+	Error(w, http.StatusTooManyRequests, code, message)
+}
+
 func InternalError(w http.ResponseWriter, message string) {
 	Error(w, http.StatusInternalServerError, "INTERNAL_ERROR", message)
 }
@@ -26,6 +26,9 @@ export const options = {
 };
 
 const BASE_URL = __ENV.BASE_URL || 'http://localhost:8080';
+const AUTH = __ENV.API_TOKEN ? { Authorization: `Bearer ${__ENV.API_TOKEN}` } : {};
+const JSON_PARAMS = { headers: Object.assign({ 'Content-Type': 'application/json' }, AUTH) };
+const GET_PARAMS = { headers: AUTH };
 
 function generateID(prefix) {
   return `${prefix}_${Date.now()}_${Math.random().toString(36).substr(2, 9)}`;
@@ -50,7 +53,7 @@ function smokeTestLogic() {
   const createTeamRes = http.post(
     `${BASE_URL}/team/add`,
     createTeamPayload,
-    { headers: { 'Content-Type': 'application/json' } }
+    JSON_PARAMS
   );
 
   check(createTeamRes, {
@@ -90,7 +93,7 @@ export default function () {
   const createTeamRes = http.post(
     `${BASE_URL}/team/add`,
     createTeamPayload,
-    { headers: { 'Content-Type': 'application/json' } }
+    JSON_PARAMS
   );
 
   check(createTeamRes, {
@@ -99,7 +102,7 @@ export default function () {
 
   sleep(0.1);
 
-  const getTeamRes = http.get(`${BASE_URL}/team/get?team_name=${teamName}`);
+  const getTeamRes = http.get(`${BASE_URL}/team/get?team_name=${teamName}`, GET_PARAMS);
   check(getTeamRes, {
     'get team status is 200': (r) => r.status === 200,
   });
@@ -115,7 +118,7 @@ export default function () {
   const createPRRes = http.post(
     `${BASE_URL}/pullRequest/create`,
     createPRPayload,
-    { headers: { 'Content-Type': 'application/json' } }
+    JSON_PARAMS
   );
 
   let reviewerID = null;
@@ -138,7 +141,7 @@ export default function () {
   sleep(0.1);
 
   if (reviewerID) {
-    const getReviewRes = http.get(`${BASE_URL}/users/getReview?user_id=${reviewerID}`);
+    const getReviewRes = http.get(`${BASE_URL}/users/getReview?user_id=${reviewerID}`, GET_PARAMS);
     check(getReviewRes, {
       'get user reviews status is 200': (r) => r.status === 200,
     });
@@ -152,7 +155,7 @@ export default function () {
   const mergeRes = http.post(
     `${BASE_URL}/pullRequest/merge`,
     mergePayload,
-    { headers: { 'Content-Type': 'application/json' } }
+    JSON_PARAMS
   );
 
   check(mergeRes, {
@@ -164,7 +167,7 @@ export default function () {
   const mergeAgainRes = http.post(
     `${BASE_URL}/pullRequest/merge`,
     mergePayload,
-    { headers: { 'Content-Type': 'application/json' } }
+    JSON_PARAMS
   );
 
   check(mergeAgainRes, {